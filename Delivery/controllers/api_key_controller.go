@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	domain "Task-Management/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController interface {
+	CreateAPIKey(ctx *gin.Context)
+	RevokeAPIKey(ctx *gin.Context)
+}
+
+type APIKeyControllerImpl struct {
+	apiKeyUseCase domain.APIKeyUseCase
+}
+
+func NewAPIKeyController(apiKeyUseCase domain.APIKeyUseCase) *APIKeyControllerImpl {
+	return &APIKeyControllerImpl{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (c *APIKeyControllerImpl) CreateAPIKey(ctx *gin.Context) {
+	var req domain.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, rawKey, err := c.apiKeyUseCase.GenerateKey(ctx.Request.Context(), req.Name)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// The plain key is only ever returned here
+	respondCreated(ctx, "API key created successfully", gin.H{
+		"key":     rawKey,
+		"api_key": key,
+	})
+}
+
+func (c *APIKeyControllerImpl) RevokeAPIKey(ctx *gin.Context) {
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.apiKeyUseCase.RevokeKey(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			respondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "API key revoked successfully", nil)
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Task-Management/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockAPIKeyUseCase is a mock implementation of the APIKeyUseCase interface
+type MockAPIKeyUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyUseCase) GenerateKey(ctx context.Context, name string) (*Domain.APIKey, string, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*Domain.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyUseCase) RevokeKey(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyUseCase) ValidateKey(ctx context.Context, rawKey string) (*Domain.APIKey, error) {
+	args := m.Called(ctx, rawKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+// APIKeyControllerTestSuite groups all API key controller tests
+type APIKeyControllerTestSuite struct {
+	suite.Suite
+	mockAPIKeyUseCase *MockAPIKeyUseCase
+	router            *gin.Engine
+}
+
+// SetupSuite runs once before all tests
+func (suite *APIKeyControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *APIKeyControllerTestSuite) SetupTest() {
+	suite.mockAPIKeyUseCase = new(MockAPIKeyUseCase)
+	suite.router = gin.New()
+	controller := NewAPIKeyController(suite.mockAPIKeyUseCase)
+	suite.router.POST("/api-keys", controller.CreateAPIKey)
+	suite.router.DELETE("/api-keys/:id", controller.RevokeAPIKey)
+}
+
+// Test CreateAPIKey returns the plain key once
+func (suite *APIKeyControllerTestSuite) TestCreateAPIKey_Success() {
+	key := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "billing"}
+	suite.mockAPIKeyUseCase.On("GenerateKey", mock.Anything, "billing").Return(key, "tm_plain", nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api-keys", bytes.NewBufferString(`{"name": "billing"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	var body struct {
+		Data struct {
+			Key string `json:"key"`
+		} `json:"data"`
+	}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(suite.T(), "tm_plain", body.Data.Key)
+	assert.NotContains(suite.T(), resp.Body.String(), "key_hash")
+	suite.mockAPIKeyUseCase.AssertExpectations(suite.T())
+}
+
+// Test CreateAPIKey requires a name
+func (suite *APIKeyControllerTestSuite) TestCreateAPIKey_MissingName() {
+	req, _ := http.NewRequest(http.MethodPost, "/api-keys", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+// Test RevokeAPIKey Success
+func (suite *APIKeyControllerTestSuite) TestRevokeAPIKey_Success() {
+	id := primitive.NewObjectID()
+	suite.mockAPIKeyUseCase.On("RevokeKey", mock.Anything, id).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api-keys/"+id.Hex(), nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockAPIKeyUseCase.AssertExpectations(suite.T())
+}
+
+// Test RevokeAPIKey Not Found
+func (suite *APIKeyControllerTestSuite) TestRevokeAPIKey_NotFound() {
+	id := primitive.NewObjectID()
+	suite.mockAPIKeyUseCase.On("RevokeKey", mock.Anything, id).Return(Domain.ErrAPIKeyNotFound)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api-keys/"+id.Hex(), nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+}
+
+// Run the test suite
+func TestAPIKeyControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyControllerTestSuite))
+}
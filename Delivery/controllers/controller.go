@@ -1,7 +1,11 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	domain "Task-Management/Domain"
 
@@ -11,8 +15,22 @@ import (
 
 type UserController interface {
 	Register(ctx *gin.Context)
+	BulkRegister(ctx *gin.Context)
+	CreateInvite(ctx *gin.Context)
 	Login(ctx *gin.Context)
 	GetAllUsers(ctx *gin.Context)
+	SearchUsers(ctx *gin.Context)
+	GetUserByID(ctx *gin.Context)
+	GetMe(ctx *gin.Context)
+	UpdateProfile(ctx *gin.Context)
+	DeleteMe(ctx *gin.Context)
+	ExportMe(ctx *gin.Context)
+	RestoreUser(ctx *gin.Context)
+	ChangePassword(ctx *gin.Context)
+	RevokeSessions(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	Impersonate(ctx *gin.Context)
+	IssueConfirmation(ctx *gin.Context)
 }
 
 type UserControllerImpl struct {
@@ -22,10 +40,35 @@ type UserControllerImpl struct {
 type TaskController interface {
 	CreateTask(ctx *gin.Context)
 	GetTasksByUserID(ctx *gin.Context)
+	ReorderTasks(ctx *gin.Context)
+	CountTasks(ctx *gin.Context)
+	PreviewFilter(ctx *gin.Context)
+	GetUpcomingTasks(ctx *gin.Context)
+	GetAgenda(ctx *gin.Context)
+	SearchTasks(ctx *gin.Context)
+	GetTaskTags(ctx *gin.Context)
+	GetUserTaskStats(ctx *gin.Context)
+	GetTimeReport(ctx *gin.Context)
+	GetActivity(ctx *gin.Context)
+	GetCompleted(ctx *gin.Context)
 	GetTaskByID(ctx *gin.Context)
+	GetTaskHistory(ctx *gin.Context)
+	BatchGetTasks(ctx *gin.Context)
+	BulkTagTasks(ctx *gin.Context)
+	CloneTask(ctx *gin.Context)
+	TransferTask(ctx *gin.Context)
+	ShareTask(ctx *gin.Context)
+	UnshareTask(ctx *gin.Context)
+	ToggleChecklistItem(ctx *gin.Context)
 	UpdateTask(ctx *gin.Context)
 	DeleteTask(ctx *gin.Context)
+	ForceCompleteTask(ctx *gin.Context)
+	DeleteCompletedTasks(ctx *gin.Context)
 	GetAllTasks(ctx *gin.Context)
+	GetUnassignedTasks(ctx *gin.Context)
+	GetUsersWithOverdueTasks(ctx *gin.Context)
+	GetSharedTasks(ctx *gin.Context)
+	GetSharedWithMe(ctx *gin.Context)
 }
 
 type TaskControllerImpl struct {
@@ -48,200 +91,1447 @@ func NewTaskController(taskUseCase domain.TaskUseCase) *TaskControllerImpl {
 func (c *UserControllerImpl) Register(ctx *gin.Context) {
 	var req domain.RegisterRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+		respondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	user := &domain.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
-		Role:     req.Role,
+		Name:      req.Name,
+		Email:     req.Email,
+		Password:  req.Password,
+		Role:      req.Role,
+		AvatarURL: req.AvatarURL,
 	}
 
-	createdUser, err := c.userUseCase.Register(ctx.Request.Context(), user)
+	createdUser, err := c.userUseCase.Register(ctx.Request.Context(), user, req.InviteCode)
 	if err != nil {
 		if err.Error() == "user already exists" {
-			ctx.JSON(http.StatusConflict, domain.APIResponse{Message: "user already exists"})
+			respondError(ctx, http.StatusConflict, "user already exists")
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, domain.APIResponse{Message: "internal server error"})
+		if errors.Is(err, domain.ErrInviteRequired) || errors.Is(err, domain.ErrInvalidInvite) {
+			respondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	ctx.Header("Location", "/api/users/"+createdUser.ID.Hex())
+	respondCreated(ctx, "User registered successfully", createdUser)
+}
+
+// CreateInvite issues a registration invite code on behalf of the calling
+// admin
+func (c *UserControllerImpl) CreateInvite(ctx *gin.Context) {
+	adminID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	invite, err := c.userUseCase.CreateInvite(ctx.Request.Context(), adminID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondCreated(ctx, "Invite created successfully", invite)
+}
+
+// Impersonate issues the calling admin a short-lived token acting as the user
+// in the path. The token records the admin and the action is audited.
+func (c *UserControllerImpl) Impersonate(ctx *gin.Context) {
+	adminID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	token, err := c.userUseCase.Impersonate(ctx.Request.Context(), adminID, id)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Impersonation token issued", gin.H{"token": token})
+}
+
+// IssueConfirmation hands the caller a short-lived token to send in the
+// X-Confirmation-Token header of a destructive admin request
+func (c *UserControllerImpl) IssueConfirmation(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	token, err := c.userUseCase.IssueConfirmation(ctx.Request.Context(), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondCreated(ctx, "Confirmation token issued", gin.H{"token": token})
+}
+
+// BulkRegister creates several users at once and reports the outcome of each
+// record. It answers 201 when all were created, 207 when only some were and
+// 422 when none were.
+func (c *UserControllerImpl) BulkRegister(ctx *gin.Context) {
+	var reqs []domain.RegisterRequest
+	if err := ctx.ShouldBindJSON(&reqs); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(reqs) == 0 {
+		respondError(ctx, http.StatusBadRequest, "at least one user is required")
+		return
+	}
+
+	users := make([]*domain.User, len(reqs))
+	for i, req := range reqs {
+		users[i] = &domain.User{
+			Name:      req.Name,
+			Email:     req.Email,
+			Password:  req.Password,
+			Role:      req.Role,
+			AvatarURL: req.AvatarURL,
+		}
+	}
+
+	results, err := c.userUseCase.BulkRegister(ctx.Request.Context(), users)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == domain.BulkStatusFailed {
+			failed++
+		}
+	}
+	respondMultiStatus(ctx, http.StatusCreated, "Bulk user creation processed", results, failed, len(results))
+}
+
+func (c *UserControllerImpl) Login(ctx *gin.Context) {
+	var req domain.LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, token, err := c.userUseCase.Login(ctx.Request.Context(), req.Email, req.Password, req.RememberMe)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	respondOK(ctx, "Login successful", gin.H{
+		"token":                token,
+		"user":                 user,
+		"must_change_password": user.MustChangePassword,
+	})
+}
+
+func (c *UserControllerImpl) GetAllUsers(ctx *gin.Context) {
+	createdFrom, createdFromDay, err := parseDateParam(ctx.Query("created_from"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, "invalid created_from: "+err.Error())
+		return
+	}
+	createdTo, createdToDay, err := parseDateParam(ctx.Query("created_to"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, "invalid created_to: "+err.Error())
+		return
+	}
+
+	includeDeleted := false
+	if value := ctx.Query("include_deleted"); value != "" {
+		if includeDeleted, err = strconv.ParseBool(value); err != nil {
+			respondError(ctx, http.StatusBadRequest, "invalid include_deleted: must be true or false")
+			return
+		}
+	}
+	if includeDeleted && ctx.GetString("role") != domain.RoleAdmin {
+		respondError(ctx, http.StatusForbidden, "only admins may list deleted users")
+		return
+	}
+
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	filter := domain.UserFilter{
+		Role:           ctx.Query("role"),
+		CreatedFrom:    createdFrom,
+		CreatedTo:      createdTo,
+		CreatedFromDay: createdFromDay,
+		CreatedToDay:   createdToDay,
+		IncludeDeleted: includeDeleted,
+		Pagination:     page,
+	}
+
+	users, err := c.userUseCase.GetAllUsers(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDateRange) {
+			respondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setPageLinks(ctx, page, len(users))
+	respondOK(ctx, "Users retrieved successfully", users)
+}
+
+// SearchUsers finds users whose name or email contains the q parameter
+func (c *UserControllerImpl) SearchUsers(ctx *gin.Context) {
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := c.userUseCase.SearchUsers(ctx.Request.Context(), ctx.Query("q"), page)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmptySearchQuery) {
+			respondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setPageLinks(ctx, page, len(users))
+	respondOK(ctx, "Users retrieved successfully", users)
+}
+
+// GetUserByID returns user id. Admins may look up anyone; other users only
+// their own account, so emails and roles are not exposed to everyone.
+func (c *UserControllerImpl) GetUserByID(ctx *gin.Context) {
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+	if ctx.GetString("role") != domain.RoleAdmin && ctx.GetString("user_id") != id.Hex() {
+		respondError(ctx, http.StatusForbidden, "only admins may view other users")
+		return
+	}
+
+	user, err := c.userUseCase.GetUserByID(ctx.Request.Context(), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if user == nil {
+		respondError(ctx, http.StatusNotFound, domain.ErrUserNotFound.Error())
+		return
+	}
+
+	respondOK(ctx, "User retrieved successfully", user)
+}
+
+// currentUser returns the authenticated user, preferring the record the
+// current user middleware stored in the context and falling back to a
+// lookup by the token's user ID. On failure it writes the error response and
+// returns false.
+func (c *UserControllerImpl) currentUser(ctx *gin.Context) (*domain.User, bool) {
+	if user, ok := ctx.Value("current_user").(*domain.User); ok {
+		return user, true
+	}
+
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	user, err := c.userUseCase.GetUserByID(ctx.Request.Context(), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+	if user == nil {
+		respondError(ctx, http.StatusNotFound, domain.ErrUserNotFound.Error())
+		return nil, false
+	}
+	return user, true
+}
+
+// GetMe returns the authenticated user's profile
+func (c *UserControllerImpl) GetMe(ctx *gin.Context) {
+	user, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+	respondOK(ctx, "User retrieved successfully", user)
+}
+
+func (c *UserControllerImpl) UpdateProfile(ctx *gin.Context) {
+	var req domain.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	// Leave the stored hash untouched; UpdateUser only rehashes a new password
+	user.Password = ""
+
+	if err := c.userUseCase.UpdateUser(ctx.Request.Context(), user); err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Profile updated successfully", user)
+}
+
+// RevokeSessions logs the caller out everywhere by invalidating all of their
+// tokens, including the one used for this request
+func (c *UserControllerImpl) RevokeSessions(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.userUseCase.RevokeSessions(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			respondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Sessions revoked successfully", nil)
+}
+
+func (c *UserControllerImpl) ChangePassword(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.userUseCase.ChangePassword(ctx.Request.Context(), id, req.CurrentPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrIncorrectPassword):
+			// The caller is authenticated; a wrong current password must not
+			// read as an expired session
+			respondError(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, domain.ErrPasswordReused):
+			respondError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrUserNotFound):
+			respondError(ctx, http.StatusNotFound, err.Error())
+		default:
+			respondError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondOK(ctx, "Password changed successfully", nil)
+}
+
+func (c *UserControllerImpl) ResetPassword(ctx *gin.Context) {
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.userUseCase.ResetPassword(ctx.Request.Context(), id, req.NewPassword); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			respondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrPasswordReused) {
+			respondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Password reset successfully", nil)
+}
+
+// DeleteMe deletes the caller's account. It stays restorable by an admin
+// during the deletion grace period.
+func (c *UserControllerImpl) DeleteMe(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.userUseCase.DeleteUser(ctx.Request.Context(), id); err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Account deleted successfully", nil)
+}
+
+// ExportMe streams the caller's profile and tasks as one JSON document,
+// served as a file download
+func (c *UserControllerImpl) ExportMe(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	stream := &exportStream{ctx: ctx, style: jsonStyleOf(ctx)}
+	err := c.userUseCase.ExportUserData(ctx.Request.Context(), id, stream)
+	if err == nil {
+		err = stream.close()
+	}
+	if err != nil {
+		if stream.started {
+			// The status line is already sent; cut the document short so
+			// the client sees invalid JSON rather than a partial export
+			ctx.Abort()
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			respondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// exportStream writes a data export to the response as it is produced:
+// the profile opens the document and each task is appended to its tasks
+// array, so nothing is collected in memory first. Every part is flushed to
+// the client as soon as it is written, in the request's jsonStyle.
+type exportStream struct {
+	ctx     *gin.Context
+	style   jsonStyle
+	started bool
+	tasks   int
+}
+
+func (s *exportStream) WriteProfile(user *domain.User) error {
+	exportedAt, err := s.style.marshal(time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return err
+	}
+	profile, err := s.style.marshal(user)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.Header("Content-Type", "application/json; charset=utf-8")
+	s.ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s.json"`, user.ID.Hex()))
+	s.ctx.Status(http.StatusOK)
+	s.started = true
+	_, err = fmt.Fprintf(s.ctx.Writer, `{%q:%s,"profile":%s,"tasks":[`, s.style.name("exported_at"), exportedAt, profile)
+	s.ctx.Writer.Flush()
+	return err
+}
+
+func (s *exportStream) WriteTask(task *domain.Task) error {
+	data, err := s.style.marshal(task)
+	if err != nil {
+		return err
+	}
+	if s.tasks > 0 {
+		if _, err := s.ctx.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	s.tasks++
+	_, err = s.ctx.Writer.Write(data)
+	s.ctx.Writer.Flush()
+	return err
+}
+
+// close ends the document with the number of tasks written
+func (s *exportStream) close() error {
+	_, err := fmt.Fprintf(s.ctx.Writer, `],%q:%d}`, s.style.name("task_count"), s.tasks)
+	return err
+}
+
+// RestoreUser brings back a deleted account whose grace period is not over
+func (c *UserControllerImpl) RestoreUser(ctx *gin.Context) {
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.userUseCase.RestoreUser(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			respondError(ctx, http.StatusNotFound, "no deleted user to restore")
+			return
+		}
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			respondError(ctx, http.StatusConflict, "another account now uses this email")
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "User restored successfully", nil)
+}
+
+// Task Controllers
+func (c *TaskControllerImpl) CreateTask(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var task domain.Task
+	if err := bindStrictJSON(ctx, &task); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	task.UserID = id
+
+	createdTask, err := c.taskUseCase.CreateTask(ctx.Request.Context(), &task)
+	if errors.Is(err, domain.ErrTaskQuotaExceeded) {
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	var blocked *domain.TaskBlockedError
+	if errors.As(err, &blocked) {
+		respond(ctx, http.StatusConflict, domain.APIResponse{
+			Message: err.Error(),
+			Data:    gin.H{"blocking_task_ids": blocked.BlockingTaskIDs},
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrTaskCreateThrottled) {
+		respondError(ctx, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrDuplicateTaskTitle) {
+		respondError(ctx, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		// Fix: Return 400 for use case errors
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.Header("Location", "/api/tasks/"+createdTask.ID.Hex())
+	respondCreated(ctx, "Task created successfully", createdTask)
+}
+
+func (c *TaskControllerImpl) GetTaskByID(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	task, err := c.taskUseCase.GetTaskByID(ctx.Request.Context(), id, callerID, asAdmin)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(ctx, http.StatusNotFound, err.Error())
+		} else {
+			respondError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondOK(ctx, "Task retrieved successfully", task)
+}
+
+// GetTaskHistory lists the revisions of a task, oldest first. Only its owner
+// and admins may see them.
+func (c *TaskControllerImpl) GetTaskHistory(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	revisions, err := c.taskUseCase.GetTaskHistory(ctx.Request.Context(), id, callerID, asAdmin, page)
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, domain.ErrNotTaskOwner):
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	setPageLinks(ctx, page, len(revisions))
+	respondOK(ctx, "Task history retrieved successfully", revisions)
+}
+
+// BatchGetTasks returns several of the caller's tasks at once, listing the
+// requested IDs that do not exist or belong to someone else separately
+func (c *TaskControllerImpl) BatchGetTasks(ctx *gin.Context) {
+	ownerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.BatchGetTasksRequest
+	if err := bindStrictJSON(ctx, &req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ids, ok := parseObjectIDList(ctx, req.IDs)
+	if !ok {
+		return
+	}
+
+	result, err := c.taskUseCase.GetTasksByIDs(ctx.Request.Context(), ownerID, ids)
+	if errors.Is(err, domain.ErrBatchTooLarge) {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Tasks retrieved successfully", result)
+}
+
+// BulkTagTasks adds and removes tags across several of the caller's tasks,
+// reporting how many were updated and which IDs were skipped
+func (c *TaskControllerImpl) BulkTagTasks(ctx *gin.Context) {
+	ownerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.BulkTagRequest
+	if err := bindStrictJSON(ctx, &req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ids, ok := parseObjectIDList(ctx, req.IDs)
+	if !ok {
+		return
+	}
+
+	result, err := c.taskUseCase.BulkTagTasks(ctx.Request.Context(), ownerID, ids, req.Add, req.Remove)
+	if errors.Is(err, domain.ErrNoTagChanges) || errors.Is(err, domain.ErrBatchTooLarge) ||
+		errors.Is(err, domain.ErrTooManyTags) || errors.Is(err, domain.ErrTagTooLong) {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Tasks tagged successfully", result)
+}
+
+// ReorderTasks stores the order of the caller's tasks given by the ids in the
+// request body, reporting IDs that were skipped
+func (c *TaskControllerImpl) ReorderTasks(ctx *gin.Context) {
+	ownerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.ReorderTasksRequest
+	if err := bindStrictJSON(ctx, &req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ids, ok := parseObjectIDList(ctx, req.IDs)
+	if !ok {
+		return
+	}
+
+	result, err := c.taskUseCase.ReorderTasks(ctx.Request.Context(), ownerID, ids)
+	if errors.Is(err, domain.ErrBatchTooLarge) {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Tasks reordered successfully", result)
+}
+
+func (c *TaskControllerImpl) GetTasksByUserID(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	filter, ok := parseTaskFilter(ctx)
+	if !ok {
+		return
+	}
+	if filter.Pagination, ok = parsePagination(ctx); !ok {
+		return
+	}
+
+	tasks, err := c.taskUseCase.GetTasksByUserID(ctx.Request.Context(), id, filter)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	total, err := c.taskUseCase.CountTasksByUserID(ctx.Request.Context(), id, filter)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	setPaginationLinks(ctx, filter.Pagination, total)
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
+}
+
+// CountTasks returns how many of the caller's tasks match the list filters,
+// for badge counters that do not need the tasks themselves
+func (c *TaskControllerImpl) CountTasks(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	filter, ok := parseTaskFilter(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := c.taskUseCase.CountTasksByUserID(ctx.Request.Context(), id, filter)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Tasks counted successfully", gin.H{"count": count})
+}
+
+// PreviewFilter reports how many of the caller's tasks the filter in the
+// request body matches, with a small sample of them
+func (c *TaskControllerImpl) PreviewFilter(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.PreviewFilterRequest
+	if err := bindStrictJSON(ctx, &req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter, err := taskFilterFromRequest(req)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preview, err := c.taskUseCase.PreviewTaskFilter(ctx.Request.Context(), id, filter)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Filter previewed successfully", preview)
+}
+
+// defaultUpcomingLimit and maxUpcomingLimit bound the limit query value of
+// GetUpcomingTasks
+const (
+	defaultUpcomingLimit = 5
+	maxUpcomingLimit     = 100
+)
+
+// GetUpcomingTasks returns the caller's next unfinished tasks by due date,
+// or with reminders=true those whose reminder time has arrived
+func (c *TaskControllerImpl) GetUpcomingTasks(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	limit := defaultUpcomingLimit
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxUpcomingLimit {
+			respondError(ctx, http.StatusBadRequest, "invalid limit: must be between 1 and 100")
+			return
+		}
+	}
+
+	reminders := false
+	if value := ctx.Query("reminders"); value != "" {
+		var err error
+		reminders, err = strconv.ParseBool(value)
+		if err != nil {
+			respondError(ctx, http.StatusBadRequest, "invalid reminders: must be true or false")
+			return
+		}
+	}
+
+	var tasks []*domain.Task
+	var err error
+	if reminders {
+		tasks, err = c.taskUseCase.GetDueReminders(ctx.Request.Context(), id, limit)
+	} else {
+		tasks, err = c.taskUseCase.GetUpcomingTasks(ctx.Request.Context(), id, limit)
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, domain.APIResponse{
-		Message: "User registered successfully",
-		Data:    createdUser,
-	})
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
 }
 
-func (c *UserControllerImpl) Login(ctx *gin.Context) {
-	var req domain.LoginRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+// SearchTasks returns the caller's tasks matching the q parameter, most
+// relevant first
+func (c *TaskControllerImpl) SearchTasks(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePagination(ctx)
+	if !ok {
 		return
 	}
 
-	user, token, err := c.userUseCase.Login(ctx.Request.Context(), req.Email, req.Password)
+	tasks, err := c.taskUseCase.SearchTasks(ctx.Request.Context(), id, ctx.Query("q"), page)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, domain.APIResponse{Message: err.Error()})
+		if errors.Is(err, domain.ErrEmptySearchQuery) {
+			respondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Login successful",
-		Data: gin.H{
-			"token": token,
-			"user":  user,
-		},
-	})
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
 }
 
-func (c *UserControllerImpl) GetAllUsers(ctx *gin.Context) {
-	users, err := c.userUseCase.GetAllUsers(ctx.Request.Context())
+func (c *TaskControllerImpl) GetTaskTags(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tags, err := c.taskUseCase.GetTagsByUserID(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.APIResponse{Message: err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Users retrieved successfully",
-		Data:    users,
-	})
+	respondOK(ctx, "Tags retrieved successfully", tags)
 }
 
-// Task Controllers
-func (c *TaskControllerImpl) CreateTask(ctx *gin.Context) {
-	userID, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, domain.APIResponse{Message: "unauthorized"})
+// GetUserTaskStats returns the task breakdown of the user in the path
+func (c *TaskControllerImpl) GetUserTaskStats(ctx *gin.Context) {
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
 		return
 	}
 
-	var task domain.Task
-	if err := ctx.ShouldBindJSON(&task); err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+	stats, err := c.taskUseCase.GetTaskStatsByUserID(ctx.Request.Context(), id)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	id, err := primitive.ObjectIDFromHex(userID.(string))
+	respondOK(ctx, "Task statistics retrieved successfully", stats)
+}
+
+// GetAgenda returns the caller's unfinished tasks grouped into overdue,
+// today, this week and later
+func (c *TaskControllerImpl) GetAgenda(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	agenda, err := c.taskUseCase.GetAgenda(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: "Invalid user ID"})
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	task.UserID = id
 
-	createdTask, err := c.taskUseCase.CreateTask(ctx.Request.Context(), &task)
+	respondOK(ctx, "Agenda retrieved successfully", agenda)
+}
+
+// GetTimeReport compares the estimated and actual minutes of the caller's
+// tasks, per status
+func (c *TaskControllerImpl) GetTimeReport(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.taskUseCase.GetTimeReport(ctx.Request.Context(), id)
 	if err != nil {
-		// Fix: Return 400 for use case errors
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, domain.APIResponse{
-		Message: "Task created successfully",
-		Data:    createdTask,
-	})
+	respondOK(ctx, "Time report retrieved successfully", report)
 }
 
-func (c *TaskControllerImpl) GetTaskByID(ctx *gin.Context) {
-	id, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+// GetActivity returns the caller's activity timeline, newest first
+func (c *TaskControllerImpl) GetActivity(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	events, err := c.taskUseCase.GetActivityByUserID(ctx.Request.Context(), id, page)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: "Invalid task ID"})
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	setPageLinks(ctx, page, len(events))
+	respondOK(ctx, "Activity retrieved successfully", events)
+}
+
+// GetCompleted returns the caller's tasks completed between the optional
+// from and to query values, each an RFC 3339 timestamp or a YYYY-MM-DD date
+func (c *TaskControllerImpl) GetCompleted(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
 		return
 	}
 
-	task, err := c.taskUseCase.GetTaskByID(ctx.Request.Context(), id)
+	from, fromDay, err := parseDateParam(ctx.Query("from"))
 	if err != nil {
-		if err.Error() == "task not found" {
-			ctx.JSON(http.StatusNotFound, domain.APIResponse{Message: err.Error()})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, domain.APIResponse{Message: "internal server error"})
+		respondError(ctx, http.StatusBadRequest, "invalid from: "+err.Error())
+		return
+	}
+	to, toDay, err := parseDateParam(ctx.Query("to"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, "invalid to: "+err.Error())
+		return
+	}
+
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	filter := domain.CompletedFilter{From: from, To: to, FromDay: fromDay, ToDay: toDay, Pagination: page}
+	tasks, err := c.taskUseCase.GetCompletedTasks(ctx.Request.Context(), id, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDateRange) {
+			respondError(ctx, http.StatusBadRequest, err.Error())
+			return
 		}
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Task retrieved successfully",
-		Data:    task,
-	})
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Completed tasks retrieved successfully", tasks)
 }
 
-func (c *TaskControllerImpl) GetTasksByUserID(ctx *gin.Context) {
-	userID, exists := ctx.Get("user_id")
-	if !exists || userID == nil {
-		ctx.JSON(http.StatusUnauthorized, domain.APIResponse{Message: "unauthorized"})
+func (c *TaskControllerImpl) GetAllTasks(ctx *gin.Context) {
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	switch ctx.Query("expand") {
+	case "":
+	case "user":
+		c.getAllTasksWithOwners(ctx, page)
+		return
+	default:
+		respondError(ctx, http.StatusBadRequest, "invalid expand: only \"user\" is supported")
 		return
 	}
 
-	id, err := primitive.ObjectIDFromHex(userID.(string))
+	tasks, err := c.taskUseCase.GetAllTasks(ctx.Request.Context(), page)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: "Invalid user ID"})
+		respondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
+}
+
+// GetUnassignedTasks lists the tasks nobody is assigned to, for admins
+// triaging the queue
+func (c *TaskControllerImpl) GetUnassignedTasks(ctx *gin.Context) {
+	page, ok := parsePagination(ctx)
+	if !ok {
 		return
 	}
 
-	tasks, err := c.taskUseCase.GetTasksByUserID(ctx.Request.Context(), id)
+	tasks, err := c.taskUseCase.GetUnassignedTasks(ctx.Request.Context(), page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.APIResponse{Message: err.Error()})
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Tasks retrieved successfully",
-		Data:    tasks,
-	})
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
 }
 
-func (c *TaskControllerImpl) GetAllTasks(ctx *gin.Context) {
-	tasks, err := c.taskUseCase.GetAllTasks(ctx.Request.Context())
+// GetUsersWithOverdueTasks lists the users with overdue tasks and how many
+// each has, for admins following up
+func (c *TaskControllerImpl) GetUsersWithOverdueTasks(ctx *gin.Context) {
+	users, err := c.taskUseCase.GetUsersWithOverdueTasks(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.APIResponse{Message: err.Error()})
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Tasks retrieved successfully",
-		Data:    tasks,
-	})
+	respondOK(ctx, "Users retrieved successfully", users)
 }
 
-func (c *TaskControllerImpl) UpdateTask(ctx *gin.Context) {
-	var task domain.Task
-	if err := ctx.ShouldBindJSON(&task); err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+// GetSharedTasks lists every user's tasks when shared mode is on. Without it
+// the caller may only list their own tasks, so the request is forbidden.
+func (c *TaskControllerImpl) GetSharedTasks(ctx *gin.Context) {
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.taskUseCase.GetSharedTasks(ctx.Request.Context(), page)
+	if errors.Is(err, domain.ErrSharedTasksDisabled) {
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
+}
+
+// GetSharedWithMe lists the tasks other users have shared with the caller
+func (c *TaskControllerImpl) GetSharedWithMe(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.taskUseCase.GetTasksSharedWithMe(ctx.Request.Context(), id, page)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
+}
+
+func (c *TaskControllerImpl) getAllTasksWithOwners(ctx *gin.Context, page domain.Pagination) {
+	tasks, err := c.taskUseCase.GetAllTasksWithOwners(ctx.Request.Context(), page)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setPageLinks(ctx, page, len(tasks))
+	respondOK(ctx, "Tasks retrieved successfully", tasks)
+}
+
+// CloneTask copies one of the caller's tasks into a new task. The optional
+// body overrides fields of the copy.
+func (c *TaskControllerImpl) CloneTask(ctx *gin.Context) {
+	ownerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var overrides domain.CloneTaskRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := bindStrictJSON(ctx, &overrides); err != nil {
+			respondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	clone, err := c.taskUseCase.CloneTask(ctx.Request.Context(), id, ownerID, overrides)
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, domain.ErrTaskQuotaExceeded):
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, domain.ErrTaskCreateThrottled):
+		respondError(ctx, http.StatusTooManyRequests, err.Error())
+		return
+	case errors.Is(err, domain.ErrDuplicateTaskTitle):
+		respondError(ctx, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.Header("Location", "/api/tasks/"+clone.ID.Hex())
+	respondCreated(ctx, "Task cloned successfully", clone)
+}
+
+// TransferTask hands one of the caller's tasks to another user. Admins may
+// transfer any task.
+func (c *TaskControllerImpl) TransferTask(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.TransferTaskRequest
+	if err := bindStrictJSON(ctx, &req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	toUserID, err := primitive.ObjectIDFromHex(req.ToUserID)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, "invalid to_user_id: must be a valid ObjectID")
+		return
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	task, err := c.taskUseCase.TransferTask(ctx.Request.Context(), id, callerID, asAdmin, toUserID)
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, domain.ErrNotTaskOwner), errors.Is(err, domain.ErrTaskQuotaExceeded):
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, domain.ErrUserNotFound):
+		respondError(ctx, http.StatusBadRequest, "target user not found")
+		return
+	case errors.Is(err, domain.ErrDuplicateTaskTitle):
+		respondError(ctx, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Task transferred successfully", task)
+}
+
+// ShareTask lets another user read one of the caller's tasks, or also change
+// it when permission is "write". Admins may share any task.
+func (c *TaskControllerImpl) ShareTask(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.ShareTaskRequest
+	if err := bindStrictJSON(ctx, &req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, "invalid user_id: must be a valid ObjectID")
+		return
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	task, err := c.taskUseCase.ShareTask(ctx.Request.Context(), id, callerID, asAdmin, userID, req.Permission)
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, domain.ErrNotTaskOwner):
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, domain.ErrUserNotFound):
+		respondError(ctx, http.StatusBadRequest, "target user not found")
+		return
+	case errors.Is(err, domain.ErrInvalidSharePermission), errors.Is(err, domain.ErrShareWithOwner), errors.Is(err, domain.ErrInvalidID):
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Task shared successfully", task)
+}
+
+// UnshareTask takes away another user's access to one of the caller's tasks
+func (c *TaskControllerImpl) UnshareTask(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseObjectID(ctx, "userID")
+	if !ok {
+		return
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	task, err := c.taskUseCase.UnshareTask(ctx.Request.Context(), id, callerID, asAdmin, userID)
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, domain.ErrNotTaskOwner):
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Task unshared successfully", task)
+}
+
+// ToggleChecklistItem flips the done state of one checklist item, addressed
+// by its zero-based position
+func (c *TaskControllerImpl) ToggleChecklistItem(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
 		return
 	}
 
-	id, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+	index, err := strconv.Atoi(ctx.Param("index"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: "Invalid task ID"})
+		respondError(ctx, http.StatusBadRequest, "invalid index: must be an integer")
+		return
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	task, err := c.taskUseCase.ToggleChecklistItem(ctx.Request.Context(), id, callerID, asAdmin, index)
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, domain.ErrNotTaskOwner):
+		respondError(ctx, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, domain.ErrChecklistIndexOutOfRange):
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Checklist item updated successfully", task)
+}
+
+func (c *TaskControllerImpl) UpdateTask(ctx *gin.Context) {
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var task domain.Task
+	if err := bindStrictJSON(ctx, &task); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	task.ID = id
-	if err := c.taskUseCase.UpdateTask(ctx.Request.Context(), &task); err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	if err := c.taskUseCase.UpdateTask(ctx.Request.Context(), &task, callerID, asAdmin); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTaskNotFound):
+			respondError(ctx, http.StatusNotFound, err.Error())
+			return
+		case errors.Is(err, domain.ErrNotTaskOwner):
+			respondError(ctx, http.StatusForbidden, err.Error())
+			return
+		case errors.Is(err, domain.ErrStatusRegression), errors.Is(err, domain.ErrDuplicateTaskTitle):
+			respondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		var blocked *domain.TaskBlockedError
+		if errors.As(err, &blocked) {
+			respond(ctx, http.StatusConflict, domain.APIResponse{
+				Message: err.Error(),
+				Data:    gin.H{"blocking_task_ids": blocked.BlockingTaskIDs},
+			})
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Task updated successfully",
-	})
+	respondOK(ctx, "Task updated successfully", nil)
 }
 
 func (c *TaskControllerImpl) DeleteTask(ctx *gin.Context) {
-	id, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	callerID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	force := false
+	if value := ctx.Query("force"); value != "" {
+		var err error
+		if force, err = strconv.ParseBool(value); err != nil {
+			respondError(ctx, http.StatusBadRequest, "invalid force: must be true or false")
+			return
+		}
+	}
+
+	asAdmin := ctx.GetString("role") == domain.RoleAdmin
+	if err := c.taskUseCase.DeleteTask(ctx.Request.Context(), id, callerID, asAdmin, force); err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrNotTaskOwner) {
+			respondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrTaskInProgress) {
+			respondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondOK(ctx, "Task deleted successfully", nil)
+}
+
+// ForceCompleteTask lets an admin complete any task, skipping the ownership
+// and status transition checks
+func (c *TaskControllerImpl) ForceCompleteTask(ctx *gin.Context) {
+	adminID, ok := authenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := parseObjectID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	task, err := c.taskUseCase.ForceCompleteTask(ctx.Request.Context(), id, adminID)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		respondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: "Invalid task ID"})
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Task completed successfully", task)
+}
+
+// DeleteCompletedTasks clears the caller's completed tasks. The request must
+// carry confirm=true so the bulk delete is never triggered by accident.
+func (c *TaskControllerImpl) DeleteCompletedTasks(ctx *gin.Context) {
+	id, ok := authenticatedUserID(ctx)
+	if !ok {
 		return
 	}
 
-	if err := c.taskUseCase.DeleteTask(ctx.Request.Context(), id); err != nil {
-		ctx.JSON(http.StatusBadRequest, domain.APIResponse{Message: err.Error()})
+	if confirm, _ := strconv.ParseBool(ctx.Query("confirm")); !confirm {
+		respondError(ctx, http.StatusBadRequest, "confirm=true is required to delete all completed tasks")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.APIResponse{
-		Message: "Task deleted successfully",
-	})
+	deleted, err := c.taskUseCase.DeleteCompletedTasks(ctx.Request.Context(), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondOK(ctx, "Completed tasks deleted successfully", gin.H{"deleted": deleted})
 }
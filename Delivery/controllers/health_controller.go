@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long a readiness probe waits on dependencies
+const readinessTimeout = 2 * time.Second
+
+// Build metadata, set at build time with
+//
+//	go build -ldflags "-X Task-Management/Delivery/controllers.Version=1.4.0 \
+//		-X Task-Management/Delivery/controllers.GitCommit=$(git rev-parse HEAD) \
+//		-X Task-Management/Delivery/controllers.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// ReadinessCheck reports whether a dependency can serve traffic
+type ReadinessCheck func(ctx context.Context) error
+
+type HealthController interface {
+	Liveness(ctx *gin.Context)
+	Readiness(ctx *gin.Context)
+	Version(ctx *gin.Context)
+}
+
+type HealthControllerImpl struct {
+	checks []ReadinessCheck
+}
+
+func NewHealthController(checks ...ReadinessCheck) *HealthControllerImpl {
+	return &HealthControllerImpl{
+		checks: checks,
+	}
+}
+
+// Liveness reports that the process is up. It never touches the database so
+// an outage does not get healthy instances restarted.
+func (c *HealthControllerImpl) Liveness(ctx *gin.Context) {
+	respondOK(ctx, "ok", nil)
+}
+
+// Readiness reports whether every dependency is reachable. The probe is
+// public, so why a dependency failed is logged rather than sent back.
+func (c *HealthControllerImpl) Readiness(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	for _, check := range c.checks {
+		if err := check(checkCtx); err != nil {
+			log.Println("Readiness check failed:", err)
+			respondError(ctx, http.StatusServiceUnavailable, "not ready")
+			return
+		}
+	}
+
+	respondOK(ctx, "ready", nil)
+}
+
+// Version reports the build metadata of the running binary
+func (c *HealthControllerImpl) Version(ctx *gin.Context) {
+	respondOK(ctx, "success", gin.H{
+		"version":    Version,
+		"git_commit": GitCommit,
+		"build_time": BuildTime,
+	})
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// HealthControllerTestSuite groups the liveness and readiness probe tests
+type HealthControllerTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// SetupSuite runs once before all tests
+func (suite *HealthControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *HealthControllerTestSuite) SetupTest() {
+	suite.router = gin.New()
+}
+
+func (suite *HealthControllerTestSuite) serve(path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// Test HealthController: Liveness succeeds even when the database is down
+func (suite *HealthControllerTestSuite) TestLiveness_DatabaseDown() {
+	dbDown := func(ctx context.Context) error { return errors.New("server selection timeout") }
+	controller := NewHealthController(dbDown)
+	suite.router.GET("/healthz", controller.Liveness)
+
+	resp := suite.serve("/healthz")
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "ok"}`, resp.Body.String())
+}
+
+// Test HealthController: Readiness when every check passes
+func (suite *HealthControllerTestSuite) TestReadiness_Ready() {
+	dbUp := func(ctx context.Context) error { return nil }
+	controller := NewHealthController(dbUp)
+	suite.router.GET("/readyz", controller.Readiness)
+
+	resp := suite.serve("/readyz")
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "ready"}`, resp.Body.String())
+}
+
+// Test HealthController: Readiness returns 503 when the database is down
+func (suite *HealthControllerTestSuite) TestReadiness_DatabaseDown() {
+	dbUp := func(ctx context.Context) error { return nil }
+	dbDown := func(ctx context.Context) error { return errors.New("server selection timeout") }
+	controller := NewHealthController(dbUp, dbDown)
+	suite.router.GET("/readyz", controller.Readiness)
+
+	resp := suite.serve("/readyz")
+
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "not ready"}`, resp.Body.String())
+}
+
+// Test HealthController: Readiness checks receive a deadline
+func (suite *HealthControllerTestSuite) TestReadiness_CheckHasDeadline() {
+	var hasDeadline bool
+	controller := NewHealthController(func(ctx context.Context) error {
+		_, hasDeadline = ctx.Deadline()
+		return nil
+	})
+	suite.router.GET("/readyz", controller.Readiness)
+
+	suite.serve("/readyz")
+
+	assert.True(suite.T(), hasDeadline)
+}
+
+// Test HealthController: Version reports the build variables
+func (suite *HealthControllerTestSuite) TestVersion() {
+	defer func(version, commit, buildTime string) {
+		Version, GitCommit, BuildTime = version, commit, buildTime
+	}(Version, GitCommit, BuildTime)
+	Version, GitCommit, BuildTime = "1.4.0", "0c4b6eb", "2024-05-01T12:00:00Z"
+
+	controller := NewHealthController()
+	suite.router.GET("/api/version", controller.Version)
+
+	resp := suite.serve("/api/version")
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "success", "data": {
+		"version": "1.4.0", "git_commit": "0c4b6eb", "build_time": "2024-05-01T12:00:00Z"
+	}}`, resp.Body.String())
+}
+
+// Run the test suite
+func TestHealthControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthControllerTestSuite))
+}
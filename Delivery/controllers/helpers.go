@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dateOnlyLayout is the layout of plain YYYY-MM-DD query values
+const dateOnlyLayout = "2006-01-02"
+
+// defaultPageSize is the page size when only page is given, and maxPageSize
+// bounds the page_size query value of every list endpoint
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// respond writes body with the given status, encoded as XML when the client
+// asks for application/xml and as JSON in the request's jsonStyle otherwise
+func respond(ctx *gin.Context, status int, body domain.APIResponse) {
+	body.Data = emptyIfNilSlice(body.Data)
+	switch ctx.NegotiateFormat(binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2) {
+	case binding.MIMEXML, binding.MIMEXML2:
+		ctx.XML(status, body)
+		return
+	}
+	ctx.JSON(status, jsonStyleOf(ctx).present(body))
+}
+
+// respondOK writes a 200 response carrying message and, unless it is nil,
+// data
+func respondOK(ctx *gin.Context, message string, data interface{}) {
+	respond(ctx, http.StatusOK, domain.APIResponse{Message: message, Data: data})
+}
+
+// respondCreated writes a 201 response carrying message and the created
+// resource. Handlers set the Location header themselves.
+func respondCreated(ctx *gin.Context, message string, data interface{}) {
+	respond(ctx, http.StatusCreated, domain.APIResponse{Message: message, Data: data})
+}
+
+// respondMultiStatus writes the per-item results of a bulk operation in
+// which failed of total items failed. The status is success when none
+// failed, 422 Unprocessable Entity when all of them did and 207 Multi-Status
+// when the outcome is mixed.
+func respondMultiStatus(ctx *gin.Context, success int, message string, results interface{}, failed, total int) {
+	status := http.StatusMultiStatus
+	switch {
+	case failed == 0:
+		status = success
+	case failed == total:
+		status = http.StatusUnprocessableEntity
+	}
+	respond(ctx, status, domain.APIResponse{Message: message, Data: results})
+}
+
+// respondError writes an error response whose envelope holds only message
+func respondError(ctx *gin.Context, status int, message string) {
+	respond(ctx, status, domain.APIResponse{Message: message})
+}
+
+// emptyIfNilSlice replaces a nil slice with an empty one of the same type so
+// empty lists encode as [] instead of null
+func emptyIfNilSlice(data interface{}) interface{} {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
+
+// bindStrictJSON decodes the request body into obj like ShouldBindJSON but
+// rejects fields obj does not declare, so a typo such as "titel" is reported
+// instead of silently dropped. The error names the unexpected field.
+func bindStrictJSON(ctx *gin.Context, obj interface{}) error {
+	if ctx.Request.Body == nil {
+		return errors.New("invalid request")
+	}
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// authenticatedUserID returns the caller's ID stored by the auth middleware.
+// A missing, malformed or zero ID means the request is not authenticated, so it
+// writes a 401 response, aborts the request and returns false. Handlers
+// answer 403, not 401, when an authenticated caller may not act.
+func authenticatedUserID(ctx *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(ctx.GetString("user_id"))
+	if err != nil || id.IsZero() {
+		respondError(ctx, http.StatusUnauthorized, "unauthorized")
+		ctx.Abort()
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// parseObjectID reads the named path parameter as an ObjectID. The zero ID
+// never names a record and is rejected too. On failure it writes a 400
+// response, aborts the request and returns false.
+func parseObjectID(ctx *gin.Context, param string) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(ctx.Param(param))
+	if err != nil || id.IsZero() {
+		respondError(ctx, http.StatusBadRequest, "invalid "+param+": must be a valid ObjectID")
+		ctx.Abort()
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// parseObjectIDList parses the IDs of a request body. On failure it writes a
+// 400 response naming the bad value, aborts the request and returns false.
+func parseObjectIDList(ctx *gin.Context, values []string) ([]primitive.ObjectID, bool) {
+	ids := make([]primitive.ObjectID, len(values))
+	for i, value := range values {
+		var err error
+		if ids[i], err = primitive.ObjectIDFromHex(value); err != nil {
+			respondError(ctx, http.StatusBadRequest, fmt.Sprintf("invalid id %q: must be a valid ObjectID", value))
+			ctx.Abort()
+			return nil, false
+		}
+	}
+	return ids, true
+}
+
+// parsePagination reads the page and page_size query values shared by all list
+// endpoints. Without either the zero Pagination is returned, so the whole
+// listing is sent as before pagination existed; with only one, the other
+// defaults to the first page or defaultPageSize records. On invalid input it
+// writes a 400 response, aborts the request and returns false.
+func parsePagination(ctx *gin.Context) (domain.Pagination, bool) {
+	if ctx.Query("page") == "" && ctx.Query("page_size") == "" {
+		return domain.Pagination{}, true
+	}
+	page := domain.Pagination{Page: 1, PageSize: defaultPageSize}
+	if value := ctx.Query("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			respondError(ctx, http.StatusBadRequest, "invalid page: must be a positive integer")
+			ctx.Abort()
+			return domain.Pagination{}, false
+		}
+		page.Page = n
+	}
+	if value := ctx.Query("page_size"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPageSize {
+			respondError(ctx, http.StatusBadRequest, "invalid page_size: must be between 1 and 100")
+			ctx.Abort()
+			return domain.Pagination{}, false
+		}
+		page.PageSize = n
+	}
+	return page, true
+}
+
+// setPaginationLinks sets an RFC 5988 Link header pointing at the first,
+// last and, where they exist, previous and next pages of a listing of total
+// records. The links keep the request's other query values. An unpaginated
+// listing has no other pages and gets no header.
+func setPaginationLinks(ctx *gin.Context, page domain.Pagination, total int64) {
+	if page.PageSize <= 0 {
+		return
+	}
+	last := int((total + int64(page.PageSize) - 1) / int64(page.PageSize))
+	if last < 1 {
+		last = 1
+	}
+	writePaginationLinks(ctx, page, last, page.Page < last)
+}
+
+// setPageLinks sets the Link header of a listing that is not counted, given
+// how many records the current page holds. A full page may be followed by
+// another, so it gets a next link; without a total there is no last link.
+func setPageLinks(ctx *gin.Context, page domain.Pagination, count int) {
+	if page.PageSize <= 0 {
+		return
+	}
+	writePaginationLinks(ctx, page, 0, count >= page.PageSize)
+}
+
+// writePaginationLinks sets the Link header for page. last is the number of
+// the last page, or 0 when it is not known.
+func writePaginationLinks(ctx *gin.Context, page domain.Pagination, last int, hasNext bool) {
+	link := func(n int, rel string) string {
+		u := *ctx.Request.URL
+		query := u.Query()
+		query.Set("page", strconv.Itoa(n))
+		query.Set("page_size", strconv.Itoa(page.PageSize))
+		u.RawQuery = query.Encode()
+		return fmt.Sprintf("<%s>; rel=%q", u.RequestURI(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if page.Page > 1 {
+		prev := page.Page - 1
+		if last > 0 {
+			prev = min(prev, last)
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if hasNext {
+		links = append(links, link(page.Page+1, "next"))
+	}
+	if last > 0 {
+		links = append(links, link(last, "last"))
+	}
+	ctx.Header("Link", strings.Join(links, ", "))
+}
+
+// parseTaskFilter reads the relation, status, due_date and has_due_date
+// query values accepted by the task list endpoints. status is a
+// comma-separated list such as "pending,in_progress". On invalid input it
+// writes a 400 response, aborts the request and returns false.
+func parseTaskFilter(ctx *gin.Context) (domain.TaskFilter, bool) {
+	var filter domain.TaskFilter
+	if value := ctx.Query("relation"); value != "" {
+		if !domain.IsValidRelation(value) {
+			respondError(ctx, http.StatusBadRequest, fmt.Sprintf("invalid relation %q: must be owned, assigned or all", value))
+			ctx.Abort()
+			return domain.TaskFilter{}, false
+		}
+		filter.Relation = value
+	}
+	if value := ctx.Query("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if !domain.IsValidStatus(status) {
+				respondError(ctx, http.StatusBadRequest, fmt.Sprintf("invalid status %q: must be pending, in_progress or completed", status))
+				ctx.Abort()
+				return domain.TaskFilter{}, false
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	if value := ctx.Query("due_date"); value != "" {
+		day, err := time.Parse(dateOnlyLayout, value)
+		if err != nil {
+			respondError(ctx, http.StatusBadRequest, "invalid due_date: must be YYYY-MM-DD")
+			ctx.Abort()
+			return domain.TaskFilter{}, false
+		}
+		filter.DueOn = &day
+	}
+	if value := ctx.Query("has_due_date"); value != "" {
+		hasDueDate, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(ctx, http.StatusBadRequest, "invalid has_due_date: must be true or false")
+			ctx.Abort()
+			return domain.TaskFilter{}, false
+		}
+		filter.HasDueDate = &hasDueDate
+	}
+	for key := range ctx.Request.URL.Query() {
+		name, ok := strings.CutPrefix(key, "meta.")
+		if !ok {
+			continue
+		}
+		if !domain.IsValidMetadataKey(name) {
+			respondError(ctx, http.StatusBadRequest, fmt.Sprintf("invalid metadata filter %q: %s", key, domain.ErrInvalidMetadataKey))
+			ctx.Abort()
+			return domain.TaskFilter{}, false
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = map[string]string{}
+		}
+		filter.Metadata[name] = ctx.Query(key)
+	}
+	if value := ctx.Query("sort"); value != "" {
+		if value != domain.TaskSortOrder {
+			respondError(ctx, http.StatusBadRequest, fmt.Sprintf("invalid sort %q: must be order", value))
+			ctx.Abort()
+			return domain.TaskFilter{}, false
+		}
+		filter.Sort = value
+	}
+	return filter, true
+}
+
+// taskFilterFromRequest builds the filter a PreviewFilterRequest describes.
+// Binding has already checked the relation, statuses and date format.
+func taskFilterFromRequest(req domain.PreviewFilterRequest) (domain.TaskFilter, error) {
+	filter := domain.TaskFilter{
+		Relation:   req.Relation,
+		Statuses:   req.Statuses,
+		HasDueDate: req.HasDueDate,
+	}
+	if req.DueDate != "" {
+		day, err := time.Parse(dateOnlyLayout, req.DueDate)
+		if err != nil {
+			return domain.TaskFilter{}, errors.New("invalid due_date: must be YYYY-MM-DD")
+		}
+		filter.DueOn = &day
+	}
+	for key, value := range req.Metadata {
+		if !domain.IsValidMetadataKey(key) {
+			return domain.TaskFilter{}, fmt.Errorf("invalid metadata filter %q: %s", key, domain.ErrInvalidMetadataKey)
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = map[string]string{}
+		}
+		filter.Metadata[key] = value
+	}
+	return filter, nil
+}
+
+// parseDateParam parses an optional query value as an RFC 3339 timestamp,
+// returned in at, or as a plain YYYY-MM-DD date, returned in day for the use
+// case to resolve in its configured timezone. An empty value yields neither.
+func parseDateParam(value string) (at, day *time.Time, err error) {
+	if value == "" {
+		return nil, nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil, nil
+	}
+	t, err := time.Parse(dateOnlyLayout, value)
+	if err != nil {
+		return nil, nil, errors.New("expected RFC 3339 timestamp or YYYY-MM-DD date")
+	}
+	return nil, &t, nil
+}
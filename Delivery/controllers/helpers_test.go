@@ -0,0 +1,383 @@
+package controllers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Task-Management/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HelpersTestSuite groups tests for the shared controller helpers
+type HelpersTestSuite struct {
+	suite.Suite
+}
+
+// SetupSuite runs once before all tests
+func (suite *HelpersTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// Test parseObjectID: valid path parameter
+func (suite *HelpersTestSuite) TestParseObjectID_Valid() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	expected := primitive.NewObjectID()
+	ctx.Params = gin.Params{{Key: "id", Value: expected.Hex()}}
+
+	id, ok := parseObjectID(ctx, "id")
+
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), expected, id)
+	assert.False(suite.T(), ctx.IsAborted())
+}
+
+// Test parseObjectID: malformed path parameter
+func (suite *HelpersTestSuite) TestParseObjectID_Malformed() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "not-an-id"}}
+
+	id, ok := parseObjectID(ctx, "id")
+
+	assert.False(suite.T(), ok)
+	assert.Equal(suite.T(), primitive.NilObjectID, id)
+	assert.True(suite.T(), ctx.IsAborted())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+
+	var body Domain.APIResponse
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(suite.T(), "invalid id: must be a valid ObjectID", body.Message)
+}
+
+// Test parseObjectID: the zero ObjectID is rejected
+func (suite *HelpersTestSuite) TestParseObjectID_Zero() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: primitive.NilObjectID.Hex()}}
+
+	_, ok := parseObjectID(ctx, "id")
+
+	assert.False(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+// Test authenticatedUserID: ID stored by the auth middleware
+func (suite *HelpersTestSuite) TestAuthenticatedUserID_Valid() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	expected := primitive.NewObjectID()
+	ctx.Set("user_id", expected.Hex())
+
+	id, ok := authenticatedUserID(ctx)
+
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), expected, id)
+	assert.False(suite.T(), ctx.IsAborted())
+}
+
+// Test authenticatedUserID: missing and malformed IDs are unauthenticated, not bad requests
+func (suite *HelpersTestSuite) TestAuthenticatedUserID_Invalid() {
+	for _, value := range []interface{}{nil, "", "not-an-id", primitive.NilObjectID.Hex()} {
+		resp := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+		if value != nil {
+			ctx.Set("user_id", value)
+		}
+
+		_, ok := authenticatedUserID(ctx)
+
+		assert.False(suite.T(), ok)
+		assert.True(suite.T(), ctx.IsAborted())
+		assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	}
+}
+
+// Test parsePagination: everything when no query values are given, and
+// defaults for the missing one of the two
+func (suite *HelpersTestSuite) TestParsePagination_Defaults() {
+	cases := map[string]Domain.Pagination{
+		"":            {},
+		"page=2":      {Page: 2, PageSize: defaultPageSize},
+		"page_size=5": {Page: 1, PageSize: 5},
+	}
+	for query, want := range cases {
+		resp := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+
+		page, ok := parsePagination(ctx)
+
+		assert.True(suite.T(), ok, query)
+		assert.Equal(suite.T(), want, page, query)
+		assert.False(suite.T(), ctx.IsAborted(), query)
+	}
+}
+
+// Test parsePagination: explicit values, including the maximum page size
+func (suite *HelpersTestSuite) TestParsePagination_Explicit() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/?page=3&page_size=100", nil)
+
+	page, ok := parsePagination(ctx)
+
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), Domain.Pagination{Page: 3, PageSize: 100}, page)
+	assert.Equal(suite.T(), int64(200), page.Skip())
+	assert.Equal(suite.T(), int64(100), page.Limit())
+}
+
+// Test parsePagination: invalid values are rejected with 400
+func (suite *HelpersTestSuite) TestParsePagination_Invalid() {
+	cases := map[string]string{
+		"page=0":        "invalid page: must be a positive integer",
+		"page=-1":       "invalid page: must be a positive integer",
+		"page=two":      "invalid page: must be a positive integer",
+		"page_size=0":   "invalid page_size: must be between 1 and 100",
+		"page_size=101": "invalid page_size: must be between 1 and 100",
+		"page_size=ten": "invalid page_size: must be between 1 and 100",
+	}
+	for query, message := range cases {
+		resp := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+
+		_, ok := parsePagination(ctx)
+
+		assert.False(suite.T(), ok, query)
+		assert.True(suite.T(), ctx.IsAborted(), query)
+		assert.Equal(suite.T(), http.StatusBadRequest, resp.Code, query)
+
+		var body Domain.APIResponse
+		assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(suite.T(), message, body.Message, query)
+	}
+}
+
+// Test respondOK: 200 with message and data
+func (suite *HelpersTestSuite) TestRespondOK() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	respondOK(ctx, "Tags retrieved successfully", []string{"work"})
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "Tags retrieved successfully", "data": ["work"]}`, resp.Body.String())
+}
+
+// Test respondOK: nil data is left out of the envelope
+func (suite *HelpersTestSuite) TestRespondOK_NoData() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	respondOK(ctx, "Task deleted successfully", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "Task deleted successfully"}`, resp.Body.String())
+}
+
+// Test respondCreated: 201 with the created resource
+func (suite *HelpersTestSuite) TestRespondCreated() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+
+	task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Write report"}
+	respondCreated(ctx, "Task created successfully", task)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	var body struct {
+		Message string      `json:"message"`
+		Data    Domain.Task `json:"data"`
+	}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(suite.T(), "Task created successfully", body.Message)
+	assert.Equal(suite.T(), task.ID, body.Data.ID)
+}
+
+// Test respondError: the status is kept and the envelope holds only the message
+func (suite *HelpersTestSuite) TestRespondError() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.Header.Set("Accept", "application/xml")
+
+	respondError(ctx, http.StatusNotFound, "task not found")
+
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+	assert.Contains(suite.T(), resp.Header().Get("Content-Type"), "application/xml")
+	assert.Equal(suite.T(), "<response><message>task not found</message></response>", resp.Body.String())
+}
+
+// Test respond: JSON is the default encoding
+func (suite *HelpersTestSuite) TestRespond_DefaultsToJSON() {
+	for _, accept := range []string{"", "*/*", "application/json"} {
+		resp := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+		if accept != "" {
+			ctx.Request.Header.Set("Accept", accept)
+		}
+
+		respond(ctx, http.StatusOK, Domain.APIResponse{Message: "ok"})
+
+		assert.Contains(suite.T(), resp.Header().Get("Content-Type"), "application/json")
+		assert.JSONEq(suite.T(), `{"message": "ok"}`, resp.Body.String())
+	}
+}
+
+// Test respond: a nil list encodes as an empty array
+func (suite *HelpersTestSuite) TestRespond_NilSliceAsEmptyArray() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	var tasks []*Domain.Task
+	respond(ctx, http.StatusOK, Domain.APIResponse{Message: "ok", Data: tasks})
+
+	assert.JSONEq(suite.T(), `{"message": "ok", "data": []}`, resp.Body.String())
+}
+
+// Test respond: XML when requested via Accept
+func (suite *HelpersTestSuite) TestRespond_XML() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.Header.Set("Accept", "application/xml")
+
+	task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Write report"}
+	respond(ctx, http.StatusOK, Domain.APIResponse{Message: "ok", Data: task})
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Contains(suite.T(), resp.Header().Get("Content-Type"), "application/xml")
+
+	var body struct {
+		XMLName xml.Name `xml:"response"`
+		Message string   `xml:"message"`
+		Data    struct {
+			ID    string `xml:"id"`
+			Title string `xml:"title"`
+		} `xml:"data"`
+	}
+	assert.NoError(suite.T(), xml.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(suite.T(), "ok", body.Message)
+	assert.Equal(suite.T(), task.ID.Hex(), body.Data.ID)
+	assert.Equal(suite.T(), "Write report", body.Data.Title)
+}
+
+// Test respond: XML output never includes the password hash
+func (suite *HelpersTestSuite) TestRespond_XMLOmitsPassword() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.Header.Set("Accept", "text/xml")
+
+	respond(ctx, http.StatusOK, Domain.APIResponse{Message: "ok", Data: &Domain.User{Email: "john@example.com", Password: "secretHash"}})
+
+	assert.Contains(suite.T(), resp.Body.String(), "<email>john@example.com</email>")
+	assert.NotContains(suite.T(), resp.Body.String(), "secretHash")
+}
+
+// Test respondMultiStatus: the status reflects whether all, some or none of the items failed
+func (suite *HelpersTestSuite) TestRespondMultiStatus() {
+	cases := []struct {
+		failed, total int
+		expected      int
+	}{
+		{failed: 0, total: 3, expected: http.StatusCreated},
+		{failed: 1, total: 3, expected: http.StatusMultiStatus},
+		{failed: 3, total: 3, expected: http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range cases {
+		resp := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+
+		results := []Domain.BulkUserResult{{Index: 0, Status: Domain.BulkStatusFailed, Error: "user already exists"}}
+		respondMultiStatus(ctx, http.StatusCreated, "processed", results, tc.failed, tc.total)
+
+		assert.Equal(suite.T(), tc.expected, resp.Code)
+		assert.JSONEq(suite.T(), `{"message": "processed", "data": [{"index": 0, "email": "", "status": "failed", "error": "user already exists"}]}`, resp.Body.String())
+	}
+}
+
+// Test setPaginationLinks: a middle page links to every neighbour
+func (suite *HelpersTestSuite) TestSetPaginationLinks_MiddlePage() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/tasks?status=pending&page=2", nil)
+
+	setPaginationLinks(ctx, Domain.Pagination{Page: 2, PageSize: 10}, 45)
+
+	assert.Equal(suite.T(), `</api/tasks?page=1&page_size=10&status=pending>; rel="first", `+
+		`</api/tasks?page=1&page_size=10&status=pending>; rel="prev", `+
+		`</api/tasks?page=3&page_size=10&status=pending>; rel="next", `+
+		`</api/tasks?page=5&page_size=10&status=pending>; rel="last"`, resp.Header().Get("Link"))
+}
+
+// Test setPaginationLinks: a single page has neither prev nor next
+func (suite *HelpersTestSuite) TestSetPaginationLinks_SinglePage() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/tasks", nil)
+
+	setPaginationLinks(ctx, Domain.Pagination{Page: 1, PageSize: 20}, 0)
+
+	assert.Equal(suite.T(), `</api/tasks?page=1&page_size=20>; rel="first", </api/tasks?page=1&page_size=20>; rel="last"`, resp.Header().Get("Link"))
+}
+
+// Test setPaginationLinks: an unpaginated listing gets no header
+func (suite *HelpersTestSuite) TestSetPaginationLinks_Unpaginated() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/tasks", nil)
+
+	setPaginationLinks(ctx, Domain.Pagination{}, 45)
+
+	assert.Empty(suite.T(), resp.Header().Get("Link"))
+}
+
+// Test setPageLinks: a full page of an uncounted listing links to the next
+func (suite *HelpersTestSuite) TestSetPageLinks_FullPage() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/tasks/search?q=report&page=2&page_size=10", nil)
+
+	setPageLinks(ctx, Domain.Pagination{Page: 2, PageSize: 10}, 10)
+
+	assert.Equal(suite.T(), `</api/tasks/search?page=1&page_size=10&q=report>; rel="first", `+
+		`</api/tasks/search?page=1&page_size=10&q=report>; rel="prev", `+
+		`</api/tasks/search?page=3&page_size=10&q=report>; rel="next"`, resp.Header().Get("Link"))
+}
+
+// Test setPageLinks: a short page is the last one
+func (suite *HelpersTestSuite) TestSetPageLinks_ShortPage() {
+	resp := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/tasks/search?q=report", nil)
+
+	setPageLinks(ctx, Domain.Pagination{Page: 1, PageSize: 10}, 3)
+
+	assert.Equal(suite.T(), `</api/tasks/search?page=1&page_size=10&q=report>; rel="first"`, resp.Header().Get("Link"))
+}
+
+// Run the test suite
+func TestHelpersTestSuite(t *testing.T) {
+	suite.Run(t, new(HelpersTestSuite))
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "Task-Management/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceSwitch turns maintenance mode on and off at runtime
+type MaintenanceSwitch interface {
+	Enabled() bool
+	SetEnabled(enabled bool)
+}
+
+type MaintenanceController interface {
+	SetMaintenance(ctx *gin.Context)
+}
+
+type MaintenanceControllerImpl struct {
+	mode MaintenanceSwitch
+}
+
+func NewMaintenanceController(mode MaintenanceSwitch) *MaintenanceControllerImpl {
+	return &MaintenanceControllerImpl{
+		mode: mode,
+	}
+}
+
+// SetMaintenance turns maintenance mode on or off and reports the new state
+func (c *MaintenanceControllerImpl) SetMaintenance(ctx *gin.Context) {
+	var req domain.MaintenanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.mode.SetEnabled(*req.Enabled)
+
+	message := "maintenance mode disabled"
+	if *req.Enabled {
+		message = "maintenance mode enabled"
+	}
+	respondOK(ctx, message, gin.H{"enabled": c.mode.Enabled()})
+}
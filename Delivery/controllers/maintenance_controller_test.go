@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeMaintenanceSwitch records the state set by the controller
+type fakeMaintenanceSwitch struct {
+	enabled bool
+}
+
+func (s *fakeMaintenanceSwitch) Enabled() bool {
+	return s.enabled
+}
+
+func (s *fakeMaintenanceSwitch) SetEnabled(enabled bool) {
+	s.enabled = enabled
+}
+
+// MaintenanceControllerTestSuite groups the maintenance toggle tests
+type MaintenanceControllerTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+	mode   *fakeMaintenanceSwitch
+}
+
+// SetupSuite runs once before all tests
+func (suite *MaintenanceControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *MaintenanceControllerTestSuite) SetupTest() {
+	suite.mode = &fakeMaintenanceSwitch{}
+	suite.router = gin.New()
+	suite.router.POST("/admin/maintenance", NewMaintenanceController(suite.mode).SetMaintenance)
+}
+
+func (suite *MaintenanceControllerTestSuite) post(body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/admin/maintenance", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// Test MaintenanceController: SetMaintenance turns maintenance mode on
+func (suite *MaintenanceControllerTestSuite) TestSetMaintenance_Enable() {
+	resp := suite.post(`{"enabled": true}`)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "maintenance mode enabled", "data": {"enabled": true}}`, resp.Body.String())
+	assert.True(suite.T(), suite.mode.enabled)
+}
+
+// Test MaintenanceController: SetMaintenance turns maintenance mode off
+func (suite *MaintenanceControllerTestSuite) TestSetMaintenance_Disable() {
+	suite.mode.enabled = true
+
+	resp := suite.post(`{"enabled": false}`)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "maintenance mode disabled", "data": {"enabled": false}}`, resp.Body.String())
+	assert.False(suite.T(), suite.mode.enabled)
+}
+
+// Test MaintenanceController: SetMaintenance without a state is rejected
+func (suite *MaintenanceControllerTestSuite) TestSetMaintenance_MissingEnabled() {
+	suite.mode.enabled = true
+
+	resp := suite.post(`{}`)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+	assert.True(suite.T(), suite.mode.enabled)
+}
+
+// Run the test suite
+func TestMaintenanceControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceControllerTestSuite))
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonStyle is how a JSON response names its fields and formats its
+// timestamps. The response middlewares store it in the request context:
+// CamelCaseJSON sets "camel_case_json" and TimestampFormat sets
+// "timestamp_layout".
+type jsonStyle struct {
+	camelCase       bool
+	timestampLayout string
+}
+
+// jsonStyleOf returns the style the middlewares chose for the request
+func jsonStyleOf(ctx *gin.Context) jsonStyle {
+	return jsonStyle{
+		camelCase:       ctx.GetBool("camel_case_json"),
+		timestampLayout: ctx.GetString("timestamp_layout"),
+	}
+}
+
+// plain reports whether the style leaves encoding/json's output as it is
+func (s jsonStyle) plain() bool {
+	return !s.camelCase && s.timestampLayout == ""
+}
+
+// name returns the key a snake_case field name is sent under
+func (s jsonStyle) name(key string) string {
+	if s.camelCase {
+		return toCamelCase(key)
+	}
+	return key
+}
+
+// marshal encodes v as JSON in the style
+func (s jsonStyle) marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(s.present(v))
+}
+
+// present returns v in a form that encodes in the style. Only the names of
+// struct fields and of the objects controllers build with gin.H are
+// converted; the keys of any other map, such as a task's metadata, are user
+// data and are sent as stored. Likewise only time.Time values are formatted
+// with the timestamp layout, never strings that happen to look like one.
+func (s jsonStyle) present(v interface{}) interface{} {
+	if s.plain() {
+		return v
+	}
+	return s.value(reflect.ValueOf(v))
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	ginHType          = reflect.TypeOf(gin.H{})
+)
+
+func (s jsonStyle) value(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if s.timestampLayout != "" {
+		switch t := v.Interface().(type) {
+		case time.Time:
+			return t.Format(s.timestampLayout)
+		case *time.Time:
+			if t != nil {
+				return t.Format(s.timestampLayout)
+			}
+		}
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return s.value(v.Elem())
+	case reflect.Struct:
+		return s.object(v)
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		converted := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if v.Type() == ginHType {
+				key = s.name(key)
+			}
+			converted[key] = s.value(iter.Value())
+		}
+		return converted
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = s.value(v.Index(i))
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+// object converts a struct the way encoding/json would encode it, following
+// its json tags and inlining embedded structs, with the field names in the
+// style
+func (s jsonStyle) object(v reflect.Value) jsonObject {
+	var fields jsonObject
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		value := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				fields = fields.with(s.object(value)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if strings.Contains(options, "omitempty") && isEmptyValue(value) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = fields.with(jsonField{name: s.name(name), value: s.value(value)})
+	}
+	return fields
+}
+
+// isEmptyValue reports whether v is left out by an omitempty tag
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// jsonObject is a JSON object whose fields keep the order of the struct it
+// was built from
+type jsonObject []jsonField
+
+type jsonField struct {
+	name  string
+	value interface{}
+}
+
+// with adds fields, replacing any earlier field of the same name as an outer
+// struct's field hides an embedded one
+func (o jsonObject) with(fields ...jsonField) jsonObject {
+	for _, field := range fields {
+		replaced := false
+		for i := range o {
+			if o[i].name == field.name {
+				o[i], replaced = field, true
+				break
+			}
+		}
+		if !replaced {
+			o = append(o, field)
+		}
+	}
+	return o
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toCamelCase turns a snake_case key such as "due_date" into "dueDate"
+func toCamelCase(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
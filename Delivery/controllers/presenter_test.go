@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Task-Management/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PresenterTestSuite groups tests for the JSON response styles
+type PresenterTestSuite struct {
+	suite.Suite
+}
+
+// SetupSuite runs once before all tests
+func (suite *PresenterTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// styledResponse answers a request through respond with data, in the style
+// the given context values choose
+func (suite *PresenterTestSuite) styledResponse(values gin.H, data interface{}) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/", func(ctx *gin.Context) {
+		for key, value := range values {
+			ctx.Set(key, value)
+		}
+		respondOK(ctx, "ok", data)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// Test present: camelCase renames struct fields but not metadata keys
+func (suite *PresenterTestSuite) TestPresent_CamelCase() {
+	taskID := primitive.NewObjectID()
+	task := Domain.TaskWithOwner{
+		Task: Domain.Task{
+			ID:               taskID,
+			Title:            "Report",
+			Status:           Domain.StatusPending,
+			EstimatedMinutes: 90,
+			Checklist:        []Domain.ChecklistItem{{Text: "a", Done: true}},
+			Metadata:         map[string]string{"cost_center": "ops"},
+		},
+	}
+
+	resp := suite.styledResponse(gin.H{"camel_case_json": true}, []Domain.TaskWithOwner{task})
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	var body struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Len(suite.T(), body.Data, 1)
+	item := body.Data[0]
+	assert.Equal(suite.T(), taskID.Hex(), item["id"])
+	assert.Equal(suite.T(), float64(90), item["estimatedMinutes"])
+	assert.Equal(suite.T(), false, item["isOverdue"])
+	assert.Equal(suite.T(), map[string]interface{}{"cost_center": "ops"}, item["metadata"])
+	assert.Equal(suite.T(), []interface{}{map[string]interface{}{"text": "a", "done": true}}, item["checklist"])
+	assert.Contains(suite.T(), item, "user")
+	assert.Contains(suite.T(), item, "createdAt")
+	assert.Contains(suite.T(), item, "dueDate")
+	assert.NotContains(suite.T(), item, "created_at")
+	assert.NotContains(suite.T(), item, "actualMinutes")
+	assert.NotContains(suite.T(), item, "remindAt")
+}
+
+// Test present: camelCase renames the keys of objects built with gin.H
+func (suite *PresenterTestSuite) TestPresent_CamelCaseGinH() {
+	resp := suite.styledResponse(gin.H{"camel_case_json": true}, gin.H{"must_change_password": true})
+
+	assert.JSONEq(suite.T(), `{"message": "ok", "data": {"mustChangePassword": true}}`, resp.Body.String())
+}
+
+// Test present: time values are formatted with the layout, strings are not
+func (suite *PresenterTestSuite) TestPresent_TimestampLayout() {
+	remindAt := time.Date(2024, 1, 9, 17, 0, 0, 0, time.FixedZone("", 2*60*60))
+	task := &Domain.Task{
+		Title:     "2024-01-02T03:04:05.123456789Z",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		RemindAt:  &remindAt,
+		Metadata:  map[string]string{"since": "2024-01-02T03:04:05.123456789Z"},
+	}
+
+	resp := suite.styledResponse(gin.H{"timestamp_layout": "2006-01-02T15:04:05.000Z07:00"}, task)
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(suite.T(), "2024-01-02T03:04:05.123Z", body.Data["created_at"])
+	assert.Equal(suite.T(), "2024-01-09T17:00:00.000+02:00", body.Data["remind_at"])
+	assert.Equal(suite.T(), "0001-01-01T00:00:00.000Z", body.Data["due_date"])
+	assert.Equal(suite.T(), "2024-01-02T03:04:05.123456789Z", body.Data["title"])
+	assert.Equal(suite.T(), map[string]interface{}{"since": "2024-01-02T03:04:05.123456789Z"}, body.Data["metadata"])
+	assert.NotContains(suite.T(), body.Data, "completed_at")
+}
+
+// Test present: the plain style leaves the encoding to encoding/json
+func (suite *PresenterTestSuite) TestPresent_Plain() {
+	task := &Domain.Task{Title: "Report", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)}
+
+	resp := suite.styledResponse(nil, task)
+
+	expected, _ := json.Marshal(Domain.APIResponse{Message: "ok", Data: task})
+	assert.Equal(suite.T(), string(expected), resp.Body.String())
+}
+
+// Test toCamelCase: snake_case keys are converted
+func (suite *PresenterTestSuite) TestToCamelCase() {
+	for key, expected := range map[string]string{
+		"created_at":           "createdAt",
+		"id":                   "id",
+		"blocking_task_ids":    "blockingTaskIds",
+		"must_change_password": "mustChangePassword",
+	} {
+		assert.Equal(suite.T(), expected, toCamelCase(key))
+	}
+}
+
+// Run the test suite
+func TestPresenterTestSuite(t *testing.T) {
+	suite.Run(t, new(PresenterTestSuite))
+}
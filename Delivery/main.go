@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,12 +12,13 @@ import (
 
 	"Task-Management/Delivery/controllers"
 	"Task-Management/Delivery/routers"
+	infrastructure "Task-Management/Infrastructure"
 	repository "Task-Management/Repository"
 	"Task-Management/Usecases"
 
-	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 func initMongoDB() (*mongo.Client, *mongo.Database, error) {
@@ -41,9 +43,46 @@ func initServer(router http.Handler) *http.Server {
 	}
 }
 
-func runServer(srv *http.Server, suppressLogs bool) {
+// initAdminServer builds the server for the admin-only router, listening on
+// addr instead of the public port
+func initAdminServer(addr string, router http.Handler) *http.Server {
+	return &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+}
+
+// initRedirectServer builds a plain HTTP server on addr that permanently
+// redirects every request to the same URL on the HTTPS server listening at
+// httpsAddr
+func initRedirectServer(addr, httpsAddr string) *http.Server {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+	return &http.Server{
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(r.Host); err == nil {
+				host = h
+			}
+			if httpsPort != "" && httpsPort != "443" {
+				host = net.JoinHostPort(host, httpsPort)
+			}
+			http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+		}),
+	}
+}
+
+// runServer starts srv in the background. It serves HTTPS when certFile and
+// keyFile are set and plain HTTP otherwise.
+func runServer(srv *http.Server, certFile, keyFile string, suppressLogs bool) {
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if certFile != "" && keyFile != "" {
+			err = srv.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			if !suppressLogs {
 				log.Fatalf("Failed to start server: %v", err)
 			}
@@ -51,7 +90,61 @@ func runServer(srv *http.Server, suppressLogs bool) {
 	}()
 }
 
+// indexTimeout bounds creating every repository index at startup
+const indexTimeout = 30 * time.Second
+
+// createIndexes creates the repositories' indexes, giving up after
+// indexTimeout
+func createIndexes(db *mongo.Database, opts []repository.Option) error {
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	return repository.EnsureIndexes(ctx, db, opts...)
+}
+
+// runPeriodically calls job every interval in the background until ctx is
+// done. A non-positive interval disables the job.
+func runPeriodically(ctx context.Context, interval time.Duration, job func(context.Context)) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	}()
+}
+
+// shutdownServer stops accepting connections and gives in-flight requests
+// up to timeout to complete
+func shutdownServer(srv *http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
 func main() {
+	cfg := infrastructure.LoadConfig()
+	if err := infrastructure.ConfigureLogging(cfg.LogLevel, cfg.LogFormat, os.Stderr); err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	if err := infrastructure.ConfigureJWT(cfg.JWTAlgorithm, cfg.JWTPrivateKeyPath, cfg.JWTPublicKeyPath); err != nil {
+		log.Fatalf("Failed to configure JWT: %v", err)
+	}
+	if err := cfg.CORS.Validate(); err != nil {
+		log.Fatalf("Invalid CORS configuration: %v", err)
+	}
+	infrastructure.ConfigureTokenExpiry(cfg.JWTExpiry, cfg.JWTRememberMeExpiry)
+	if err := infrastructure.ConfigurePasswordCost(cfg.BcryptCost); err != nil {
+		log.Fatalf("Failed to configure password hashing: %v", err)
+	}
+
 	// Initialize MongoDB
 	client, db, err := initMongoDB()
 	if err != nil {
@@ -60,37 +153,151 @@ func main() {
 	defer client.Disconnect(context.Background())
 
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(db)
-	taskRepo := repository.NewTaskRepository(db)
+	repoOptions := []repository.Option{
+		repository.WithCollectionPrefix(cfg.CollectionPrefix),
+		repository.WithRetries(cfg.RepoRetries, cfg.RepoRetryBackoff),
+	}
+	if cfg.CreateIndexes {
+		if err := createIndexes(db, repoOptions); err != nil {
+			log.Fatalf("Failed to create indexes: %v", err)
+		}
+	}
+	userRepo := repository.NewUserRepository(db, repoOptions...)
+	taskRepo := repository.NewTaskRepository(db, repoOptions...)
+	apiKeyRepo := repository.NewAPIKeyRepository(db, repoOptions...)
+	inviteRepo := repository.NewInviteRepository(db, repoOptions...)
+	auditRepo := repository.NewAuditRepository(db, repoOptions...)
+	taskHistoryRepo := repository.NewTaskHistoryRepository(db, repoOptions...)
 
 	// Initialize use cases
-	userUseCase := Usecases.NewUserUseCase(userRepo)
-	taskUseCase := Usecases.NewTaskUseCase(taskRepo)
+	userUseCase := Usecases.NewUserUseCase(
+		userRepo,
+		Usecases.WithPasswordHistory(cfg.PasswordHistory),
+		Usecases.WithInviteRepository(inviteRepo),
+		Usecases.WithInviteOnly(cfg.InviteOnly),
+		Usecases.WithAuditRepository(auditRepo),
+		Usecases.WithTaskRepository(taskRepo),
+		Usecases.WithDeletionGracePeriod(cfg.DeletionGrace),
+		Usecases.WithUserLocation(cfg.Location),
+	)
+	taskUseCase := Usecases.NewTaskUseCase(
+		taskRepo,
+		Usecases.WithStrictDelete(cfg.StrictDelete),
+		Usecases.WithUserRepository(userRepo),
+		Usecases.WithLocation(cfg.Location),
+		Usecases.WithTaskQuota(cfg.TaskQuota),
+		Usecases.WithCreateInterval(cfg.TaskCreateInterval),
+		Usecases.WithBatchLimit(cfg.BatchGetMaxIDs),
+		Usecases.WithTagLimits(cfg.MaxTagsPerTask, cfg.MaxTagLength),
+		Usecases.WithMetadataLimits(cfg.MaxMetadataKeys, cfg.MaxMetadataKeyLen, cfg.MaxMetadataValueLen),
+		Usecases.WithDefaultStatus(cfg.DefaultTaskStatus),
+		Usecases.WithDefaultDueDate(cfg.DefaultDueIn, cfg.DefaultDueEndOfDay),
+		Usecases.WithAutoAssign(cfg.AutoAssignCreator),
+		Usecases.WithSharedTasks(cfg.SharedTasks),
+		Usecases.WithNoStatusRegression(cfg.NoStatusRegression),
+		Usecases.WithUniqueTitles(cfg.UniqueTaskTitles),
+		Usecases.WithTaskAuditRepository(auditRepo),
+		Usecases.WithTaskHistoryRepository(taskHistoryRepo),
+	)
+	apiKeyUseCase := Usecases.NewAPIKeyUseCase(apiKeyRepo)
 
 	// Initialize controllers
 	userController := controllers.NewUserController(userUseCase)
 	taskController := controllers.NewTaskController(taskUseCase)
+	apiKeyController := controllers.NewAPIKeyController(apiKeyUseCase)
+	healthController := controllers.NewHealthController(func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	})
+	maintenanceMode := infrastructure.NewMaintenanceMode(cfg.Maintenance, cfg.MaintenanceRetry)
+	maintenanceController := controllers.NewMaintenanceController(maintenanceMode)
 
-	// Setup router
 	// Define middleware functions
-	middleware1 := func(c *gin.Context) {
-		// Example middleware logic
-		log.Println("Middleware1 executed")
-		c.Next()
-	}
-
-	middleware2 := func(c *gin.Context) {
-		// Example middleware logic
-		log.Println("Middleware2 executed")
-		c.Next()
-	}
+	lookupUser := infrastructure.CachedUserLookup(userUseCase.GetUserByID, cfg.CurrentUserCacheTTL)
+	authMiddleware := infrastructure.AuthMiddleware(infrastructure.ValidateToken, lookupUser)
+	adminMiddleware := infrastructure.AdminMiddleware()
+	apiKeyMiddleware := infrastructure.APIKeyMiddleware(apiKeyUseCase.ValidateKey)
+	jsonContentTypeMiddleware := infrastructure.RequireJSONContentType()
+	currentUserMiddleware := infrastructure.CurrentUserMiddleware(lookupUser)
+	passwordChangeMiddleware := infrastructure.PasswordChangeMiddleware(userUseCase.GetUserByID)
+	freshTokenMiddleware := infrastructure.RequireFreshToken(cfg.FreshTokenMaxAge)
+	heavyRouteTimeout := infrastructure.Timeout(cfg.HeavyRouteTimeout)
+	requestLogger := infrastructure.RequestLogger()
+	corsMiddleware := infrastructure.CORSMiddleware(cfg.CORS)
+	jsonNamingMiddleware := infrastructure.CamelCaseJSON(cfg.CamelCaseJSON)
+	timestampMiddleware := infrastructure.TimestampFormat(cfg.TimestampLayout)
+	confirmationMiddleware := infrastructure.RequireConfirmation()
+	maintenanceMiddleware := infrastructure.Maintenance(maintenanceMode)
 
 	// Setup router with middlewares
-	router := routers.SetupRouter(userController, taskController, middleware1, middleware2)
+	router := routers.SetupRouter(
+		userController,
+		taskController,
+		apiKeyController,
+		healthController,
+		maintenanceController,
+		authMiddleware,
+		adminMiddleware,
+		apiKeyMiddleware,
+		jsonContentTypeMiddleware,
+		currentUserMiddleware,
+		passwordChangeMiddleware,
+		freshTokenMiddleware,
+		heavyRouteTimeout,
+		requestLogger,
+		corsMiddleware,
+		jsonNamingMiddleware,
+		timestampMiddleware,
+		confirmationMiddleware,
+		maintenanceMiddleware,
+		cfg.AdminAddr != "",
+	)
 
 	// Initialize and run server
 	srv := initServer(router)
-	runServer(srv, false)
+	runServer(srv, cfg.TLSCertPath, cfg.TLSKeyPath, false)
+
+	// Optionally serve the admin routes on their own listener only
+	var adminSrv *http.Server
+	if cfg.AdminAddr != "" {
+		adminRouter := routers.SetupAdminRouter(
+			userController,
+			taskController,
+			apiKeyController,
+			maintenanceController,
+			authMiddleware,
+			adminMiddleware,
+			jsonContentTypeMiddleware,
+			passwordChangeMiddleware,
+			heavyRouteTimeout,
+			requestLogger,
+			jsonNamingMiddleware,
+			timestampMiddleware,
+			confirmationMiddleware,
+			maintenanceMiddleware,
+		)
+		adminSrv = initAdminServer(cfg.AdminAddr, adminRouter)
+		runServer(adminSrv, cfg.TLSCertPath, cfg.TLSKeyPath, false)
+	}
+
+	// Optionally send plain HTTP clients to the HTTPS listener
+	var redirectSrv *http.Server
+	if cfg.TLSEnabled() && cfg.HTTPRedirectAddr != "" {
+		redirectSrv = initRedirectServer(cfg.HTTPRedirectAddr, srv.Addr)
+		runServer(redirectSrv, "", "", false)
+	}
+
+	// Hard-delete accounts whose deletion grace period is over
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	runPeriodically(jobsCtx, cfg.PurgeInterval, func(ctx context.Context) {
+		purged, err := userUseCase.PurgeDeletedUsers(ctx)
+		if err != nil {
+			log.Println("Failed to purge deleted accounts:", err)
+			return
+		}
+		if purged > 0 {
+			log.Printf("Purged %d deleted accounts", purged)
+		}
+	})
 
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
@@ -98,12 +305,20 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Give outstanding requests a deadline for completion
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
+	if redirectSrv != nil {
+		if err := shutdownServer(redirectSrv, cfg.ShutdownTimeout); err != nil {
+			log.Println("Redirect server forced to shutdown:", err)
+		}
+	}
+	if adminSrv != nil {
+		if err := shutdownServer(adminSrv, cfg.ShutdownTimeout); err != nil {
+			log.Println("Admin server forced to shutdown:", err)
+		}
+	}
+	if err := shutdownServer(srv, cfg.ShutdownTimeout); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
@@ -2,9 +2,20 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"testing"
 	"time"
@@ -52,6 +63,15 @@ func (suite *MainTestSuite) TestInitServer() {
 	assert.Equal(suite.T(), router, server.Handler)
 }
 
+// TestInitAdminServer tests that the admin server listens on its own address
+func (suite *MainTestSuite) TestInitAdminServer() {
+	router := http.NewServeMux()
+	server := initAdminServer("127.0.0.1:9090", router)
+
+	assert.Equal(suite.T(), "127.0.0.1:9090", server.Addr)
+	assert.Equal(suite.T(), router, server.Handler)
+}
+
 // TestRunServer tests running the server
 func (suite *MainTestSuite) TestRunServer() {
 	router := http.NewServeMux()
@@ -73,13 +93,164 @@ func (suite *MainTestSuite) TestRunServerWithError() {
 	}
 
 	go func() {
-		runServer(server, true) // Suppress logs during testing
+		runServer(server, "", "", true) // Suppress logs during testing
 	}()
 	time.Sleep(100 * time.Millisecond) // Allow goroutine to execute
 
 	assert.NoError(suite.T(), server.Close())
 }
 
+// writeSelfSignedCert writes a throwaway certificate and key for 127.0.0.1
+// into dir and returns their paths
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+// TestRunServer_TLS tests that a configured certificate makes the server speak HTTPS
+func (suite *MainTestSuite) TestRunServer_TLS() {
+	certPath, keyPath := writeSelfSignedCert(suite.T(), suite.T().TempDir())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	router := http.NewServeMux()
+	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{Addr: addr, Handler: router}
+	runServer(server, certPath, keyPath, true)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	var resp *http.Response
+	for i := 0; i < 20; i++ {
+		if resp, err = client.Get("https://" + addr + "/ping"); err == nil {
+			break
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.NotNil(suite.T(), resp.TLS)
+}
+
+// TestInitRedirectServer tests that plain HTTP requests are sent to the HTTPS port
+func (suite *MainTestSuite) TestInitRedirectServer() {
+	server := initRedirectServer(":8081", ":8443")
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com:8081/api/tasks?limit=5", nil)
+	resp := httptest.NewRecorder()
+	server.Handler.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), ":8081", server.Addr)
+	assert.Equal(suite.T(), http.StatusPermanentRedirect, resp.Code)
+	assert.Equal(suite.T(), "https://example.com:8443/api/tasks?limit=5", resp.Header().Get("Location"))
+}
+
+// TestShutdownServer_HonorsTimeout tests that shutdown returns once the timeout elapses
+func (suite *MainTestSuite) TestShutdownServer_HonorsTimeout() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	router := http.NewServeMux()
+	router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	server := &http.Server{Handler: router}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	begin := time.Now()
+	err = shutdownServer(server, 50*time.Millisecond)
+
+	assert.ErrorIs(suite.T(), err, context.DeadlineExceeded)
+	assert.Less(suite.T(), time.Since(begin), time.Second)
+}
+
+// TestRunPeriodically tests that the job runs on every tick until the context is cancelled
+func (suite *MainTestSuite) TestRunPeriodically() {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 10)
+
+	runPeriodically(ctx, 10*time.Millisecond, func(context.Context) {
+		calls <- struct{}{}
+	})
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			suite.FailNow("job was not run")
+		}
+	}
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	for len(calls) > 0 {
+		<-calls
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	assert.Zero(suite.T(), len(calls))
+}
+
+// TestRunPeriodically_Disabled tests that a zero interval never runs the job
+func (suite *MainTestSuite) TestRunPeriodically_Disabled() {
+	ran := make(chan struct{}, 1)
+
+	runPeriodically(context.Background(), 0, func(context.Context) {
+		ran <- struct{}{}
+	})
+	time.Sleep(30 * time.Millisecond)
+
+	assert.Zero(suite.T(), len(ran))
+}
+
 // TestMainFunction tests the main function indirectly by mocking dependencies
 func (suite *MainTestSuite) TestMainFunction() {
 	// Mock environment variables
@@ -6,42 +6,173 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// SetupRouter builds the public API. When separateAdmin is set the
+// /api/admin routes are left out so they can be served only by the router
+// from SetupAdminRouter on its own listener. Every route except the health
+// probes and the maintenance toggle runs behind maintenanceMiddleware.
 func SetupRouter(
 	userController controllers.UserController,
 	taskController controllers.TaskController,
+	apiKeyController controllers.APIKeyController,
+	healthController controllers.HealthController,
+	maintenanceController controllers.MaintenanceController,
 	authMiddleware gin.HandlerFunc,
 	adminMiddleware gin.HandlerFunc,
+	apiKeyMiddleware gin.HandlerFunc,
+	jsonContentTypeMiddleware gin.HandlerFunc,
+	currentUserMiddleware gin.HandlerFunc,
+	passwordChangeMiddleware gin.HandlerFunc,
+	freshTokenMiddleware gin.HandlerFunc,
+	heavyRouteTimeout gin.HandlerFunc,
+	requestLogger gin.HandlerFunc,
+	corsMiddleware gin.HandlerFunc,
+	jsonNamingMiddleware gin.HandlerFunc,
+	timestampMiddleware gin.HandlerFunc,
+	confirmationMiddleware gin.HandlerFunc,
+	maintenanceMiddleware gin.HandlerFunc,
+	separateAdmin bool,
 ) *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+	router.Use(requestLogger, jsonNamingMiddleware, timestampMiddleware, corsMiddleware, gin.Recovery(), jsonContentTypeMiddleware)
+
+	// Probes for the orchestrator, outside /api and without authentication
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
 
 	// Public routes
 	public := router.Group("/api")
+	public.Use(maintenanceMiddleware)
 	{
 		public.POST("/register", userController.Register)
 		public.POST("/login", userController.Login)
+		public.GET("/version", healthController.Version)
+	}
+
+	// Routes a user with a reset password may still call
+	account := router.Group("/api")
+	account.Use(maintenanceMiddleware, authMiddleware, currentUserMiddleware)
+	{
+		account.PUT("/me/password", freshTokenMiddleware, userController.ChangePassword)
+		account.POST("/me/revoke-sessions", userController.RevokeSessions)
 	}
 
 	// Protected routes
 	protected := router.Group("/api")
-	protected.Use(authMiddleware)
+	protected.Use(maintenanceMiddleware, authMiddleware, currentUserMiddleware, passwordChangeMiddleware)
 	{
 		// User routes
 		protected.GET("/users", userController.GetAllUsers)
+		protected.GET("/users/:id", userController.GetUserByID)
+		protected.GET("/me", userController.GetMe)
+		protected.GET("/me/activity", taskController.GetActivity)
+		protected.GET("/me/agenda", taskController.GetAgenda)
+		protected.GET("/me/completed", taskController.GetCompleted)
+		protected.GET("/me/export", userController.ExportMe)
+		protected.PUT("/me", userController.UpdateProfile)
+		protected.DELETE("/me", freshTokenMiddleware, userController.DeleteMe)
 
 		// Task routes
 		protected.POST("/tasks", taskController.CreateTask)
 		protected.GET("/tasks", taskController.GetTasksByUserID)
+		protected.GET("/tasks/count", taskController.CountTasks)
+		protected.POST("/tasks/preview-filter", taskController.PreviewFilter)
+		protected.POST("/tasks/batch-get", taskController.BatchGetTasks)
+		protected.POST("/tasks/bulk-tag", taskController.BulkTagTasks)
+		protected.PATCH("/tasks/reorder", taskController.ReorderTasks)
+		protected.GET("/tasks/upcoming", taskController.GetUpcomingTasks)
+		protected.GET("/tasks/search", taskController.SearchTasks)
+		protected.GET("/tasks/tags", taskController.GetTaskTags)
+		protected.GET("/tasks/shared", taskController.GetSharedTasks)
+		protected.GET("/tasks/shared-with-me", taskController.GetSharedWithMe)
+		protected.GET("/tasks/time-report", taskController.GetTimeReport)
 		protected.GET("/tasks/:id", taskController.GetTaskByID)
+		protected.GET("/tasks/:id/history", taskController.GetTaskHistory)
+		protected.POST("/tasks/:id/clone", taskController.CloneTask)
+		protected.POST("/tasks/:id/transfer", taskController.TransferTask)
+		protected.POST("/tasks/:id/share", taskController.ShareTask)
+		protected.DELETE("/tasks/:id/share/:userID", taskController.UnshareTask)
+		protected.PATCH("/tasks/:id/checklist/:index", taskController.ToggleChecklistItem)
 		protected.PUT("/tasks/:id", taskController.UpdateTask)
+		protected.DELETE("/tasks/completed", taskController.DeleteCompletedTasks)
 		protected.DELETE("/tasks/:id", taskController.DeleteTask)
 	}
 
 	// Admin routes
-	admin := router.Group("/api/admin")
-	admin.Use(authMiddleware, adminMiddleware)
+	if !separateAdmin {
+		admin := router.Group("/api/admin")
+		admin.Use(authMiddleware, adminMiddleware, passwordChangeMiddleware)
+		registerAdminRoutes(admin, userController, taskController, apiKeyController, maintenanceController, heavyRouteTimeout, confirmationMiddleware, maintenanceMiddleware)
+	}
+
+	// Service routes for machine clients authenticated by API key
+	service := router.Group("/api/service")
+	service.Use(maintenanceMiddleware, apiKeyMiddleware)
 	{
-		admin.GET("/tasks", taskController.GetAllTasks)
+		service.GET("/tasks", heavyRouteTimeout, taskController.GetAllTasks)
 	}
 
 	return router
 }
+
+// SetupAdminRouter builds a router serving only the /api/admin routes, for a
+// listener kept off the public network. Browsers are not expected to call it,
+// so it has no CORS middleware.
+func SetupAdminRouter(
+	userController controllers.UserController,
+	taskController controllers.TaskController,
+	apiKeyController controllers.APIKeyController,
+	maintenanceController controllers.MaintenanceController,
+	authMiddleware gin.HandlerFunc,
+	adminMiddleware gin.HandlerFunc,
+	jsonContentTypeMiddleware gin.HandlerFunc,
+	passwordChangeMiddleware gin.HandlerFunc,
+	heavyRouteTimeout gin.HandlerFunc,
+	requestLogger gin.HandlerFunc,
+	jsonNamingMiddleware gin.HandlerFunc,
+	timestampMiddleware gin.HandlerFunc,
+	confirmationMiddleware gin.HandlerFunc,
+	maintenanceMiddleware gin.HandlerFunc,
+) *gin.Engine {
+	router := gin.New()
+	router.Use(requestLogger, jsonNamingMiddleware, timestampMiddleware, gin.Recovery(), jsonContentTypeMiddleware)
+
+	admin := router.Group("/api/admin")
+	admin.Use(authMiddleware, adminMiddleware, passwordChangeMiddleware)
+	registerAdminRoutes(admin, userController, taskController, apiKeyController, maintenanceController, heavyRouteTimeout, confirmationMiddleware, maintenanceMiddleware)
+
+	return router
+}
+
+// registerAdminRoutes adds the admin endpoints to group, which must already
+// require an authenticated admin. Destructive endpoints sit behind
+// confirmationMiddleware, and all but the maintenance toggle behind
+// maintenanceMiddleware so the mode can always be switched off again.
+func registerAdminRoutes(
+	group *gin.RouterGroup,
+	userController controllers.UserController,
+	taskController controllers.TaskController,
+	apiKeyController controllers.APIKeyController,
+	maintenanceController controllers.MaintenanceController,
+	heavyRouteTimeout gin.HandlerFunc,
+	confirmationMiddleware gin.HandlerFunc,
+	maintenanceMiddleware gin.HandlerFunc,
+) {
+	group.POST("/maintenance", maintenanceController.SetMaintenance)
+
+	admin := group.Group("", maintenanceMiddleware)
+	admin.POST("/confirm", userController.IssueConfirmation)
+	admin.GET("/users", userController.GetAllUsers)
+	admin.GET("/users/search", userController.SearchUsers)
+	admin.POST("/users/bulk", userController.BulkRegister)
+	admin.GET("/users/with-overdue", heavyRouteTimeout, taskController.GetUsersWithOverdueTasks)
+	admin.POST("/invites", userController.CreateInvite)
+	admin.PUT("/users/:id/password", userController.ResetPassword)
+	admin.POST("/users/:id/impersonate", userController.Impersonate)
+	admin.POST("/users/:id/restore", userController.RestoreUser)
+	admin.GET("/users/:id/stats", heavyRouteTimeout, taskController.GetUserTaskStats)
+	admin.GET("/tasks", heavyRouteTimeout, taskController.GetAllTasks)
+	admin.GET("/tasks/unassigned", taskController.GetUnassignedTasks)
+	admin.POST("/tasks/:id/force-complete", taskController.ForceCompleteTask)
+	admin.POST("/api-keys", apiKeyController.CreateAPIKey)
+	admin.DELETE("/api-keys/:id", confirmationMiddleware, apiKeyController.RevokeAPIKey)
+}
@@ -21,6 +21,16 @@ func (m *MockUserController) Register(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, gin.H{"message": "User registered successfully"})
 }
 
+func (m *MockUserController) BulkRegister(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Bulk user creation processed"})
+}
+
+func (m *MockUserController) CreateInvite(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Invite created successfully"})
+}
+
 func (m *MockUserController) Login(ctx *gin.Context) {
 	m.Called(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Login successful"})
@@ -31,6 +41,66 @@ func (m *MockUserController) GetAllUsers(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Users retrieved successfully"})
 }
 
+func (m *MockUserController) SearchUsers(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Users retrieved successfully"})
+}
+
+func (m *MockUserController) ExportMe(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"profile": gin.H{}, "tasks": []interface{}{}, "task_count": 0})
+}
+
+func (m *MockUserController) DeleteMe(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+}
+
+func (m *MockUserController) RestoreUser(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "User restored successfully"})
+}
+
+func (m *MockUserController) GetUserByID(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "User retrieved successfully"})
+}
+
+func (m *MockUserController) GetMe(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "User retrieved successfully"})
+}
+
+func (m *MockUserController) UpdateProfile(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
+}
+
+func (m *MockUserController) ChangePassword(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+func (m *MockUserController) RevokeSessions(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Sessions revoked successfully"})
+}
+
+func (m *MockUserController) IssueConfirmation(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Confirmation token issued"})
+}
+
+func (m *MockUserController) Impersonate(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Impersonation token issued"})
+}
+
+func (m *MockUserController) ResetPassword(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
 // MockTaskController is a mock implementation of the TaskController
 type MockTaskController struct {
 	mock.Mock
@@ -46,26 +116,196 @@ func (m *MockTaskController) GetTasksByUserID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
 }
 
+func (m *MockTaskController) GetSharedWithMe(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) PreviewFilter(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Filter previewed successfully"})
+}
+
+func (m *MockTaskController) CountTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks counted successfully"})
+}
+
+func (m *MockTaskController) GetUpcomingTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) SearchTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) GetSharedTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) GetTaskTags(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tags retrieved successfully"})
+}
+
+func (m *MockTaskController) GetUsersWithOverdueTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Users retrieved successfully"})
+}
+
+func (m *MockTaskController) GetUnassignedTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) GetAgenda(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Agenda retrieved successfully"})
+}
+
+func (m *MockTaskController) GetTimeReport(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Time report retrieved successfully"})
+}
+
+func (m *MockTaskController) GetUserTaskStats(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task statistics retrieved successfully"})
+}
+
+func (m *MockTaskController) GetCompleted(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Completed tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) GetActivity(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Activity retrieved successfully"})
+}
+
+func (m *MockTaskController) BatchGetTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks retrieved successfully"})
+}
+
+func (m *MockTaskController) ReorderTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks reordered successfully"})
+}
+
+func (m *MockTaskController) BulkTagTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tasks tagged successfully"})
+}
+
 func (m *MockTaskController) GetTaskByID(ctx *gin.Context) {
 	m.Called(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Task retrieved successfully"})
 }
 
+func (m *MockTaskController) GetTaskHistory(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task history retrieved successfully"})
+}
+
+func (m *MockTaskController) CloneTask(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Task cloned successfully"})
+}
+
+func (m *MockTaskController) TransferTask(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task transferred successfully"})
+}
+
+func (m *MockTaskController) ShareTask(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task shared successfully"})
+}
+
+func (m *MockTaskController) UnshareTask(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task unshared successfully"})
+}
+
+func (m *MockTaskController) ToggleChecklistItem(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Checklist item updated successfully"})
+}
+
 func (m *MockTaskController) UpdateTask(ctx *gin.Context) {
 	m.Called(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Task updated successfully"})
 }
 
+func (m *MockTaskController) ForceCompleteTask(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task completed successfully"})
+}
+
 func (m *MockTaskController) DeleteTask(ctx *gin.Context) {
 	m.Called(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
 
+func (m *MockTaskController) DeleteCompletedTasks(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Completed tasks deleted successfully"})
+}
+
 func (m *MockTaskController) GetAllTasks(ctx *gin.Context) {
 	m.Called(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "All tasks retrieved successfully"})
 }
 
+// MockAPIKeyController is a mock implementation of the APIKeyController
+type MockAPIKeyController struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyController) CreateAPIKey(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusCreated, gin.H{"message": "API key created successfully"})
+}
+
+func (m *MockAPIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+// MockHealthController is a mock implementation of the HealthController
+type MockHealthController struct {
+	mock.Mock
+}
+
+func (m *MockHealthController) Liveness(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "ok"})
+}
+
+func (m *MockHealthController) Readiness(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusServiceUnavailable, gin.H{"message": "not ready"})
+}
+
+func (m *MockHealthController) Version(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// MockMaintenanceController is a mock implementation of the MaintenanceController
+type MockMaintenanceController struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceController) SetMaintenance(ctx *gin.Context) {
+	m.Called(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "maintenance mode enabled"})
+}
+
 // Mock middlewares
 func MockAuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -80,12 +320,124 @@ func MockAdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+func MockAPIKeyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("X-API-Key") == "" {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.Set("service", "mockService")
+		ctx.Next()
+	}
+}
+
+func MockJSONContentTypeMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next() // Accept any content type
+	}
+}
+
+// MockCurrentUserMiddleware marks the request so tests can see it ran
+func MockCurrentUserMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("X-Current-User-Loaded", "true")
+		ctx.Next()
+	}
+}
+
+// MockCORSMiddleware answers preflight requests with 204
+func MockCORSMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Method == http.MethodOptions {
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// MockPasswordChangeMiddleware blocks writes when the X-Must-Change-Password header is set
+func MockPasswordChangeMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Method != http.MethodGet && ctx.GetHeader("X-Must-Change-Password") != "" {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// MockFreshTokenMiddleware rejects requests carrying the X-Stale-Token header
+func MockFreshTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("X-Stale-Token") != "" {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// MockTimeoutMiddleware marks the request so tests can see it ran
+func MockTimeoutMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("X-Timeout-Applied", "true")
+		ctx.Next()
+	}
+}
+
+// MockJSONNamingMiddleware passes every request through
+func MockJSONNamingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+	}
+}
+
+func MockTimestampMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+	}
+}
+
+func MockConfirmationMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("X-Confirmation-Token") == "" {
+			ctx.AbortWithStatus(http.StatusPreconditionRequired)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// MockMaintenanceMiddleware blocks requests sent with X-Maintenance, standing
+// in for maintenance mode being switched on
+func MockMaintenanceMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("X-Maintenance") != "" {
+			ctx.AbortWithStatus(http.StatusServiceUnavailable)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// MockRequestLogger marks every request so tests can see it ran
+func MockRequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("X-Request-Logged", "true")
+		ctx.Next()
+	}
+}
+
 // RouterTestSuite groups all router-related tests
 type RouterTestSuite struct {
 	suite.Suite
-	mockUserController *MockUserController
-	mockTaskController *MockTaskController
-	router             *gin.Engine
+	mockUserController        *MockUserController
+	mockTaskController        *MockTaskController
+	mockAPIKeyController      *MockAPIKeyController
+	mockHealthController      *MockHealthController
+	mockMaintenanceController *MockMaintenanceController
+	router                    *gin.Engine
 }
 
 // SetupSuite runs once before all tests
@@ -97,7 +449,78 @@ func (suite *RouterTestSuite) SetupSuite() {
 func (suite *RouterTestSuite) SetupTest() {
 	suite.mockUserController = new(MockUserController)
 	suite.mockTaskController = new(MockTaskController)
-	suite.router = SetupRouter(suite.mockUserController, suite.mockTaskController, MockAuthMiddleware(), MockAdminMiddleware())
+	suite.mockAPIKeyController = new(MockAPIKeyController)
+	suite.mockHealthController = new(MockHealthController)
+	suite.mockMaintenanceController = new(MockMaintenanceController)
+	suite.router = SetupRouter(
+		suite.mockUserController,
+		suite.mockTaskController,
+		suite.mockAPIKeyController,
+		suite.mockHealthController,
+		suite.mockMaintenanceController,
+		MockAuthMiddleware(),
+		MockAdminMiddleware(),
+		MockAPIKeyMiddleware(),
+		MockJSONContentTypeMiddleware(),
+		MockCurrentUserMiddleware(),
+		MockPasswordChangeMiddleware(),
+		MockFreshTokenMiddleware(),
+		MockTimeoutMiddleware(),
+		MockRequestLogger(),
+		MockCORSMiddleware(),
+		MockJSONNamingMiddleware(),
+		MockTimestampMiddleware(),
+		MockConfirmationMiddleware(),
+		MockMaintenanceMiddleware(),
+		false,
+	)
+}
+
+// Test Liveness Route
+func (suite *RouterTestSuite) TestLivenessRoute() {
+	suite.mockHealthController.On("Liveness", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), "true", resp.Header().Get("X-Request-Logged"))
+	suite.mockHealthController.AssertExpectations(suite.T())
+}
+
+// Test CORS preflight is answered before routing and authentication
+func (suite *RouterTestSuite) TestCORSPreflight() {
+	req, _ := http.NewRequest(http.MethodOptions, "/api/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTasksByUserID", mock.Anything)
+}
+
+// Test Readiness Route
+func (suite *RouterTestSuite) TestReadinessRoute() {
+	suite.mockHealthController.On("Readiness", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.Code)
+	suite.mockHealthController.AssertExpectations(suite.T())
+}
+
+// Test Version Route is public
+func (suite *RouterTestSuite) TestVersionRoute() {
+	suite.mockHealthController.On("Version", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/version", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockHealthController.AssertExpectations(suite.T())
 }
 
 // Test Register Route
@@ -124,40 +547,646 @@ func (suite *RouterTestSuite) TestLoginRoute() {
 	suite.mockUserController.AssertExpectations(suite.T())
 }
 
-// Test Create Task Route
-func (suite *RouterTestSuite) TestCreateTaskRoute() {
-	suite.mockTaskController.On("CreateTask", mock.Anything).Return().Once()
+// Test Get User By ID Route
+func (suite *RouterTestSuite) TestGetUserByIDRoute() {
+	suite.mockUserController.On("GetUserByID", mock.Anything).Return().Once()
 
-	req, _ := http.NewRequest(http.MethodPost, "/api/tasks", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/api/users/123", nil)
 	resp := httptest.NewRecorder()
 	suite.router.ServeHTTP(resp, req)
 
-	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
-	suite.mockTaskController.AssertExpectations(suite.T())
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
 }
 
-// Test Get Tasks By User ID Route
-func (suite *RouterTestSuite) TestGetTasksByUserIDRoute() {
-	suite.mockTaskController.On("GetTasksByUserID", mock.Anything).Return().Once()
+// Test Admin Get All Users Route
+func (suite *RouterTestSuite) TestAdminGetAllUsersRoute() {
+	suite.mockUserController.On("GetAllUsers", mock.Anything).Return().Once()
 
-	req, _ := http.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/users", nil)
 	resp := httptest.NewRecorder()
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusOK, resp.Code)
-	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockUserController.AssertExpectations(suite.T())
 }
 
-// Test Get Task By ID Route
-func (suite *RouterTestSuite) TestGetTaskByIDRoute() {
-	suite.mockTaskController.On("GetTaskByID", mock.Anything).Return().Once()
+// Test Admin Search Users Route
+func (suite *RouterTestSuite) TestAdminSearchUsersRoute() {
+	suite.mockUserController.On("SearchUsers", mock.Anything).Return().Once()
 
-	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/123", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/users/search?q=doe", nil)
 	resp := httptest.NewRecorder()
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusOK, resp.Code)
-	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Admin Bulk Register Route
+func (suite *RouterTestSuite) TestAdminBulkRegisterRoute() {
+	suite.mockUserController.On("BulkRegister", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/users/bulk", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Admin Create Invite Route
+func (suite *RouterTestSuite) TestAdminCreateInviteRoute() {
+	suite.mockUserController.On("CreateInvite", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/invites", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Get Me Route
+func (suite *RouterTestSuite) TestGetMeRoute() {
+	suite.mockUserController.On("GetMe", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/me", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), "true", resp.Header().Get("X-Current-User-Loaded"))
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Activity Route
+func (suite *RouterTestSuite) TestGetActivityRoute() {
+	suite.mockTaskController.On("GetActivity", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/me/activity?page=2", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Completed Tasks Route
+func (suite *RouterTestSuite) TestGetCompletedRoute() {
+	suite.mockTaskController.On("GetCompleted", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/me/completed?from=2024-05-06&to=2024-05-12", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Update Profile Route
+func (suite *RouterTestSuite) TestUpdateProfileRoute() {
+	suite.mockUserController.On("UpdateProfile", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/me", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Change Password Route is reachable while a password change is pending
+func (suite *RouterTestSuite) TestChangePasswordRoute() {
+	suite.mockUserController.On("ChangePassword", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/me/password", nil)
+	req.Header.Set("X-Must-Change-Password", "true")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Change Password Route requires a fresh token
+func (suite *RouterTestSuite) TestChangePasswordRoute_StaleToken() {
+	req, _ := http.NewRequest(http.MethodPut, "/api/me/password", nil)
+	req.Header.Set("X-Stale-Token", "true")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	suite.mockUserController.AssertNotCalled(suite.T(), "ChangePassword", mock.Anything)
+}
+
+// Test protected writes are blocked while a password change is pending
+func (suite *RouterTestSuite) TestCreateTaskRoute_PasswordChangePending() {
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks", nil)
+	req.Header.Set("X-Must-Change-Password", "true")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, resp.Code)
+	suite.mockTaskController.AssertNotCalled(suite.T(), "CreateTask", mock.Anything)
+}
+
+// Test Revoke Sessions Route
+func (suite *RouterTestSuite) TestRevokeSessionsRoute() {
+	suite.mockUserController.On("RevokeSessions", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/me/revoke-sessions", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Export Me Route
+func (suite *RouterTestSuite) TestExportMeRoute() {
+	suite.mockUserController.On("ExportMe", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/me/export", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Delete Me Route
+func (suite *RouterTestSuite) TestDeleteMeRoute() {
+	suite.mockUserController.On("DeleteMe", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/me", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Delete Me Route requires a fresh token
+func (suite *RouterTestSuite) TestDeleteMeRoute_StaleToken() {
+	req, _ := http.NewRequest(http.MethodDelete, "/api/me", nil)
+	req.Header.Set("X-Stale-Token", "true")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	suite.mockUserController.AssertNotCalled(suite.T(), "DeleteMe", mock.Anything)
+}
+
+// Test Admin Restore User Route
+func (suite *RouterTestSuite) TestAdminRestoreUserRoute() {
+	suite.mockUserController.On("RestoreUser", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/users/123/restore", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Admin Impersonate Route
+func (suite *RouterTestSuite) TestAdminImpersonateRoute() {
+	suite.mockUserController.On("Impersonate", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/users/123/impersonate", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Admin Unassigned Tasks Route
+func (suite *RouterTestSuite) TestAdminUnassignedTasksRoute() {
+	suite.mockTaskController.On("GetUnassignedTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/tasks/unassigned", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetAllTasks", mock.Anything)
+}
+
+// Test Admin Users With Overdue Tasks Route is not taken for a user ID
+func (suite *RouterTestSuite) TestAdminUsersWithOverdueRoute() {
+	suite.mockTaskController.On("GetUsersWithOverdueTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/users/with-overdue", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Admin Force Complete Task Route
+func (suite *RouterTestSuite) TestAdminForceCompleteTaskRoute() {
+	suite.mockTaskController.On("ForceCompleteTask", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/tasks/123/force-complete", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test that a separate admin listener takes the admin routes off the public router
+func (suite *RouterTestSuite) TestSeparateAdminRouter() {
+	publicRouter := SetupRouter(
+		suite.mockUserController,
+		suite.mockTaskController,
+		suite.mockAPIKeyController,
+		suite.mockHealthController,
+		suite.mockMaintenanceController,
+		MockAuthMiddleware(),
+		MockAdminMiddleware(),
+		MockAPIKeyMiddleware(),
+		MockJSONContentTypeMiddleware(),
+		MockCurrentUserMiddleware(),
+		MockPasswordChangeMiddleware(),
+		MockFreshTokenMiddleware(),
+		MockTimeoutMiddleware(),
+		MockRequestLogger(),
+		MockCORSMiddleware(),
+		MockJSONNamingMiddleware(),
+		MockTimestampMiddleware(),
+		MockConfirmationMiddleware(),
+		MockMaintenanceMiddleware(),
+		true,
+	)
+	adminRouter := SetupAdminRouter(
+		suite.mockUserController,
+		suite.mockTaskController,
+		suite.mockAPIKeyController,
+		suite.mockMaintenanceController,
+		MockAuthMiddleware(),
+		MockAdminMiddleware(),
+		MockJSONContentTypeMiddleware(),
+		MockPasswordChangeMiddleware(),
+		MockTimeoutMiddleware(),
+		MockRequestLogger(),
+		MockJSONNamingMiddleware(),
+		MockTimestampMiddleware(),
+		MockConfirmationMiddleware(),
+		MockMaintenanceMiddleware(),
+	)
+	suite.mockUserController.On("GetAllUsers", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	resp := httptest.NewRecorder()
+	publicRouter.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+
+	resp = httptest.NewRecorder()
+	adminRouter.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/tasks", nil)
+	resp = httptest.NewRecorder()
+	adminRouter.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test that API routes are blocked while maintenance mode is on
+func (suite *RouterTestSuite) TestMaintenance_BlocksRoutes() {
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/login"},
+		{http.MethodPut, "/api/me/password"},
+		{http.MethodGet, "/api/tasks"},
+		{http.MethodGet, "/api/admin/users"},
+		{http.MethodGet, "/api/service/tasks"},
+	} {
+		req, _ := http.NewRequest(route.method, route.path, nil)
+		req.Header.Set("X-Maintenance", "on")
+		req.Header.Set("X-API-Key", "key")
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+
+		assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.Code, route.path)
+	}
+	suite.mockUserController.AssertNotCalled(suite.T(), "Login", mock.Anything)
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTasksByUserID", mock.Anything)
+}
+
+// Test that API routes are served while maintenance mode is off
+func (suite *RouterTestSuite) TestMaintenance_AllowsRoutesWhenOff() {
+	suite.mockTaskController.On("GetTasksByUserID", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test that health probes and the maintenance toggle stay reachable while maintenance mode is on
+func (suite *RouterTestSuite) TestMaintenance_ExemptRoutes() {
+	suite.mockHealthController.On("Liveness", mock.Anything).Return().Once()
+	suite.mockMaintenanceController.On("SetMaintenance", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set("X-Maintenance", "on")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/admin/maintenance", nil)
+	req.Header.Set("X-Maintenance", "on")
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	suite.mockHealthController.AssertExpectations(suite.T())
+	suite.mockMaintenanceController.AssertExpectations(suite.T())
+}
+
+// Test Admin Reset Password Route
+func (suite *RouterTestSuite) TestAdminResetPasswordRoute() {
+	suite.mockUserController.On("ResetPassword", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/123/password", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Count Tasks Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestCountTasksRoute() {
+	suite.mockTaskController.On("CountTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/count?status=pending", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Preview Filter Route
+func (suite *RouterTestSuite) TestPreviewFilterRoute() {
+	suite.mockTaskController.On("PreviewFilter", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/preview-filter", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Shared With Me Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestSharedWithMeRoute() {
+	suite.mockTaskController.On("GetSharedWithMe", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/shared-with-me", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Agenda Route
+func (suite *RouterTestSuite) TestAgendaRoute() {
+	suite.mockTaskController.On("GetAgenda", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/me/agenda", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Time Report Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestTimeReportRoute() {
+	suite.mockTaskController.On("GetTimeReport", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/time-report", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Shared Tasks Route
+func (suite *RouterTestSuite) TestGetSharedTasksRoute() {
+	suite.mockTaskController.On("GetSharedTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/shared", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Upcoming Tasks Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestGetUpcomingTasksRoute() {
+	suite.mockTaskController.On("GetUpcomingTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/upcoming?limit=3", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Search Tasks Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestSearchTasksRoute() {
+	suite.mockTaskController.On("SearchTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/search?q=report", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Get Task Tags Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestGetTaskTagsRoute() {
+	suite.mockTaskController.On("GetTaskTags", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/tags", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything)
+}
+
+// Test Admin User Task Stats Route
+func (suite *RouterTestSuite) TestAdminUserTaskStatsRoute() {
+	suite.mockTaskController.On("GetUserTaskStats", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/users/123/stats", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), "true", resp.Header().Get("X-Timeout-Applied"))
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Create Task Route
+func (suite *RouterTestSuite) TestCreateTaskRoute() {
+	suite.mockTaskController.On("CreateTask", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Get Tasks By User ID Route
+func (suite *RouterTestSuite) TestGetTasksByUserIDRoute() {
+	suite.mockTaskController.On("GetTasksByUserID", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Get Task By ID Route
+func (suite *RouterTestSuite) TestGetTaskByIDRoute() {
+	suite.mockTaskController.On("GetTaskByID", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/123", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Get Task History Route
+func (suite *RouterTestSuite) TestGetTaskHistoryRoute() {
+	suite.mockTaskController.On("GetTaskHistory", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/123/history", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Clone Task Route
+func (suite *RouterTestSuite) TestCloneTaskRoute() {
+	suite.mockTaskController.On("CloneTask", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/123/clone", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Batch Get Tasks Route
+func (suite *RouterTestSuite) TestBatchGetTasksRoute() {
+	suite.mockTaskController.On("BatchGetTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/batch-get", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Bulk Tag Tasks Route
+func (suite *RouterTestSuite) TestBulkTagTasksRoute() {
+	suite.mockTaskController.On("BulkTagTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/bulk-tag", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Reorder Tasks Route is not taken for a task ID
+func (suite *RouterTestSuite) TestReorderTasksRoute() {
+	suite.mockTaskController.On("ReorderTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/tasks/reorder", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Transfer Task Route
+func (suite *RouterTestSuite) TestTransferTaskRoute() {
+	suite.mockTaskController.On("TransferTask", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/123/transfer", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Share and Unshare Task Routes
+func (suite *RouterTestSuite) TestShareTaskRoutes() {
+	suite.mockTaskController.On("ShareTask", mock.Anything).Return().Once()
+	suite.mockTaskController.On("UnshareTask", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/123/share", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/tasks/123/share/456", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
+// Test Toggle Checklist Item Route
+func (suite *RouterTestSuite) TestToggleChecklistItemRoute() {
+	suite.mockTaskController.On("ToggleChecklistItem", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/tasks/123/checklist/0", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
 }
 
 // Test Update Task Route
@@ -184,6 +1213,19 @@ func (suite *RouterTestSuite) TestDeleteTaskRoute() {
 	suite.mockTaskController.AssertExpectations(suite.T())
 }
 
+// Test Delete Completed Tasks Route takes precedence over /tasks/:id
+func (suite *RouterTestSuite) TestDeleteCompletedTasksRoute() {
+	suite.mockTaskController.On("DeleteCompletedTasks", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/tasks/completed?confirm=true", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+	suite.mockTaskController.AssertNotCalled(suite.T(), "DeleteTask", mock.Anything)
+}
+
 // Test Get All Tasks Route
 func (suite *RouterTestSuite) TestGetAllTasksRoute() {
 	suite.mockTaskController.On("GetAllTasks", mock.Anything).Return().Once()
@@ -196,6 +1238,71 @@ func (suite *RouterTestSuite) TestGetAllTasksRoute() {
 	suite.mockTaskController.AssertExpectations(suite.T())
 }
 
+// Test Create API Key Route
+func (suite *RouterTestSuite) TestCreateAPIKeyRoute() {
+	suite.mockAPIKeyController.On("CreateAPIKey", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/api-keys", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	suite.mockAPIKeyController.AssertExpectations(suite.T())
+}
+
+// Test Revoke API Key Route
+func (suite *RouterTestSuite) TestRevokeAPIKeyRoute() {
+	suite.mockAPIKeyController.On("RevokeAPIKey", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/admin/api-keys/123", nil)
+	req.Header.Set("X-Confirmation-Token", "confirmed")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockAPIKeyController.AssertExpectations(suite.T())
+}
+
+// Test Revoke API Key Route without a confirmation token
+func (suite *RouterTestSuite) TestRevokeAPIKeyRoute_Unconfirmed() {
+	req, _ := http.NewRequest(http.MethodDelete, "/api/admin/api-keys/123", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusPreconditionRequired, resp.Code)
+	suite.mockAPIKeyController.AssertNotCalled(suite.T(), "RevokeAPIKey", mock.Anything)
+}
+
+// Test Admin Confirm Route
+func (suite *RouterTestSuite) TestAdminConfirmRoute() {
+	suite.mockUserController.On("IssueConfirmation", mock.Anything).Return().Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/confirm", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	suite.mockUserController.AssertExpectations(suite.T())
+}
+
+// Test Service Tasks Route requires an API key
+func (suite *RouterTestSuite) TestServiceTasksRoute() {
+	req, _ := http.NewRequest(http.MethodGet, "/api/service/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+
+	suite.mockTaskController.On("GetAllTasks", mock.Anything).Return().Once()
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/service/tasks", nil)
+	req.Header.Set("X-API-Key", "tm_key")
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	suite.mockTaskController.AssertExpectations(suite.T())
+}
+
 // Run the test suite
 func TestRouterTestSuite(t *testing.T) {
 	suite.Run(t, new(RouterTestSuite))
@@ -2,6 +2,7 @@ package Domain
 
 import (
 	"context"
+	"encoding/xml"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,6 +14,28 @@ const (
 	RoleAdmin      = "admin"
 )
 
+const (
+	APIKeyCollection = "api_keys"
+)
+
+const (
+	InviteCollection = "invites"
+)
+
+const (
+	AuditCollection = "audit_log"
+)
+
+const (
+	TaskHistoryCollection = "task_history"
+)
+
+// Audit actions
+const (
+	AuditUserImpersonated   = "user_impersonated"
+	AuditTaskForceCompleted = "task_force_completed"
+)
+
 const (
 	TaskCollection   = "tasks"
 	StatusPending    = "pending"
@@ -20,84 +43,621 @@ const (
 	StatusCompleted  = "completed"
 )
 
-// User represents the core user entity
+// IsValidStatus reports whether status is one of the known task statuses
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusInProgress, StatusCompleted:
+		return true
+	}
+	return false
+}
+
+// IsValidMetadataKey reports whether key can name a task metadata field:
+// non-empty and made of ASCII letters, digits, '-' and '_', so it is safe to
+// use in a query path
+func IsValidMetadataKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Share permissions grant a user read access to a task, or read and write
+const (
+	SharePermissionRead  = "read"
+	SharePermissionWrite = "write"
+)
+
+// Relations select which of a user's tasks a listing covers: the tasks they
+// own, the tasks assigned to them, or both
+const (
+	RelationOwned    = "owned"
+	RelationAssigned = "assigned"
+	RelationAll      = "all"
+)
+
+// IsValidRelation reports whether relation is one of the known task relations
+func IsValidRelation(relation string) bool {
+	switch relation {
+	case RelationOwned, RelationAssigned, RelationAll:
+		return true
+	}
+	return false
+}
+
+// TaskSortOrder lists tasks by their manual position, as set by reordering.
+// Without a sort, tasks are listed in creation order.
+const TaskSortOrder = "order"
+
+// TaskSortNewest lists tasks by creation time, newest first. It is used
+// internally and not accepted as a query value.
+const TaskSortNewest = "newest"
+
+// User represents the core user entity. MustChangePassword is set by an
+// admin password reset and blocks mutating requests until the user picks a
+// new password. PasswordHistory keeps the hashes of recent previous
+// passwords, newest first. DeletedAt marks a soft-deleted account.
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name" json:"name"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"-"`
-	Role      string             `bson:"role" json:"role"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id" xml:"id"`
+	Name               string             `bson:"name" json:"name" xml:"name"`
+	Email              string             `bson:"email" json:"email" xml:"email"`
+	Password           string             `bson:"password,omitempty" json:"-" xml:"-"`
+	Role               string             `bson:"role" json:"role" xml:"role"`
+	AvatarURL          string             `bson:"avatar_url,omitempty" json:"avatar_url,omitempty" xml:"avatar_url,omitempty"`
+	MustChangePassword bool               `bson:"must_change_password" json:"must_change_password" xml:"must_change_password"`
+	PasswordHistory    []string           `bson:"password_history,omitempty" json:"-" xml:"-"`
+	TokenVersion       int                `bson:"token_version" json:"-" xml:"-"`
+	CreatedAt          time.Time          `bson:"created_at" json:"created_at" xml:"created_at"`
+	UpdatedAt          time.Time          `bson:"updated_at" json:"updated_at" xml:"updated_at"`
+	DeletedAt          *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty" xml:"deleted_at,omitempty"`
 }
 
-// Task represents the core task entity
+// Task represents the core task entity. DependsOn lists tasks that must be
+// completed before this one can be. Checklist holds optional sub-steps.
+// SharedWith lists the other users who may read the task; those also in
+// SharedEditors may change it too. Only the owner and admins manage shares.
 type Task struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title       string             `bson:"title" json:"title"`
-	Description string             `bson:"description" json:"description"`
-	DueDate     time.Time          `bson:"due_date" json:"due_date"`
-	Status      string             `bson:"status" json:"status"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id" xml:"id"`
+	Title         string               `bson:"title" json:"title" xml:"title"`
+	Description   string               `bson:"description" json:"description" xml:"description"`
+	DueDate       time.Time            `bson:"due_date" json:"due_date" xml:"due_date"`
+	RemindAt      *time.Time           `bson:"remind_at" json:"remind_at,omitempty" xml:"remind_at,omitempty"`
+	Status        string               `bson:"status" json:"status" xml:"status"`
+	UserID        primitive.ObjectID   `bson:"user_id" json:"user_id" xml:"user_id"`
+	AssigneeID    *primitive.ObjectID  `bson:"assignee_id" json:"assignee_id,omitempty" xml:"assignee_id,omitempty"`
+	SharedWith    []primitive.ObjectID `bson:"shared_with" json:"shared_with,omitempty" xml:"shared_with,omitempty"`
+	SharedEditors []primitive.ObjectID `bson:"shared_editors" json:"shared_editors,omitempty" xml:"shared_editors,omitempty"`
+	DependsOn     []primitive.ObjectID `bson:"depends_on" json:"depends_on,omitempty" xml:"depends_on,omitempty"`
+	Tags          []string             `bson:"tags" json:"tags,omitempty" xml:"tags,omitempty"`
+	Checklist     []ChecklistItem      `bson:"checklist" json:"checklist,omitempty" xml:"checklist,omitempty"`
+	// Metadata holds custom key-value fields. encoding/xml cannot encode
+	// maps, so it is only sent in JSON responses.
+	Metadata map[string]string `bson:"metadata" json:"metadata,omitempty" xml:"-"`
+	// Order is the task's position in the owner's manually ordered list.
+	// Tasks that were never reordered share position zero.
+	Order int `bson:"order" json:"order" xml:"order"`
+	// EstimatedMinutes and ActualMinutes track the effort planned for and
+	// spent on the task
+	EstimatedMinutes int       `bson:"estimated_minutes" json:"estimated_minutes,omitempty" xml:"estimated_minutes,omitempty"`
+	ActualMinutes    int       `bson:"actual_minutes" json:"actual_minutes,omitempty" xml:"actual_minutes,omitempty"`
+	CreatedAt        time.Time `bson:"created_at" json:"created_at" xml:"created_at"`
+	UpdatedAt        time.Time `bson:"updated_at" json:"updated_at" xml:"updated_at"`
+	// CompletedAt is set by the use case when the task becomes completed and
+	// cleared if it ever stops being so. Tasks completed before it was
+	// recorded have none.
+	CompletedAt *time.Time `bson:"completed_at" json:"completed_at,omitempty" xml:"completed_at,omitempty"`
+	// Score is the text search relevance, set only on search results
+	Score float64 `bson:"score,omitempty" json:"score,omitempty" xml:"score,omitempty"`
+	// IsOverdue is computed by the use case when the task is read and is
+	// never stored
+	IsOverdue bool `bson:"-" json:"is_overdue" xml:"is_overdue"`
+	// OwnerDeleted is set while the owner's account is soft-deleted, so the
+	// task is hidden from everyone but admins until it is restored or purged
+	OwnerDeleted bool `bson:"owner_deleted,omitempty" json:"-" xml:"-"`
+}
+
+// OverdueAt reports whether the task is unfinished and its due date is
+// before now. A task without a due date is never overdue.
+func (t *Task) OverdueAt(now time.Time) bool {
+	return !t.DueDate.IsZero() && t.DueDate.Before(now) && t.Status != StatusCompleted
+}
+
+// ChecklistItem is one step of a task's checklist
+type ChecklistItem struct {
+	Text string `bson:"text" json:"text" xml:"text"`
+	Done bool   `bson:"done" json:"done" xml:"done"`
+}
+
+// TaskOwner is the subset of a user's profile embedded in expanded task
+// listings
+type TaskOwner struct {
+	ID    primitive.ObjectID `json:"id" xml:"id"`
+	Name  string             `json:"name" xml:"name"`
+	Email string             `json:"email" xml:"email"`
+}
+
+// TaskWithOwner is a task with its owner's details inlined. User is nil when
+// the owning account no longer exists.
+type TaskWithOwner struct {
+	Task
+	User *TaskOwner `json:"user" xml:"user,omitempty"`
+}
+
+// TaskStats summarizes a user's tasks by status. Overdue counts unfinished
+// tasks whose due date has passed.
+type TaskStats struct {
+	Total      int64 `json:"total" xml:"total"`
+	Pending    int64 `json:"pending" xml:"pending"`
+	InProgress int64 `json:"in_progress" xml:"in_progress"`
+	Completed  int64 `json:"completed" xml:"completed"`
+	Overdue    int64 `json:"overdue" xml:"overdue"`
+}
+
+// OverdueCount is the number of overdue, unfinished tasks a user owns
+type OverdueCount struct {
+	UserID primitive.ObjectID `bson:"_id"`
+	Count  int64              `bson:"count"`
+}
+
+// UserOverdue is a user with the number of their overdue, unfinished tasks
+type UserOverdue struct {
+	TaskOwner
+	OverdueTasks int64 `json:"overdue_tasks" xml:"overdue_tasks"`
+}
+
+// StatusTime totals the effort recorded on a user's tasks in one status
+type StatusTime struct {
+	Status           string `json:"status" xml:"status"`
+	Tasks            int64  `json:"tasks" xml:"tasks"`
+	EstimatedMinutes int64  `json:"estimated_minutes" xml:"estimated_minutes"`
+	ActualMinutes    int64  `json:"actual_minutes" xml:"actual_minutes"`
+}
+
+// TimeReport compares estimated and actual minutes across a user's tasks,
+// overall and per status. ByStatus lists every status, in workflow order.
+type TimeReport struct {
+	EstimatedMinutes int64        `json:"estimated_minutes" xml:"estimated_minutes"`
+	ActualMinutes    int64        `json:"actual_minutes" xml:"actual_minutes"`
+	ByStatus         []StatusTime `json:"by_status" xml:"by_status>entry"`
+}
+
+// Activity event types
+const (
+	ActivityTaskCreated   = "task_created"
+	ActivityTaskCompleted = "task_completed"
+)
+
+// Agenda groups a user's unfinished tasks by due date. Today and ThisWeek
+// follow the calendar of the configured timezone, with weeks ending on
+// Sunday; tasks in one bucket are not repeated in a later one.
+type Agenda struct {
+	Overdue  []*Task `json:"overdue" xml:"overdue>task"`
+	Today    []*Task `json:"today" xml:"today>task"`
+	ThisWeek []*Task `json:"this_week" xml:"this_week>task"`
+	Later    []*Task `json:"later" xml:"later>task"`
+}
+
+// ActivityEvent is one entry of a user's activity timeline
+type ActivityEvent struct {
+	Type      string             `json:"type" xml:"type"`
+	TaskID    primitive.ObjectID `json:"task_id" xml:"task_id"`
+	TaskTitle string             `json:"task_title" xml:"task_title"`
+	At        time.Time          `json:"at" xml:"at"`
+}
+
+// APIKey represents a credential issued to a machine client. Only a hash of
+// the key is stored; the plain value is returned once on creation.
+type APIKey struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" xml:"id"`
+	Name      string             `bson:"name" json:"name" xml:"name"`
+	KeyHash   string             `bson:"key_hash" json:"-" xml:"-"`
+	Revoked   bool               `bson:"revoked" json:"revoked" xml:"revoked"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at" xml:"created_at"`
+	RevokedAt *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty" xml:"revoked_at,omitempty"`
+}
+
+// Invite is a single-use code an admin issues so someone can register while
+// registration is invite-only
+type Invite struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" xml:"id"`
+	Code      string             `bson:"code" json:"code" xml:"code"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by" xml:"created_by"`
+	Used      bool               `bson:"used" json:"used" xml:"used"`
+	UsedBy    string             `bson:"used_by,omitempty" json:"used_by,omitempty" xml:"used_by,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at" xml:"created_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty" xml:"used_at,omitempty"`
+}
+
+// AuditEntry records a sensitive action: who did it, to which record and
+// when
+type AuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" xml:"id"`
+	Action    string             `bson:"action" json:"action" xml:"action"`
+	ActorID   primitive.ObjectID `bson:"actor_id" json:"actor_id" xml:"actor_id"`
+	TargetID  primitive.ObjectID `bson:"target_id" json:"target_id" xml:"target_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at" xml:"created_at"`
+}
+
+// TaskRevision records one update of a task: who made it, when, the fields
+// it changed by JSON name, and the task as it was before and after
+type TaskRevision struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" xml:"id"`
+	TaskID    primitive.ObjectID `bson:"task_id" json:"task_id" xml:"task_id"`
+	ActorID   primitive.ObjectID `bson:"actor_id" json:"actor_id" xml:"actor_id"`
+	Changes   []string           `bson:"changes" json:"changes" xml:"changes>field"`
+	Before    *Task              `bson:"before" json:"before" xml:"before"`
+	After     *Task              `bson:"after" json:"after" xml:"after"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at" xml:"created_at"`
+}
+
+// Pagination selects one page of a listing. Page is 1-based; a zero PageSize
+// returns everything.
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+// Skip returns how many records precede the page
+func (p Pagination) Skip() int64 {
+	if p.Page <= 1 || p.PageSize <= 0 {
+		return 0
+	}
+	return int64(p.Page-1) * int64(p.PageSize)
+}
+
+// Limit returns the maximum number of records on the page, 0 meaning no limit
+func (p Pagination) Limit() int64 {
+	if p.PageSize <= 0 {
+		return 0
+	}
+	return int64(p.PageSize)
+}
+
+// UserFilter narrows user listings. Zero values mean "no constraint"; the
+// created_at bounds are inclusive. CreatedFromDay and CreatedToDay select
+// whole calendar days, which the use case resolves into CreatedFrom and
+// CreatedTo in its configured timezone. Soft-deleted users are skipped
+// unless IncludeDeleted is set.
+type UserFilter struct {
+	Role           string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	CreatedFromDay *time.Time
+	CreatedToDay   *time.Time
+	IncludeDeleted bool
+	Pagination     Pagination
+}
+
+// TaskFilter narrows task listings. Zero values mean "no constraint"; the
+// due date bounds are inclusive. Statuses matches tasks in any of the listed
+// statuses. DueOn selects a whole calendar day, which the
+// use case resolves into DueFrom/DueTo in its configured timezone. HasDueDate
+// keeps only tasks with (true) or without (false) a due date. Metadata keeps
+// only tasks whose metadata has every listed pair. Relation is one of the
+// Relation constants; empty means RelationOwned.
+type TaskFilter struct {
+	Relation   string
+	Statuses   []string
+	DueOn      *time.Time
+	DueFrom    *time.Time
+	DueTo      *time.Time
+	HasDueDate *bool
+	Metadata   map[string]string
+	Sort       string
+	Pagination Pagination
+}
+
+// CompletedFilter selects the tasks a user completed within a window. From
+// and To are inclusive instants; FromDay and ToDay are calendar days the use
+// case resolves in its configured timezone. A missing bound leaves that side
+// of the window open.
+type CompletedFilter struct {
+	From       *time.Time
+	To         *time.Time
+	FromDay    *time.Time
+	ToDay      *time.Time
+	Pagination Pagination
+}
+
+// Clock is the source of the current time for time-dependent rules, so they
+// can be tested against a controlled time
+type Clock interface {
+	Now() time.Time
 }
 
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	Create(ctx context.Context, user *User) (*User, error)
+	CreateMany(ctx context.Context, users []*User) ([]*User, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*User, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
-	GetAll(ctx context.Context) ([]*User, error)
+	GetAll(ctx context.Context, filter UserFilter) ([]*User, error)
+	Search(ctx context.Context, query string, page Pagination) ([]*User, error)
 	Update(ctx context.Context, user *User) error
+	IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	Restore(ctx context.Context, id primitive.ObjectID, deletedAfter time.Time) error
+	ListDeletedBefore(ctx context.Context, before time.Time) ([]primitive.ObjectID, error)
+	Purge(ctx context.Context, ids []primitive.ObjectID) (int64, error)
+}
+
+// InviteRepository defines the interface for invite data access
+type InviteRepository interface {
+	Create(ctx context.Context, invite *Invite) (*Invite, error)
+	Redeem(ctx context.Context, code, email string) error
+	Release(ctx context.Context, code, email string) error
+}
+
+// AuditRepository defines the interface for audit log access
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditEntry) error
+}
+
+// TaskHistoryRepository defines the interface for task revision access
+type TaskHistoryRepository interface {
+	Create(ctx context.Context, revision *TaskRevision) error
+	GetByTaskID(ctx context.Context, taskID primitive.ObjectID, page Pagination) ([]*TaskRevision, error)
 }
 
 // TaskRepository defines the interface for task data access
 type TaskRepository interface {
 	Create(ctx context.Context, task *Task) (*Task, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*Task, error)
-	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*Task, error)
-	GetAll(ctx context.Context) ([]*Task, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*Task, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID, filter TaskFilter) ([]*Task, error)
+	ForEachByUserID(ctx context.Context, userID primitive.ObjectID, fn func(*Task) error) error
+	GetUpcoming(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*Task, error)
+	GetCompleted(ctx context.Context, userID primitive.ObjectID, from, to *time.Time, page Pagination) ([]*Task, error)
+	Search(ctx context.Context, userID primitive.ObjectID, query string, page Pagination) ([]*Task, error)
+	GetRemindersDue(ctx context.Context, userID primitive.ObjectID, now time.Time, limit int64) ([]*Task, error)
+	DistinctTags(ctx context.Context, userID primitive.ObjectID) ([]string, error)
+	CountByUserID(ctx context.Context, userID primitive.ObjectID, filter TaskFilter) (int64, error)
+	LatestCreatedAt(ctx context.Context, userID primitive.ObjectID) (time.Time, error)
+	TitleTaken(ctx context.Context, userID primitive.ObjectID, title string, excludeID primitive.ObjectID) (bool, error)
+	StatsByUserID(ctx context.Context, userID primitive.ObjectID, now time.Time) (*TaskStats, error)
+	TimeReportByUserID(ctx context.Context, userID primitive.ObjectID) (*TimeReport, error)
+	CountOverdueByUser(ctx context.Context, now time.Time) ([]OverdueCount, error)
+	GetAll(ctx context.Context, page Pagination) ([]*Task, error)
+	GetAllVisible(ctx context.Context, page Pagination) ([]*Task, error)
+	GetUnassigned(ctx context.Context, page Pagination) ([]*Task, error)
+	GetSharedWith(ctx context.Context, userID primitive.ObjectID, page Pagination) ([]*Task, error)
 	Update(ctx context.Context, task *Task) error
+	UpdateFields(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) error
+	UpdateTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, add, remove []string) (int64, error)
+	Reorder(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (int64, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteCompletedByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	DeleteByUserIDs(ctx context.Context, userIDs []primitive.ObjectID) (int64, error)
+	SetOwnerDeleted(ctx context.Context, userID primitive.ObjectID, deleted bool) error
+}
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) (*APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*APIKey, error)
+	Revoke(ctx context.Context, id primitive.ObjectID) error
 }
 
 // UserUseCase defines the interface for user business logic
 type UserUseCase interface {
-	Register(ctx context.Context, user *User) (*User, error)
-	Login(ctx context.Context, email, password string) (*User, string, error)
-	GetAllUsers(ctx context.Context) ([]*User, error)
+	Register(ctx context.Context, user *User, inviteCode string) (*User, error)
+	CreateInvite(ctx context.Context, createdBy primitive.ObjectID) (*Invite, error)
+	BulkRegister(ctx context.Context, users []*User) ([]BulkUserResult, error)
+	Login(ctx context.Context, email, password string, rememberMe bool) (*User, string, error)
+	GetAllUsers(ctx context.Context, filter UserFilter) ([]*User, error)
+	SearchUsers(ctx context.Context, query string, page Pagination) ([]*User, error)
 	GetUserByID(ctx context.Context, id primitive.ObjectID) (*User, error)
 	UpdateUser(ctx context.Context, user *User) error
+	ChangePassword(ctx context.Context, id primitive.ObjectID, currentPassword, newPassword string) error
+	ResetPassword(ctx context.Context, id primitive.ObjectID, newPassword string) error
+	RevokeSessions(ctx context.Context, id primitive.ObjectID) error
+	Impersonate(ctx context.Context, adminID, userID primitive.ObjectID) (string, error)
+	IssueConfirmation(ctx context.Context, userID primitive.ObjectID) (string, error)
 	DeleteUser(ctx context.Context, id primitive.ObjectID) error
+	RestoreUser(ctx context.Context, id primitive.ObjectID) error
+	PurgeDeletedUsers(ctx context.Context) (int64, error)
+	ExportUserData(ctx context.Context, id primitive.ObjectID, exporter UserDataExporter) error
+}
+
+// UserDataExporter receives a user's data export piece by piece: the profile
+// first, then each of their tasks
+type UserDataExporter interface {
+	WriteProfile(user *User) error
+	WriteTask(task *Task) error
 }
 
 // TaskUseCase defines the interface for task business logic
 type TaskUseCase interface {
 	CreateTask(ctx context.Context, task *Task) (*Task, error)
-	GetTaskByID(ctx context.Context, id primitive.ObjectID) (*Task, error)
-	GetTasksByUserID(ctx context.Context, userID primitive.ObjectID) ([]*Task, error)
-	GetAllTasks(ctx context.Context) ([]*Task, error)
-	UpdateTask(ctx context.Context, task *Task) error
-	DeleteTask(ctx context.Context, id primitive.ObjectID) error
+	GetTaskByID(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool) (*Task, error)
+	GetTaskHistory(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, page Pagination) ([]*TaskRevision, error)
+	GetTasksByIDs(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (*BatchGetTasksResult, error)
+	BulkTagTasks(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, add, remove []string) (*BulkTagResult, error)
+	ReorderTasks(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (*ReorderResult, error)
+	CloneTask(ctx context.Context, id, userID primitive.ObjectID, overrides CloneTaskRequest) (*Task, error)
+	TransferTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, toUserID primitive.ObjectID) (*Task, error)
+	ShareTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, userID primitive.ObjectID, permission string) (*Task, error)
+	UnshareTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, userID primitive.ObjectID) (*Task, error)
+	ToggleChecklistItem(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, index int) (*Task, error)
+	GetTasksByUserID(ctx context.Context, userID primitive.ObjectID, filter TaskFilter) ([]*Task, error)
+	CountTasksByUserID(ctx context.Context, userID primitive.ObjectID, filter TaskFilter) (int64, error)
+	GetAgenda(ctx context.Context, userID primitive.ObjectID) (*Agenda, error)
+	SearchTasks(ctx context.Context, userID primitive.ObjectID, query string, page Pagination) ([]*Task, error)
+	GetUpcomingTasks(ctx context.Context, userID primitive.ObjectID, limit int) ([]*Task, error)
+	GetDueReminders(ctx context.Context, userID primitive.ObjectID, limit int) ([]*Task, error)
+	GetTagsByUserID(ctx context.Context, userID primitive.ObjectID) ([]string, error)
+	GetTaskStatsByUserID(ctx context.Context, userID primitive.ObjectID) (*TaskStats, error)
+	GetTimeReport(ctx context.Context, userID primitive.ObjectID) (*TimeReport, error)
+	GetActivityByUserID(ctx context.Context, userID primitive.ObjectID, page Pagination) ([]ActivityEvent, error)
+	GetCompletedTasks(ctx context.Context, userID primitive.ObjectID, filter CompletedFilter) ([]*Task, error)
+	PreviewTaskFilter(ctx context.Context, userID primitive.ObjectID, filter TaskFilter) (*FilterPreview, error)
+	GetAllTasks(ctx context.Context, page Pagination) ([]*Task, error)
+	GetAllTasksWithOwners(ctx context.Context, page Pagination) ([]*TaskWithOwner, error)
+	GetSharedTasks(ctx context.Context, page Pagination) ([]*Task, error)
+	GetTasksSharedWithMe(ctx context.Context, userID primitive.ObjectID, page Pagination) ([]*Task, error)
+	GetUnassignedTasks(ctx context.Context, page Pagination) ([]*Task, error)
+	GetUsersWithOverdueTasks(ctx context.Context) ([]*UserOverdue, error)
+	UpdateTask(ctx context.Context, task *Task, callerID primitive.ObjectID, asAdmin bool) error
+	DeleteTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin, force bool) error
+	ForceCompleteTask(ctx context.Context, id, adminID primitive.ObjectID) (*Task, error)
+	DeleteCompletedTasks(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
+
+// APIKeyUseCase defines the interface for API key business logic
+type APIKeyUseCase interface {
+	GenerateKey(ctx context.Context, name string) (*APIKey, string, error)
+	RevokeKey(ctx context.Context, id primitive.ObjectID) error
+	ValidateKey(ctx context.Context, rawKey string) (*APIKey, error)
 }
 
 // Request/Response DTOs
 type RegisterRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"required,oneof=admin user"`
+	Name      string `json:"name" binding:"required"`
+	Email     string `json:"email" binding:"required,email"`
+	Password  string `json:"password" binding:"required,min=6"`
+	Role      string `json:"role" binding:"required,oneof=admin user"`
+	AvatarURL string `json:"avatar_url" binding:"omitempty,http_url"`
+	// InviteCode is required while registration is invite-only
+	InviteCode string `json:"invite_code"`
+}
+
+// UpdateProfileRequest carries the profile fields a user may change on their
+// own account. Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1"`
+	AvatarURL *string `json:"avatar_url" binding:"omitempty,http_url"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	RememberMe bool   `json:"remember_me"`
 }
 
-type APIResponse struct {
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+// ChangePasswordRequest is sent by a user replacing their own password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ResetPasswordRequest is sent by an admin setting a temporary password
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// CloneTaskRequest overrides fields of the task being cloned. Omitted fields
+// are copied from the source task.
+type CloneTaskRequest struct {
+	Title       *string    `json:"title" binding:"omitempty,min=1"`
+	Description *string    `json:"description"`
+	DueDate     *time.Time `json:"due_date"`
+	Tags        []string   `json:"tags"`
 }
 
+// TransferTaskRequest names the user who receives a task
+type TransferTaskRequest struct {
+	ToUserID string `json:"to_user_id" binding:"required"`
+}
+
+// ShareTaskRequest names the user a task is shared with. Permission is
+// "read" or "write" and defaults to read.
+type ShareTaskRequest struct {
+	UserID     string `json:"user_id" binding:"required"`
+	Permission string `json:"permission"`
+}
+
+// BatchGetTasksRequest lists the tasks to fetch in one call
+type BatchGetTasksRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1"`
+}
+
+// BatchGetTasksResult holds the requested tasks the caller owns. IDs that do
+// not exist are listed in Missing and those owned by someone else in Forbidden.
+type BatchGetTasksResult struct {
+	Tasks     []*Task              `json:"tasks" xml:"tasks>task"`
+	Missing   []primitive.ObjectID `json:"missing" xml:"missing>id"`
+	Forbidden []primitive.ObjectID `json:"forbidden" xml:"forbidden>id"`
+}
+
+// BulkTagRequest names the tasks to retag and the tags to add and remove
+type BulkTagRequest struct {
+	IDs    []string `json:"ids" binding:"required,min=1"`
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// ReorderTasksRequest lists the caller's tasks in their new order
+type ReorderTasksRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1"`
+}
+
+// ReorderResult reports how many of the caller's tasks were given a new
+// position. IDs that do not exist or belong to someone else are listed in
+// Skipped.
+type ReorderResult struct {
+	Updated int64                `json:"updated" xml:"updated"`
+	Skipped []primitive.ObjectID `json:"skipped" xml:"skipped>id"`
+}
+
+// PreviewFilterRequest describes a task filter in a request body. Its fields
+// mirror the task list query parameters: DueDate is a YYYY-MM-DD day and
+// Metadata lists the meta.<key> pairs.
+type PreviewFilterRequest struct {
+	Relation   string            `json:"relation" binding:"omitempty,oneof=owned assigned all"`
+	Statuses   []string          `json:"statuses" binding:"omitempty,dive,oneof=pending in_progress completed"`
+	DueDate    string            `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	HasDueDate *bool             `json:"has_due_date"`
+	Metadata   map[string]string `json:"metadata"`
+}
+
+// FilterPreview reports how many tasks a filter matches, with a few of them
+// as a sample
+type FilterPreview struct {
+	Count  int64   `json:"count" xml:"count"`
+	Sample []*Task `json:"sample" xml:"sample>task"`
+}
+
+// BulkTagResult reports how many of the caller's tasks were retagged. IDs
+// that do not exist or belong to someone else are listed in Skipped, and
+// tasks the change would take past the tag limit are left as they were and
+// listed in OverLimit.
+type BulkTagResult struct {
+	Updated   int64                `json:"updated" xml:"updated"`
+	Skipped   []primitive.ObjectID `json:"skipped" xml:"skipped>id"`
+	OverLimit []primitive.ObjectID `json:"over_limit" xml:"over_limit>id"`
+}
+
+// MaintenanceRequest turns maintenance mode on or off
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// BulkUserResult reports the outcome of one record in a bulk user creation
+type BulkUserResult struct {
+	Index  int    `json:"index" xml:"index"`
+	Email  string `json:"email" xml:"email"`
+	Status string `json:"status" xml:"status"`
+	User   *User  `json:"user,omitempty" xml:"user,omitempty"`
+	Error  string `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+const (
+	BulkStatusCreated = "created"
+	BulkStatusFailed  = "failed"
+)
+
+type APIResponse struct {
+	XMLName xml.Name    `json:"-" xml:"response"`
+	Message string      `json:"message" xml:"message"`
+	Data    interface{} `json:"data,omitempty" xml:"data,omitempty"`
+}
@@ -1,6 +1,131 @@
 package Domain
 
-import "errors"
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // ErrUserNotFound is returned when a user is not found in the repository.
 var ErrUserNotFound = errors.New("user not found")
+
+// ErrUserAlreadyExists is returned when an active user already has the email.
+var ErrUserAlreadyExists = errors.New("user already exists")
+
+// ErrIncorrectPassword is returned when a supplied current password does not match.
+var ErrIncorrectPassword = errors.New("current password is incorrect")
+
+// ErrPasswordReused is returned when a new password matches a recently used one.
+var ErrPasswordReused = errors.New("password was used recently; choose a different one")
+
+// ErrPasswordChangeRequired is returned while a user must replace a reset password.
+var ErrPasswordChangeRequired = errors.New("password change required")
+
+// ErrTaskNotFound is returned when a task is not found in the repository.
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrNotTaskOwner is returned when a user acts on a task only its owner may change.
+var ErrNotTaskOwner = errors.New("only the task owner can do this")
+
+// ErrInvalidSharePermission is returned when a share permission is neither read nor write.
+var ErrInvalidSharePermission = errors.New("invalid permission: must be read or write")
+
+// ErrShareWithOwner is returned when a task is shared with the user who owns it.
+var ErrShareWithOwner = errors.New("a task cannot be shared with its owner")
+
+// ErrSharedTasksDisabled is returned when everyone's tasks are listed while shared mode is off.
+var ErrSharedTasksDisabled = errors.New("shared task mode is disabled")
+
+// ErrStatusRegression is returned when an update moves an in-progress task back to pending.
+var ErrStatusRegression = errors.New("task status cannot move back from in_progress to pending")
+
+// ErrNegativeMinutes is returned when a task's estimated or actual minutes are below zero.
+var ErrNegativeMinutes = errors.New("estimated_minutes and actual_minutes cannot be negative")
+
+// ErrTooManyTags is returned when a task has more tags than allowed.
+var ErrTooManyTags = errors.New("too many tags")
+
+// ErrTagTooLong is returned when one of a task's tags is longer than allowed.
+var ErrTagTooLong = errors.New("tag is too long")
+
+// ErrTooManyMetadataKeys is returned when a task has more metadata keys than allowed.
+var ErrTooManyMetadataKeys = errors.New("too many metadata keys")
+
+// ErrInvalidMetadataKey is returned when a metadata key is empty or has characters other than letters, digits, '-' and '_'.
+var ErrInvalidMetadataKey = errors.New("metadata keys may only contain letters, digits, '-' and '_'")
+
+// ErrMetadataTooLong is returned when a metadata key or value is longer than allowed.
+var ErrMetadataTooLong = errors.New("metadata key or value is too long")
+
+// ErrTaskInProgress is returned when deleting an in-progress task without forcing it.
+var ErrTaskInProgress = errors.New("task is in progress; pass force=true to delete it")
+
+// ErrTaskQuotaExceeded is returned when a user already owns the maximum number of tasks.
+var ErrTaskQuotaExceeded = errors.New("task quota exceeded")
+
+// ErrTaskCreateThrottled is returned when a user creates tasks faster than the
+// configured minimum interval allows.
+var ErrTaskCreateThrottled = errors.New("tasks are being created too quickly; try again shortly")
+
+// ErrDuplicateTaskTitle is returned when unique titles are enforced and the
+// user already has an unfinished task with the same title.
+var ErrDuplicateTaskTitle = errors.New("an unfinished task with this title already exists")
+
+// ErrInvalidID is returned when a use case is given the zero ObjectID, which
+// never names a stored record.
+var ErrInvalidID = errors.New("id must not be empty")
+
+// ErrBatchTooLarge is returned when a batch request names more IDs than allowed.
+var ErrBatchTooLarge = errors.New("too many ids in one request")
+
+// ErrNoTagChanges is returned when a bulk tag request neither adds nor removes a tag.
+var ErrNoTagChanges = errors.New("add or remove must name at least one tag")
+
+// ErrInvalidStatus is returned when a task status is not one of the known statuses.
+var ErrInvalidStatus = errors.New("invalid status: must be pending, in_progress or completed")
+
+// ErrEmptySearchQuery is returned when a task search has no search terms.
+var ErrEmptySearchQuery = errors.New("search query cannot be empty")
+
+// ErrChecklistIndexOutOfRange is returned when a checklist item index does not exist.
+var ErrChecklistIndexOutOfRange = errors.New("checklist index out of range")
+
+// ErrReminderInPast is returned when a task's reminder time has already passed.
+var ErrReminderInPast = errors.New("reminder time cannot be in the past")
+
+// ErrReminderAfterDueDate is returned when a task's reminder is not before its due date.
+var ErrReminderAfterDueDate = errors.New("reminder time must be before the due date")
+
+// ErrSelfDependency is returned when a task lists itself as a dependency.
+var ErrSelfDependency = errors.New("task cannot depend on itself")
+
+// ErrInvalidDependency is returned when a dependency does not exist or belongs to another user.
+var ErrInvalidDependency = errors.New("dependencies must be existing tasks owned by the same user")
+
+// ErrDependencyCycle is returned when a dependency would make a task depend on itself indirectly.
+var ErrDependencyCycle = errors.New("dependencies must not form a cycle")
+
+// TaskBlockedError is returned when completing a task whose dependencies are
+// not all completed. BlockingTaskIDs lists the unfinished dependencies.
+type TaskBlockedError struct {
+	BlockingTaskIDs []primitive.ObjectID
+}
+
+func (e *TaskBlockedError) Error() string {
+	return "task is blocked by uncompleted dependencies"
+}
+
+// ErrInvalidDateRange is returned when a date range starts after it ends.
+var ErrInvalidDateRange = errors.New("invalid date range: start must not be after end")
+
+// ErrInviteRequired is returned when registration is invite-only and no invite code was given.
+var ErrInviteRequired = errors.New("an invite code is required to register")
+
+// ErrInvalidInvite is returned when an invite code is unknown or has already been used.
+var ErrInvalidInvite = errors.New("invalid or used invite code")
+
+// ErrAPIKeyNotFound is returned when an API key does not exist.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// ErrInvalidAPIKey is returned when an API key is unknown or has been revoked.
+var ErrInvalidAPIKey = errors.New("invalid api key")
@@ -0,0 +1,38 @@
+// Functions for generating and hashing API keys used by machine clients.
+
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// apiKeyPrefix makes issued keys easy to recognise in logs and secret scanners
+const apiKeyPrefix = "tm_"
+
+// GenerateAPIKey returns a new random API key
+func GenerateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashAPIKey returns the SHA-256 digest of an API key. Keys carry enough
+// entropy that a fast, deterministic hash is sufficient and lets the stored
+// value be looked up directly.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateInviteCode returns a new random single-use registration code
+func GenerateInviteCode() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// APIKeyServiceTestSuite groups all API key service-related tests
+type APIKeyServiceTestSuite struct {
+	suite.Suite
+}
+
+// TestGenerateAPIKey tests that generated keys are prefixed and unique
+func (suite *APIKeyServiceTestSuite) TestGenerateAPIKey() {
+	first, err := GenerateAPIKey()
+	assert.NoError(suite.T(), err)
+	second, err := GenerateAPIKey()
+	assert.NoError(suite.T(), err)
+
+	assert.True(suite.T(), strings.HasPrefix(first, "tm_"))
+	assert.NotEqual(suite.T(), first, second)
+}
+
+// TestHashAPIKey tests that hashing is deterministic and does not echo the key
+func (suite *APIKeyServiceTestSuite) TestHashAPIKey() {
+	key := "tm_example"
+
+	assert.Equal(suite.T(), HashAPIKey(key), HashAPIKey(key))
+	assert.NotEqual(suite.T(), key, HashAPIKey(key))
+	assert.NotEqual(suite.T(), HashAPIKey(key), HashAPIKey("tm_other"))
+}
+
+// TestGenerateInviteCode tests that invite codes are unique
+func (suite *APIKeyServiceTestSuite) TestGenerateInviteCode() {
+	first, err := GenerateInviteCode()
+	assert.NoError(suite.T(), err)
+	second, err := GenerateInviteCode()
+	assert.NoError(suite.T(), err)
+
+	assert.Len(suite.T(), first, 32)
+	assert.NotEqual(suite.T(), first, second)
+}
+
+// Run the test suite
+func TestAPIKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyServiceTestSuite))
+}
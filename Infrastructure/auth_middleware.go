@@ -1,58 +1,306 @@
 package infrastructure
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
+
+	domain "Task-Management/Domain"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// AuthMiddleware handles authentication and authorization
-func AuthMiddleware(validateToken func(string) (*Claims, error)) gin.HandlerFunc {
+// AuthMiddleware handles authentication and authorization. A token is only
+// accepted while its version matches the user's stored token version, so
+// revoking sessions invalidates every token issued before. The user loaded
+// for that check is stored as "current_user" for the middlewares and
+// handlers after it, and the role is taken from it rather than from the
+// token so a role change applies to tokens already issued.
+func AuthMiddleware(validateToken func(string) (*Claims, error), getUser func(context.Context, primitive.ObjectID) (*domain.User, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "authorization header is required")
 			return
 		}
 
 		// Extract token from Bearer header
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "invalid authorization header format")
 			return
 		}
 
 		claims, err := validateToken(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "invalid token")
 			return
 		}
 
-		// Store claims in context
+		userID, err := primitive.ObjectIDFromHex(claims.UserID)
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		user, err := getUser(c.Request.Context(), userID)
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if user == nil || user.TokenVersion != claims.TokenVersion {
+			abortWithError(c, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		// Store claims and the user in context
+		current := *user
 		c.Set("claims", claims)
+		c.Set("user_id", claims.UserID)
+		c.Set("role", user.Role)
+		c.Set("current_user", &current)
+		c.Next()
+	}
+}
+
+// APIKeyMiddleware authenticates machine clients by the X-API-Key header and
+// stores the calling service's identity in the context
+func APIKeyMiddleware(validateKey func(context.Context, string) (*domain.APIKey, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			abortWithError(c, http.StatusUnauthorized, "api key is required")
+			return
+		}
+
+		key, err := validateKey(c.Request.Context(), rawKey)
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+
+		c.Set("service", key.Name)
+		c.Set("api_key_id", key.ID.Hex())
+		c.Next()
+	}
+}
+
+// CurrentUserMiddleware loads the authenticated user and stores it in the
+// context as "current_user" so handlers can read the profile without another
+// query. It must run after AuthMiddleware, and reuses the user it already
+// loaded. A token whose user no longer exists, such as a deleted account, is
+// rejected with 401. getUser is usually a CachedUserLookup; each request
+// gets its own copy of the user, so handlers may modify it.
+func CurrentUserMiddleware(getUser func(context.Context, primitive.ObjectID) (*domain.User, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, loaded := c.Value("current_user").(*domain.User); loaded {
+			c.Next()
+			return
+		}
+
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		user, err := getUser(c.Request.Context(), userID)
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if user == nil {
+			abortWithError(c, http.StatusUnauthorized, "user no longer exists")
+			return
+		}
+
+		current := *user
+		c.Set("current_user", &current)
+		c.Next()
+	}
+}
+
+// CachedUserLookup wraps getUser so users found are kept in memory for ttl
+// and reused by every middleware given the lookup. Missing users and errors
+// are not cached. A non-positive ttl returns getUser unchanged.
+func CachedUserLookup(getUser func(context.Context, primitive.ObjectID) (*domain.User, error), ttl time.Duration) func(context.Context, primitive.ObjectID) (*domain.User, error) {
+	if ttl <= 0 {
+		return getUser
+	}
+	cache := &userCache{ttl: ttl, entries: make(map[primitive.ObjectID]cachedUser)}
+	return func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		if user, ok := cache.get(id); ok {
+			return user, nil
+		}
+		user, err := getUser(ctx, id)
+		if err == nil && user != nil {
+			cache.put(user)
+		}
+		return user, err
+	}
+}
+
+// userCache holds users loaded by CachedUserLookup until ttl passes
+type userCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[primitive.ObjectID]cachedUser
+}
+
+type cachedUser struct {
+	user    *domain.User
+	expires time.Time
+}
+
+func (uc *userCache) get(id primitive.ObjectID) (*domain.User, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	entry, ok := uc.entries[id]
+	if !ok || !clock.Now().Before(entry.expires) {
+		delete(uc.entries, id)
+		return nil, false
+	}
+	return entry.user, true
+}
+
+func (uc *userCache) put(user *domain.User) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.entries[user.ID] = cachedUser{user: user, expires: clock.Now().Add(uc.ttl)}
+}
+
+// PasswordChangeMiddleware blocks mutating requests from users whose
+// password was reset until they choose a new one. Reads pass through. The
+// flag is read from the user record so a reset applies to existing tokens;
+// the record stored by CurrentUserMiddleware is used when present.
+func PasswordChangeMiddleware(getUser func(context.Context, primitive.ObjectID) (*domain.User, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		user, loaded := c.Value("current_user").(*domain.User)
+		if !loaded {
+			userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+			if err != nil {
+				abortWithError(c, http.StatusUnauthorized, "invalid token")
+				return
+			}
+
+			user, err = getUser(c.Request.Context(), userID)
+			if err != nil {
+				abortWithError(c, http.StatusInternalServerError, "internal server error")
+				return
+			}
+		}
+		if user != nil && user.MustChangePassword {
+			abortWithError(c, http.StatusForbidden, domain.ErrPasswordChangeRequired.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireFreshToken rejects tokens issued more than maxAge ago so sensitive
+// actions need a recent login. It must run after AuthMiddleware, which stores
+// the claims in the context.
+func RequireFreshToken(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.Value("claims").(*Claims)
+		if !ok || claims.IssuedAt == 0 || clock.Now().Sub(time.Unix(claims.IssuedAt, 0)) > maxAge {
+			abortWithError(c, http.StatusUnauthorized, "re-authentication required")
+			return
+		}
 		c.Next()
 	}
 }
 
-// AdminMiddleware ensures that only admin users can access the route
+// ConfirmationHeader carries the token from POST /api/admin/confirm on a
+// destructive admin request
+const ConfirmationHeader = "X-Confirmation-Token"
+
+// RequireConfirmation guards destructive admin actions with a two-step
+// confirm. The request must carry a confirmation token issued to the same
+// user in ConfirmationHeader, otherwise 428 Precondition Required is
+// returned. A token confirms a single request: it is spent before the action
+// runs, so a failed action needs a new one. Spent tokens are remembered in
+// memory until they expire, which is shared by every route the returned
+// middleware guards but not across instances. It must run after
+// AuthMiddleware, which stores the user ID.
+func RequireConfirmation() gin.HandlerFunc {
+	spent := &spentTokens{expires: make(map[string]time.Time)}
+	return func(c *gin.Context) {
+		token := c.GetHeader(ConfirmationHeader)
+		if token == "" {
+			abortWithError(c, http.StatusPreconditionRequired, "confirmation token required")
+			return
+		}
+		claims, err := ValidateConfirmationToken(token, c.GetString("user_id"))
+		if err != nil {
+			abortWithError(c, http.StatusPreconditionRequired, "invalid or expired confirmation token")
+			return
+		}
+		if !spent.spend(claims.Id, time.Unix(claims.ExpiresAt, 0)) {
+			abortWithError(c, http.StatusPreconditionRequired, "confirmation token already used")
+			return
+		}
+		c.Next()
+	}
+}
+
+// spentTokens remembers the IDs of used confirmation tokens until they expire
+type spentTokens struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+// spend marks token id as used and reports whether it was still unused.
+// Tokens past their expiry are forgotten, since they are rejected anyway.
+func (s *spentTokens) spend(id string, expires time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := clock.Now()
+	for spentID, at := range s.expires {
+		if now.After(at) {
+			delete(s.expires, spentID)
+		}
+	}
+	if _, ok := s.expires[id]; ok {
+		return false
+	}
+	s.expires[id] = expires
+	return true
+}
+
+// AdminMiddleware ensures that only admin users can access the route.
+// Requests that were never authenticated get 401; other users get 403.
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
-			c.Abort()
+		if !exists {
+			abortWithError(c, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		if role != "admin" {
+			abortWithError(c, http.StatusForbidden, "admin access required")
 			return
 		}
 		c.Next()
 	}
 }
 
+// RequireRole lets through users whose role is one of roles. Like
+// AdminMiddleware it answers 401 when no role was set and 403 otherwise.
 func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if _, exists := c.Get("role"); !exists {
+			abortWithError(c, http.StatusUnauthorized, "authentication required")
+			return
+		}
 		userRole := c.GetString("role")
 		for _, role := range roles {
 			if role == userRole {
@@ -60,7 +308,12 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 				return
 			}
 		}
-		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-		c.Abort()
+		abortWithError(c, http.StatusForbidden, "Insufficient permissions")
 	}
 }
+
+// abortWithError stops the request with status and the same response
+// envelope the controllers use, so every error body has a "message" field
+func abortWithError(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, domain.APIResponse{Message: message})
+}
@@ -1,14 +1,20 @@
 package infrastructure
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	domain "Task-Management/Domain"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // AuthMiddlewareTestSuite groups all middleware-related tests
@@ -17,6 +23,14 @@ type AuthMiddlewareTestSuite struct {
 	router *gin.Engine
 }
 
+// usersWithVersion returns a user lookup for AuthMiddleware that finds every
+// user with the given token version
+func usersWithVersion(version int) func(context.Context, primitive.ObjectID) (*domain.User, error) {
+	return func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return &domain.User{ID: id, TokenVersion: version}, nil
+	}
+}
+
 // SetupSuite runs once before all tests
 func (suite *AuthMiddlewareTestSuite) SetupSuite() {
 	gin.SetMode(gin.TestMode)
@@ -31,7 +45,7 @@ func (suite *AuthMiddlewareTestSuite) SetupTest() {
 func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_MissingAuthorizationHeader() {
 	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
 		return nil, errors.New("mock validation not implemented")
-	}))
+	}, usersWithVersion(0)))
 	suite.router.GET("/protected", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -41,14 +55,14 @@ func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_MissingAuthorizationHea
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
-	assert.JSONEq(suite.T(), `{"error": "authorization header is required"}`, resp.Body.String())
+	assert.JSONEq(suite.T(), `{"message": "authorization header is required"}`, resp.Body.String())
 }
 
 // TestAuthMiddleware_InvalidAuthorizationHeaderFormat tests invalid Authorization header format
 func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_InvalidAuthorizationHeaderFormat() {
 	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
 		return nil, errors.New("mock validation not implemented")
-	}))
+	}, usersWithVersion(0)))
 	suite.router.GET("/protected", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -59,14 +73,14 @@ func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_InvalidAuthorizationHea
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
-	assert.JSONEq(suite.T(), `{"error": "invalid authorization header format"}`, resp.Body.String())
+	assert.JSONEq(suite.T(), `{"message": "invalid authorization header format"}`, resp.Body.String())
 }
 
 // TestAuthMiddleware_InvalidToken tests invalid token
 func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_InvalidToken() {
 	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
 		return nil, errors.New("mock validation not implemented")
-	}))
+	}, usersWithVersion(0)))
 	suite.router.GET("/protected", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -77,17 +91,17 @@ func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_InvalidToken() {
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
-	assert.JSONEq(suite.T(), `{"error": "invalid token"}`, resp.Body.String())
+	assert.JSONEq(suite.T(), `{"message": "invalid token"}`, resp.Body.String())
 }
 
 // TestAuthMiddleware_ValidToken tests valid token
 func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_ValidToken() {
 	validToken := "valid_token"
 	mockValidateToken := func(token string) (*Claims, error) {
-		return &Claims{UserID: "123", Role: "user"}, nil
+		return &Claims{UserID: primitive.NewObjectID().Hex(), Role: "user"}, nil
 	}
 
-	suite.router.Use(AuthMiddleware(mockValidateToken))
+	suite.router.Use(AuthMiddleware(mockValidateToken, usersWithVersion(0)))
 	suite.router.GET("/protected", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -106,7 +120,7 @@ func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_ExpiredToken() {
 	expiredToken := "expired_token"
 	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
 		return nil, errors.New("token expired")
-	}))
+	}, usersWithVersion(0)))
 	suite.router.GET("/protected", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -118,7 +132,7 @@ func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_ExpiredToken() {
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
-	assert.JSONEq(suite.T(), `{"error": "invalid token"}`, resp.Body.String())
+	assert.JSONEq(suite.T(), `{"message": "invalid token"}`, resp.Body.String())
 }
 
 // TestAdminMiddleware_NonAdminUser tests non-admin user access
@@ -137,7 +151,53 @@ func (suite *AuthMiddlewareTestSuite) TestAdminMiddleware_NonAdminUser() {
 	suite.router.ServeHTTP(resp, req)
 
 	assert.Equal(suite.T(), http.StatusForbidden, resp.Code)
-	assert.JSONEq(suite.T(), `{"error": "admin access required"}`, resp.Body.String())
+	assert.JSONEq(suite.T(), `{"message": "admin access required"}`, resp.Body.String())
+}
+
+// TestAdminMiddleware_Unauthenticated tests that a request without a role is unauthenticated, not forbidden
+func (suite *AuthMiddlewareTestSuite) TestAdminMiddleware_Unauthenticated() {
+	suite.router.Use(AdminMiddleware())
+	suite.router.GET("/admin", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "authentication required"}`, resp.Body.String())
+}
+
+// TestRequireRole tests that a missing role gives 401 and a wrong one 403
+func (suite *AuthMiddlewareTestSuite) TestRequireRole() {
+	role := ""
+	suite.router.Use(func(c *gin.Context) {
+		if role != "" {
+			c.Set("role", role)
+		}
+		c.Next()
+	})
+	suite.router.Use(RequireRole("admin", "manager"))
+	suite.router.GET("/reports", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	for _, tc := range []struct {
+		role   string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"user", http.StatusForbidden},
+		{"manager", http.StatusOK},
+	} {
+		role = tc.role
+		req, _ := http.NewRequest(http.MethodGet, "/reports", nil)
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+
+		assert.Equal(suite.T(), tc.status, resp.Code, tc.role)
+	}
 }
 
 // TestAdminMiddleware_AdminUser tests admin user access
@@ -159,6 +219,465 @@ func (suite *AuthMiddlewareTestSuite) TestAdminMiddleware_AdminUser() {
 	assert.JSONEq(suite.T(), `{"message": "success"}`, resp.Body.String())
 }
 
+// TestAuthMiddleware_SetsIdentity tests that the user ID, the stored role and
+// the loaded user are exposed to handlers
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_SetsIdentity() {
+	userID := primitive.NewObjectID().Hex()
+	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
+		return &Claims{UserID: userID, Role: "user"}, nil
+	}, func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return &domain.User{ID: id, Email: "admin@example.com", Role: "admin"}, nil
+	}))
+	suite.router.GET("/protected", func(c *gin.Context) {
+		user := c.MustGet("current_user").(*domain.User)
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "role": c.GetString("role"), "email": user.Email})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer valid_token")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"user_id": "`+userID+`", "role": "admin", "email": "admin@example.com"}`, resp.Body.String())
+}
+
+// TestAuthMiddleware_RevokedToken tests that a token issued before sessions were revoked is rejected
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_RevokedToken() {
+	version := 0
+	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
+		return &Claims{UserID: primitive.NewObjectID().Hex(), Role: "user", TokenVersion: 0}, nil
+	}, func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return &domain.User{ID: id, TokenVersion: version}, nil
+	}))
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer issued_before")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	// Revoking sessions bumps the stored version
+	version++
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "invalid token"}`, resp.Body.String())
+}
+
+// TestAuthMiddleware_UnknownUser tests that a token for a missing user or a malformed ID is rejected
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_UnknownUser() {
+	userID := primitive.NewObjectID().Hex()
+	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
+		if token == "malformed" {
+			return &Claims{UserID: "123"}, nil
+		}
+		return &Claims{UserID: userID}, nil
+	}, func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return nil, nil
+	}))
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	for _, token := range []string{"deleted", "malformed"} {
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+
+		assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code, token)
+	}
+}
+
+// TestPasswordChangeMiddleware_BlocksWrites tests that a flagged user cannot mutate
+func (suite *AuthMiddlewareTestSuite) TestPasswordChangeMiddleware_BlocksWrites() {
+	userID := primitive.NewObjectID()
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.Hex())
+		c.Next()
+	})
+	suite.router.Use(PasswordChangeMiddleware(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return &domain.User{ID: id, MustChangePassword: true}, nil
+	}))
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	}
+	suite.router.GET("/tasks", handler)
+	suite.router.POST("/tasks", handler)
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "password change required"}`, resp.Body.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "/tasks", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+}
+
+// TestPasswordChangeMiddleware_AllowsUnflaggedUser tests that other users pass through
+func (suite *AuthMiddlewareTestSuite) TestPasswordChangeMiddleware_AllowsUnflaggedUser() {
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", primitive.NewObjectID().Hex())
+		c.Next()
+	})
+	suite.router.Use(PasswordChangeMiddleware(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return &domain.User{ID: id}, nil
+	}))
+	suite.router.POST("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+}
+
+// TestCurrentUserMiddleware_StoresUser tests that the loaded user is available to handlers
+func (suite *AuthMiddlewareTestSuite) TestCurrentUserMiddleware_StoresUser() {
+	userID := primitive.NewObjectID()
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.Hex())
+		c.Next()
+	})
+	suite.router.Use(CurrentUserMiddleware(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return &domain.User{ID: id, Email: "user@example.com"}, nil
+	}))
+	suite.router.GET("/me", func(c *gin.Context) {
+		user := c.MustGet("current_user").(*domain.User)
+		c.JSON(http.StatusOK, gin.H{"email": user.Email})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"email": "user@example.com"}`, resp.Body.String())
+}
+
+// TestCurrentUserMiddleware_DeletedUser tests that a token for a deleted user is rejected
+func (suite *AuthMiddlewareTestSuite) TestCurrentUserMiddleware_DeletedUser() {
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", primitive.NewObjectID().Hex())
+		c.Next()
+	})
+	suite.router.Use(CurrentUserMiddleware(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		return nil, nil
+	}))
+	suite.router.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "user no longer exists"}`, resp.Body.String())
+}
+
+// TestCurrentUserMiddleware_ReusesAuthenticatedUser tests that the user
+// AuthMiddleware loaded is not fetched again
+func (suite *AuthMiddlewareTestSuite) TestCurrentUserMiddleware_ReusesAuthenticatedUser() {
+	loads := 0
+	getUser := func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		loads++
+		return &domain.User{ID: id, Email: "user@example.com"}, nil
+	}
+	suite.router.Use(AuthMiddleware(func(token string) (*Claims, error) {
+		return &Claims{UserID: primitive.NewObjectID().Hex()}, nil
+	}, getUser), CurrentUserMiddleware(getUser))
+	suite.router.GET("/me", func(c *gin.Context) {
+		user := c.MustGet("current_user").(*domain.User)
+		c.JSON(http.StatusOK, gin.H{"email": user.Email})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer valid_token")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"email": "user@example.com"}`, resp.Body.String())
+	assert.Equal(suite.T(), 1, loads)
+}
+
+// TestCachedUserLookup tests that users are reloaded only after the TTL passes
+func (suite *AuthMiddlewareTestSuite) TestCachedUserLookup() {
+	fakeClock := NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	ConfigureClock(fakeClock)
+	defer ConfigureClock(nil)
+
+	loads := 0
+	userID := primitive.NewObjectID()
+	getUser := CachedUserLookup(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		loads++
+		return &domain.User{ID: id}, nil
+	}, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		user, err := getUser(context.Background(), userID)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), userID, user.ID)
+	}
+	assert.Equal(suite.T(), 1, loads)
+
+	fakeClock.Advance(2 * time.Minute)
+	getUser(context.Background(), userID)
+	assert.Equal(suite.T(), 2, loads)
+}
+
+// TestCachedUserLookup_MissingUser tests that users not found are looked up again
+func (suite *AuthMiddlewareTestSuite) TestCachedUserLookup_MissingUser() {
+	loads := 0
+	getUser := CachedUserLookup(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		loads++
+		return nil, nil
+	}, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		user, err := getUser(context.Background(), primitive.NewObjectID())
+		assert.NoError(suite.T(), err)
+		assert.Nil(suite.T(), user)
+	}
+	assert.Equal(suite.T(), 2, loads)
+}
+
+// TestPasswordChangeMiddleware_UsesCurrentUser tests that a user loaded earlier is not fetched again
+func (suite *AuthMiddlewareTestSuite) TestPasswordChangeMiddleware_UsesCurrentUser() {
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("current_user", &domain.User{ID: primitive.NewObjectID(), MustChangePassword: true})
+		c.Next()
+	})
+	suite.router.Use(PasswordChangeMiddleware(func(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+		suite.Fail("getUser should not be called")
+		return nil, nil
+	}))
+	suite.router.POST("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, resp.Code)
+}
+
+// TestRequireFreshToken_FreshToken tests that a recently issued token passes
+func (suite *AuthMiddlewareTestSuite) TestRequireFreshToken_FreshToken() {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ConfigureClock(NewFakeClock(now))
+	defer ConfigureClock(nil)
+
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("claims", &Claims{UserID: "123", StandardClaims: jwt.StandardClaims{IssuedAt: now.Add(-5 * time.Minute).Unix()}})
+		c.Next()
+	})
+	suite.router.Use(RequireFreshToken(15 * time.Minute))
+	suite.router.PUT("/me/password", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/me/password", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+}
+
+// TestRequireFreshToken_StaleToken tests that an old token must re-authenticate
+func (suite *AuthMiddlewareTestSuite) TestRequireFreshToken_StaleToken() {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ConfigureClock(NewFakeClock(now))
+	defer ConfigureClock(nil)
+
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("claims", &Claims{UserID: "123", StandardClaims: jwt.StandardClaims{IssuedAt: now.Add(-2 * time.Hour).Unix()}})
+		c.Next()
+	})
+	suite.router.Use(RequireFreshToken(15 * time.Minute))
+	suite.router.PUT("/me/password", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/me/password", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "re-authentication required"}`, resp.Body.String())
+}
+
+// TestRequireConfirmation_MissingToken tests that a destructive request without a confirmation gets 428
+func (suite *AuthMiddlewareTestSuite) TestRequireConfirmation_MissingToken() {
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "admin-1")
+		c.Next()
+	})
+	suite.router.Use(RequireConfirmation())
+	suite.router.DELETE("/admin/api-keys/1", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/api-keys/1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusPreconditionRequired, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "confirmation token required"}`, resp.Body.String())
+}
+
+// TestRequireConfirmation_ValidToken tests that a confirmation issued to the caller lets the request through
+func (suite *AuthMiddlewareTestSuite) TestRequireConfirmation_ValidToken() {
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "admin-1")
+		c.Next()
+	})
+	suite.router.Use(RequireConfirmation())
+	suite.router.DELETE("/admin/api-keys/1", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	token, err := GenerateConfirmationToken("admin-1")
+	assert.NoError(suite.T(), err)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/api-keys/1", nil)
+	req.Header.Set(ConfirmationHeader, token)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+}
+
+// TestRequireConfirmation_SingleUse tests that a confirmation cannot be replayed on the same or another guarded route
+func (suite *AuthMiddlewareTestSuite) TestRequireConfirmation_SingleUse() {
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "admin-1")
+		c.Next()
+	})
+	suite.router.Use(RequireConfirmation())
+	suite.router.DELETE("/admin/api-keys/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	token, err := GenerateConfirmationToken("admin-1")
+	assert.NoError(suite.T(), err)
+
+	for i, path := range []string{"/admin/api-keys/1", "/admin/api-keys/1", "/admin/api-keys/2"} {
+		req, _ := http.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set(ConfirmationHeader, token)
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+
+		if i == 0 {
+			assert.Equal(suite.T(), http.StatusOK, resp.Code)
+			continue
+		}
+		assert.Equal(suite.T(), http.StatusPreconditionRequired, resp.Code)
+		assert.JSONEq(suite.T(), `{"message": "confirmation token already used"}`, resp.Body.String())
+	}
+}
+
+// TestRequireConfirmation_InvalidToken tests that session tokens, expired confirmations and other users' confirmations are refused
+func (suite *AuthMiddlewareTestSuite) TestRequireConfirmation_InvalidToken() {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := NewFakeClock(now)
+	ConfigureClock(fakeClock)
+	defer ConfigureClock(nil)
+
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "admin-1")
+		c.Next()
+	})
+	suite.router.Use(RequireConfirmation())
+	suite.router.DELETE("/admin/api-keys/1", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	sessionToken, _ := GenerateToken("admin-1", "admin", 0, false)
+	otherAdminToken, _ := GenerateConfirmationToken("admin-2")
+	expiredToken, _ := GenerateConfirmationToken("admin-1")
+	fakeClock.Advance(confirmationTokenExpiry + time.Second)
+
+	for _, token := range []string{sessionToken, otherAdminToken, expiredToken} {
+		req, _ := http.NewRequest(http.MethodDelete, "/admin/api-keys/1", nil)
+		req.Header.Set(ConfirmationHeader, token)
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+
+		assert.Equal(suite.T(), http.StatusPreconditionRequired, resp.Code)
+		assert.JSONEq(suite.T(), `{"message": "invalid or expired confirmation token"}`, resp.Body.String())
+	}
+}
+
+// TestAPIKeyMiddleware_MissingKey tests a request without an API key
+func (suite *AuthMiddlewareTestSuite) TestAPIKeyMiddleware_MissingKey() {
+	suite.router.Use(APIKeyMiddleware(func(ctx context.Context, key string) (*domain.APIKey, error) {
+		return nil, errors.New("should not be called")
+	}))
+	suite.router.GET("/service", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/service", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "api key is required"}`, resp.Body.String())
+}
+
+// TestAPIKeyMiddleware_ValidKey tests that a valid key sets the service identity
+func (suite *AuthMiddlewareTestSuite) TestAPIKeyMiddleware_ValidKey() {
+	suite.router.Use(APIKeyMiddleware(func(ctx context.Context, key string) (*domain.APIKey, error) {
+		if key != "tm_valid" {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return &domain.APIKey{ID: primitive.NewObjectID(), Name: "billing"}, nil
+	}))
+	suite.router.GET("/service", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"service": c.GetString("service")})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/service", nil)
+	req.Header.Set("X-API-Key", "tm_valid")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"service": "billing"}`, resp.Body.String())
+}
+
+// TestAPIKeyMiddleware_InvalidKey tests that an unknown or revoked key is rejected
+func (suite *AuthMiddlewareTestSuite) TestAPIKeyMiddleware_InvalidKey() {
+	suite.router.Use(APIKeyMiddleware(func(ctx context.Context, key string) (*domain.APIKey, error) {
+		return nil, domain.ErrInvalidAPIKey
+	}))
+	suite.router.GET("/service", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/service", nil)
+	req.Header.Set("X-API-Key", "tm_revoked")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "invalid api key"}`, resp.Body.String())
+}
+
 // Run the test suite
 func TestAuthMiddlewareTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthMiddlewareTestSuite))
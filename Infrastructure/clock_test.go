@@ -0,0 +1,37 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Both clocks satisfy domain.Clock
+var (
+	_ domain.Clock = SystemClock{}
+	_ domain.Clock = (*FakeClock)(nil)
+)
+
+// TestSystemClock_Now tests that the system clock follows real time
+func TestSystemClock_Now(t *testing.T) {
+	before := time.Now()
+	now := SystemClock{}.Now()
+
+	assert.False(t, now.Before(before))
+}
+
+// TestFakeClock tests that the fake clock only moves when told to
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+}
@@ -0,0 +1,241 @@
+// Application configuration loaded from environment variables.
+
+package infrastructure
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Config holds the runtime settings read at startup
+type Config struct {
+	JWTAlgorithm        string
+	JWTPrivateKeyPath   string
+	JWTPublicKeyPath    string
+	JWTExpiry           time.Duration
+	JWTRememberMeExpiry time.Duration
+	BcryptCost          int
+	PasswordHistory     int
+	InviteOnly          bool
+	DeletionGrace       time.Duration
+	PurgeInterval       time.Duration
+	FreshTokenMaxAge    time.Duration
+	CurrentUserCacheTTL time.Duration
+	ShutdownTimeout     time.Duration
+	TLSCertPath         string
+	TLSKeyPath          string
+	HTTPRedirectAddr    string
+	AdminAddr           string
+	HeavyRouteTimeout   time.Duration
+	Maintenance         bool
+	MaintenanceRetry    time.Duration
+	StrictDelete        bool
+	TaskQuota           int
+	TaskCreateInterval  time.Duration
+	DefaultTaskStatus   string
+	DefaultDueIn        time.Duration
+	DefaultDueEndOfDay  bool
+	AutoAssignCreator   bool
+	SharedTasks         bool
+	NoStatusRegression  bool
+	UniqueTaskTitles    bool
+	BatchGetMaxIDs      int
+	MaxTagsPerTask      int
+	MaxTagLength        int
+	MaxMetadataKeys     int
+	MaxMetadataKeyLen   int
+	MaxMetadataValueLen int
+	CollectionPrefix    string
+	CreateIndexes       bool
+	RepoRetries         int
+	RepoRetryBackoff    time.Duration
+	Location            *time.Location
+	LogLevel            string
+	LogFormat           string
+	CamelCaseJSON       bool
+	TimestampLayout     string
+	CORS                CORSConfig
+}
+
+// LoadConfig reads the configuration from the environment, applying defaults
+// for anything that is not set
+func LoadConfig() Config {
+	return Config{
+		JWTAlgorithm:        getEnv("JWT_ALG", "HS256"),
+		JWTPrivateKeyPath:   os.Getenv("JWT_PRIVATE_KEY_PATH"),
+		JWTPublicKeyPath:    os.Getenv("JWT_PUBLIC_KEY_PATH"),
+		JWTExpiry:           getEnvDuration("JWT_EXPIRY", 24*time.Hour),
+		JWTRememberMeExpiry: getEnvDuration("JWT_REMEMBER_ME_EXPIRY", 30*24*time.Hour),
+		BcryptCost:          getEnvInt("BCRYPT_COST", bcrypt.DefaultCost),
+		PasswordHistory:     getEnvInt("PASSWORD_HISTORY", 0),
+		InviteOnly:          getEnvBool("INVITE_ONLY", false),
+		DeletionGrace:       getEnvDuration("ACCOUNT_DELETION_GRACE", 7*24*time.Hour),
+		PurgeInterval:       getEnvDuration("ACCOUNT_PURGE_INTERVAL", time.Hour),
+		FreshTokenMaxAge:    getEnvDuration("FRESH_TOKEN_MAX_AGE", 15*time.Minute),
+		CurrentUserCacheTTL: getEnvDuration("CURRENT_USER_CACHE_TTL", 0),
+		ShutdownTimeout:     getEnvDuration("SHUTDOWN_TIMEOUT", 5*time.Second),
+		TLSCertPath:         os.Getenv("TLS_CERT_PATH"),
+		TLSKeyPath:          os.Getenv("TLS_KEY_PATH"),
+		HTTPRedirectAddr:    os.Getenv("HTTP_REDIRECT_ADDR"),
+		AdminAddr:           os.Getenv("ADMIN_ADDR"),
+		HeavyRouteTimeout:   getEnvDuration("HEAVY_ROUTE_TIMEOUT", 10*time.Second),
+		Maintenance:         getEnvBool("MAINTENANCE_MODE", false),
+		MaintenanceRetry:    getEnvDuration("MAINTENANCE_RETRY_AFTER", 5*time.Minute),
+		StrictDelete:        getEnvBool("STRICT_DELETE", false),
+		TaskQuota:           getEnvInt("TASK_QUOTA", 0),
+		TaskCreateInterval:  getEnvDuration("TASK_CREATE_INTERVAL", 0),
+		DefaultTaskStatus:   getEnvTaskStatus("DEFAULT_TASK_STATUS", domain.StatusPending),
+		DefaultDueIn:        getEnvDuration("DEFAULT_DUE_IN", 0),
+		DefaultDueEndOfDay:  getEnvBool("DEFAULT_DUE_END_OF_DAY", false),
+		AutoAssignCreator:   getEnvBool("AUTO_ASSIGN_CREATOR", false),
+		SharedTasks:         getEnvBool("SHARED_TASKS", false),
+		NoStatusRegression:  getEnvBool("NO_STATUS_REGRESSION", true),
+		UniqueTaskTitles:    getEnvBool("UNIQUE_TASK_TITLES", false),
+		BatchGetMaxIDs:      getEnvInt("BATCH_GET_MAX_IDS", 100),
+		MaxTagsPerTask:      getEnvInt("MAX_TAGS_PER_TASK", 10),
+		MaxTagLength:        getEnvInt("MAX_TAG_LENGTH", 32),
+		MaxMetadataKeys:     getEnvInt("MAX_METADATA_KEYS", 20),
+		MaxMetadataKeyLen:   getEnvInt("MAX_METADATA_KEY_LENGTH", 64),
+		MaxMetadataValueLen: getEnvInt("MAX_METADATA_VALUE_LENGTH", 256),
+		CollectionPrefix:    os.Getenv("COLLECTION_PREFIX"),
+		CreateIndexes:       getEnvBool("CREATE_INDEXES", true),
+		RepoRetries:         getEnvInt("REPO_RETRIES", 0),
+		RepoRetryBackoff:    getEnvDuration("REPO_RETRY_BACKOFF", 100*time.Millisecond),
+		Location:            getEnvLocation("APP_TIMEZONE", getEnvLocation("DUE_DATE_TIMEZONE", time.UTC)),
+		LogLevel:            getEnv("LOG_LEVEL", "info"),
+		LogFormat:           getEnv("LOG_FORMAT", "text"),
+		CamelCaseJSON:       getEnvBool("CAMEL_CASE_JSON", false),
+		TimestampLayout:     getEnvTimeLayout("TIMESTAMP_FORMAT", ""),
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS"),
+			AllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           getEnvDuration("CORS_MAX_AGE", 10*time.Minute),
+		},
+	}
+}
+
+// TLSEnabled reports whether a certificate and key were configured, in which
+// case the server listens with HTTPS
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertPath != "" && c.TLSKeyPath != ""
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvList splits a comma-separated value such as "https://a.com,https://b.com",
+// dropping empty entries. It returns nil when the variable is unset.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvDuration parses a Go duration string such as "15m" or "720h".
+// Invalid values are logged and replaced by the fallback.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// getEnvInt parses a base 10 integer such as "500".
+// Invalid values are logged and replaced by the fallback.
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %d", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+// getEnvBool parses values accepted by strconv.ParseBool such as "true" or "0".
+// Invalid values are logged and replaced by the fallback.
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %t", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+// getEnvLocation loads an IANA timezone name such as "Europe/Berlin".
+// Invalid values are logged and replaced by the fallback.
+func getEnvLocation(key string, fallback *time.Location) *time.Location {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return loc
+}
+
+// namedTimeLayouts are the layouts TIMESTAMP_FORMAT accepts by name
+var namedTimeLayouts = map[string]string{
+	"RFC3339":      time.RFC3339,
+	"RFC3339Milli": "2006-01-02T15:04:05.000Z07:00",
+	"RFC3339Nano":  time.RFC3339Nano,
+}
+
+// getEnvTimeLayout reads a time layout, either one of namedTimeLayouts or a
+// Go reference-time layout such as "2006-01-02 15:04:05"
+func getEnvTimeLayout(key, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	if layout, ok := namedTimeLayouts[value]; ok {
+		return layout
+	}
+	return value
+}
+
+// getEnvTaskStatus reads a task status such as "in_progress".
+// Unknown statuses are logged and replaced by the fallback.
+func getEnvTaskStatus(key, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	if !domain.IsValidStatus(value) {
+		log.Printf("Invalid %s %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,158 @@
+package infrastructure
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// ConfigTestSuite groups all configuration-related tests
+type ConfigTestSuite struct {
+	suite.Suite
+}
+
+// TestLoadConfig_Defaults tests the defaults applied when nothing is set
+func (suite *ConfigTestSuite) TestLoadConfig_Defaults() {
+	os.Unsetenv("JWT_ALG")
+
+	cfg := LoadConfig()
+
+	assert.Equal(suite.T(), "HS256", cfg.JWTAlgorithm)
+	assert.Equal(suite.T(), 24*time.Hour, cfg.JWTExpiry)
+	assert.Equal(suite.T(), 30*24*time.Hour, cfg.JWTRememberMeExpiry)
+	assert.Equal(suite.T(), 10, cfg.BcryptCost)
+	assert.Zero(suite.T(), cfg.PasswordHistory)
+	assert.Equal(suite.T(), 15*time.Minute, cfg.FreshTokenMaxAge)
+	assert.Zero(suite.T(), cfg.CurrentUserCacheTTL)
+	assert.Equal(suite.T(), 5*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(suite.T(), 7*24*time.Hour, cfg.DeletionGrace)
+	assert.Equal(suite.T(), time.Hour, cfg.PurgeInterval)
+	assert.Equal(suite.T(), 10*time.Second, cfg.HeavyRouteTimeout)
+	assert.False(suite.T(), cfg.Maintenance)
+	assert.Equal(suite.T(), 5*time.Minute, cfg.MaintenanceRetry)
+	assert.False(suite.T(), cfg.StrictDelete)
+	assert.Zero(suite.T(), cfg.TaskCreateInterval)
+	assert.Equal(suite.T(), "pending", cfg.DefaultTaskStatus)
+	assert.Zero(suite.T(), cfg.DefaultDueIn)
+	assert.False(suite.T(), cfg.DefaultDueEndOfDay)
+	assert.False(suite.T(), cfg.AutoAssignCreator)
+	assert.False(suite.T(), cfg.SharedTasks)
+	assert.True(suite.T(), cfg.NoStatusRegression)
+	assert.False(suite.T(), cfg.UniqueTaskTitles)
+	assert.False(suite.T(), cfg.CamelCaseJSON)
+	assert.Empty(suite.T(), cfg.TimestampLayout)
+	assert.False(suite.T(), cfg.InviteOnly)
+	assert.Equal(suite.T(), 100, cfg.BatchGetMaxIDs)
+	assert.Equal(suite.T(), 10, cfg.MaxTagsPerTask)
+	assert.Equal(suite.T(), 32, cfg.MaxTagLength)
+	assert.Equal(suite.T(), 20, cfg.MaxMetadataKeys)
+	assert.Equal(suite.T(), 64, cfg.MaxMetadataKeyLen)
+	assert.Equal(suite.T(), 256, cfg.MaxMetadataValueLen)
+	assert.Empty(suite.T(), cfg.CollectionPrefix)
+	assert.True(suite.T(), cfg.CreateIndexes)
+	assert.Zero(suite.T(), cfg.RepoRetries)
+	assert.Equal(suite.T(), 100*time.Millisecond, cfg.RepoRetryBackoff)
+	assert.Equal(suite.T(), time.UTC, cfg.Location)
+	assert.Equal(suite.T(), "info", cfg.LogLevel)
+	assert.Equal(suite.T(), "text", cfg.LogFormat)
+	assert.Empty(suite.T(), cfg.CORS.AllowedOrigins)
+	assert.False(suite.T(), cfg.CORS.AllowCredentials)
+	assert.Equal(suite.T(), 10*time.Minute, cfg.CORS.MaxAge)
+	assert.False(suite.T(), cfg.TLSEnabled())
+	assert.Empty(suite.T(), cfg.AdminAddr)
+}
+
+// TestLoadConfig_InvalidDuration tests that a malformed duration falls back to the default
+func (suite *ConfigTestSuite) TestLoadConfig_InvalidDuration() {
+	os.Setenv("JWT_REMEMBER_ME_EXPIRY", "forever")
+	defer os.Unsetenv("JWT_REMEMBER_ME_EXPIRY")
+
+	cfg := LoadConfig()
+
+	assert.Equal(suite.T(), 30*24*time.Hour, cfg.JWTRememberMeExpiry)
+}
+
+// TestLoadConfig_InvalidTimezone tests that an unknown timezone falls back to UTC
+func (suite *ConfigTestSuite) TestLoadConfig_InvalidTimezone() {
+	os.Setenv("APP_TIMEZONE", "Mars/Olympus_Mons")
+	defer os.Unsetenv("APP_TIMEZONE")
+
+	cfg := LoadConfig()
+
+	assert.Equal(suite.T(), time.UTC, cfg.Location)
+}
+
+// TestLoadConfig_LegacyTimezone tests that DUE_DATE_TIMEZONE is still honoured
+// when APP_TIMEZONE is not set
+func (suite *ConfigTestSuite) TestLoadConfig_LegacyTimezone() {
+	os.Setenv("DUE_DATE_TIMEZONE", "Europe/Berlin")
+	defer os.Unsetenv("DUE_DATE_TIMEZONE")
+
+	assert.Equal(suite.T(), "Europe/Berlin", LoadConfig().Location.String())
+
+	os.Setenv("APP_TIMEZONE", "Asia/Tokyo")
+	defer os.Unsetenv("APP_TIMEZONE")
+
+	assert.Equal(suite.T(), "Asia/Tokyo", LoadConfig().Location.String())
+}
+
+// TestLoadConfig_DefaultTaskStatus tests that a known status is used and an unknown one falls back to pending
+func (suite *ConfigTestSuite) TestLoadConfig_DefaultTaskStatus() {
+	defer os.Unsetenv("DEFAULT_TASK_STATUS")
+
+	os.Setenv("DEFAULT_TASK_STATUS", "in_progress")
+	assert.Equal(suite.T(), "in_progress", LoadConfig().DefaultTaskStatus)
+
+	os.Setenv("DEFAULT_TASK_STATUS", "started")
+	assert.Equal(suite.T(), "pending", LoadConfig().DefaultTaskStatus)
+}
+
+// TestLoadConfig_CORS tests the CORS settings read from the environment
+func (suite *ConfigTestSuite) TestLoadConfig_CORS() {
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://admin.example.com,")
+	os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	os.Setenv("CORS_MAX_AGE", "1h")
+	defer func() {
+		os.Unsetenv("CORS_ALLOWED_ORIGINS")
+		os.Unsetenv("CORS_ALLOW_CREDENTIALS")
+		os.Unsetenv("CORS_MAX_AGE")
+	}()
+
+	cfg := LoadConfig()
+
+	assert.Equal(suite.T(), []string{"https://app.example.com", "https://admin.example.com"}, cfg.CORS.AllowedOrigins)
+	assert.True(suite.T(), cfg.CORS.AllowCredentials)
+	assert.Equal(suite.T(), time.Hour, cfg.CORS.MaxAge)
+}
+
+// TestLoadConfig_FromEnvironment tests values read from the environment
+func (suite *ConfigTestSuite) TestLoadConfig_FromEnvironment() {
+	os.Setenv("JWT_ALG", "RS256")
+	os.Setenv("JWT_PRIVATE_KEY_PATH", "/keys/jwt.key")
+	os.Setenv("JWT_PUBLIC_KEY_PATH", "/keys/jwt.pub")
+	os.Setenv("COLLECTION_PREFIX", "tenant1_")
+	os.Setenv("APP_TIMEZONE", "America/New_York")
+	defer func() {
+		os.Unsetenv("JWT_ALG")
+		os.Unsetenv("JWT_PRIVATE_KEY_PATH")
+		os.Unsetenv("JWT_PUBLIC_KEY_PATH")
+		os.Unsetenv("COLLECTION_PREFIX")
+		os.Unsetenv("APP_TIMEZONE")
+	}()
+
+	cfg := LoadConfig()
+
+	assert.Equal(suite.T(), "RS256", cfg.JWTAlgorithm)
+	assert.Equal(suite.T(), "/keys/jwt.key", cfg.JWTPrivateKeyPath)
+	assert.Equal(suite.T(), "/keys/jwt.pub", cfg.JWTPublicKeyPath)
+	assert.Equal(suite.T(), "tenant1_", cfg.CollectionPrefix)
+	assert.Equal(suite.T(), "America/New_York", cfg.Location.String())
+}
+
+// Run the test suite
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSONContentType rejects write requests whose body is not declared as
+// application/json with 415 Unsupported Media Type. GET, HEAD, DELETE and
+// OPTIONS requests, and requests without a body, pass through untouched.
+func RequireJSONContentType() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != gin.MIMEJSON {
+			abortWithError(c, http.StatusUnsupportedMediaType, "content type must be application/json")
+			return
+		}
+		c.Next()
+	}
+}
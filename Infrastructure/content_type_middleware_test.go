@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// ContentTypeMiddlewareTestSuite groups the JSON content type middleware tests
+type ContentTypeMiddlewareTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// SetupSuite runs once before all tests
+func (suite *ContentTypeMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *ContentTypeMiddlewareTestSuite) SetupTest() {
+	suite.router = gin.New()
+	suite.router.Use(RequireJSONContentType())
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	}
+	suite.router.GET("/tasks", handler)
+	suite.router.POST("/tasks", handler)
+	suite.router.PUT("/tasks/1", handler)
+	suite.router.DELETE("/tasks/1", handler)
+}
+
+func (suite *ContentTypeMiddlewareTestSuite) serve(method, contentType, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, "/tasks", strings.NewReader(body))
+	if method == http.MethodPut || method == http.MethodDelete {
+		req, _ = http.NewRequest(method, "/tasks/1", strings.NewReader(body))
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestRequireJSONContentType_TextPlainPost tests that a text/plain POST is rejected
+func (suite *ContentTypeMiddlewareTestSuite) TestRequireJSONContentType_TextPlainPost() {
+	resp := suite.serve(http.MethodPost, "text/plain", "title=Task")
+
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "content type must be application/json"}`, resp.Body.String())
+}
+
+// TestRequireJSONContentType_MissingHeaderPut tests that a PUT body without a content type is rejected
+func (suite *ContentTypeMiddlewareTestSuite) TestRequireJSONContentType_MissingHeaderPut() {
+	resp := suite.serve(http.MethodPut, "", `{"title": "Task"}`)
+
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, resp.Code)
+}
+
+// TestRequireJSONContentType_JSONWithCharset tests that parameters on the media type are accepted
+func (suite *ContentTypeMiddlewareTestSuite) TestRequireJSONContentType_JSONWithCharset() {
+	resp := suite.serve(http.MethodPost, "application/json; charset=utf-8", `{"title": "Task"}`)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+}
+
+// TestRequireJSONContentType_ExemptMethods tests that GET and DELETE are never checked
+func (suite *ContentTypeMiddlewareTestSuite) TestRequireJSONContentType_ExemptMethods() {
+	assert.Equal(suite.T(), http.StatusOK, suite.serve(http.MethodGet, "text/plain", "ignored").Code)
+	assert.Equal(suite.T(), http.StatusOK, suite.serve(http.MethodDelete, "text/plain", "ignored").Code)
+}
+
+// TestRequireJSONContentType_EmptyBody tests that a write request without a body passes
+func (suite *ContentTypeMiddlewareTestSuite) TestRequireJSONContentType_EmptyBody() {
+	resp := suite.serve(http.MethodPost, "", "")
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+}
+
+// Run the test suite
+func TestContentTypeMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(ContentTypeMiddlewareTestSuite))
+}
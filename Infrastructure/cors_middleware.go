@@ -0,0 +1,93 @@
+package infrastructure
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig controls which browser origins may call the API. An empty
+// AllowedOrigins disables CORS headers entirely; "*" allows any origin.
+// AllowCredentials lets browsers send cookies and Authorization headers, and
+// MaxAge is how long a preflight response may be cached.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// Validate rejects allowing credentials from any origin. Browsers refuse "*"
+// with credentials, and echoing every origin instead would let any site make
+// authenticated requests on a user's behalf.
+func (cfg CORSConfig) Validate() error {
+	if cfg.allowsAny() && cfg.AllowCredentials {
+		return errors.New(`CORS_ALLOW_CREDENTIALS cannot be combined with CORS_ALLOWED_ORIGINS "*"; list the origins instead`)
+	}
+	return nil
+}
+
+// corsAllowedMethods and corsAllowedHeaders are advertised in preflight
+// responses, and corsExposedHeaders lets scripts read those response headers
+const (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowedHeaders = "Authorization, Content-Type, X-API-Key, " + ConfirmationHeader
+	corsExposedHeaders = "Location, Link"
+)
+
+// CORSMiddleware adds CORS headers for allowed origins and answers preflight
+// requests with 204. Allowed origins are echoed unless any origin is allowed.
+// cfg must pass Validate.
+func CORSMiddleware(cfg CORSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || !cfg.allows(origin) {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		if cfg.allowsAny() {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Add("Vary", "Origin")
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge/time.Second)))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		c.Next()
+	}
+}
+
+func (cfg CORSConfig) allowsAny() bool {
+	for _, allowed := range cfg.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg CORSConfig) allows(origin string) bool {
+	for _, allowed := range cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// CORSMiddlewareTestSuite groups the CORS middleware tests
+type CORSMiddlewareTestSuite struct {
+	suite.Suite
+}
+
+// SetupSuite runs once before all tests
+func (suite *CORSMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *CORSMiddlewareTestSuite) serve(cfg CORSConfig, method, origin string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(CORSMiddleware(cfg))
+	router.GET("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(method, "/tasks", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestCORS_Preflight tests that a preflight reflects the configured credentials and max age
+func (suite *CORSMiddlewareTestSuite) TestCORS_Preflight() {
+	cfg := CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true, MaxAge: 2 * time.Hour}
+
+	resp := suite.serve(cfg, http.MethodOptions, "https://app.example.com")
+
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)
+	assert.Equal(suite.T(), "https://app.example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(suite.T(), "true", resp.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(suite.T(), "7200", resp.Header().Get("Access-Control-Max-Age"))
+	assert.NotEmpty(suite.T(), resp.Header().Get("Access-Control-Allow-Methods"))
+	assert.Contains(suite.T(), resp.Header().Get("Access-Control-Allow-Headers"), ConfirmationHeader)
+}
+
+// TestCORS_WithoutCredentials tests that credentials and max age are omitted when not configured
+func (suite *CORSMiddlewareTestSuite) TestCORS_WithoutCredentials() {
+	cfg := CORSConfig{AllowedOrigins: []string{"*"}}
+
+	resp := suite.serve(cfg, http.MethodOptions, "https://app.example.com")
+
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)
+	assert.Equal(suite.T(), "*", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(suite.T(), resp.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(suite.T(), resp.Header().Get("Access-Control-Max-Age"))
+}
+
+// TestCORS_WildcardWithCredentials tests that allowing credentials from any origin is rejected
+func (suite *CORSMiddlewareTestSuite) TestCORS_WildcardWithCredentials() {
+	assert.Error(suite.T(), CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}.Validate())
+	assert.NoError(suite.T(), CORSConfig{AllowedOrigins: []string{"*"}}.Validate())
+	assert.NoError(suite.T(), CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}.Validate())
+}
+
+// TestCORS_ExposesHeaders tests that scripts may read the Location of created resources and pagination links
+func (suite *CORSMiddlewareTestSuite) TestCORS_ExposesHeaders() {
+	cfg := CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}
+
+	resp := suite.serve(cfg, http.MethodGet, "https://app.example.com")
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Contains(suite.T(), resp.Header().Get("Access-Control-Expose-Headers"), "Location")
+	assert.Contains(suite.T(), resp.Header().Get("Access-Control-Expose-Headers"), "Link")
+}
+
+// TestCORS_DisallowedOrigin tests that other origins get no CORS headers
+func (suite *CORSMiddlewareTestSuite) TestCORS_DisallowedOrigin() {
+	cfg := CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+
+	resp := suite.serve(cfg, http.MethodGet, "https://evil.example.com")
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Empty(suite.T(), resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(suite.T(), resp.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+// Run the test suite
+func TestCORSMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(CORSMiddlewareTestSuite))
+}
@@ -0,0 +1,20 @@
+package infrastructure
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// CamelCaseJSON has JSON responses name their fields in camelCase, so
+// created_at is sent as createdAt, for clients that expect that style. It
+// only records the choice in the request context under "camel_case_json";
+// the controllers rename struct fields as they encode, so the keys of
+// user-supplied maps such as task metadata are never touched. The struct
+// tags stay snake_case, and when camelCase is false nothing changes.
+func CamelCaseJSON(camelCase bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if camelCase {
+			c.Set("camel_case_json", true)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// JSONNamingMiddlewareTestSuite groups the response key naming tests
+type JSONNamingMiddlewareTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// SetupSuite runs once before all tests
+func (suite *JSONNamingMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *JSONNamingMiddlewareTestSuite) SetupTest() {
+	suite.router = gin.New()
+}
+
+// serve registers a handler answering with the naming choice it sees and
+// returns its response
+func (suite *JSONNamingMiddlewareTestSuite) serve(camelCase bool) *httptest.ResponseRecorder {
+	suite.router.Use(CamelCaseJSON(camelCase))
+	suite.router.GET("/tasks", func(c *gin.Context) {
+		_, exists := c.Get("camel_case_json")
+		c.JSON(http.StatusOK, gin.H{"created_at": "now", "set": exists, "camel_case": c.GetBool("camel_case_json")})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestCamelCaseJSON_Enabled tests that the choice is stored for the
+// controllers while the handler's own output is left alone
+func (suite *JSONNamingMiddlewareTestSuite) TestCamelCaseJSON_Enabled() {
+	resp := suite.serve(true)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"created_at": "now", "set": true, "camel_case": true}`, resp.Body.String())
+}
+
+// TestCamelCaseJSON_Disabled tests that nothing is stored when the option is off
+func (suite *JSONNamingMiddlewareTestSuite) TestCamelCaseJSON_Disabled() {
+	resp := suite.serve(false)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"created_at": "now", "set": false, "camel_case": false}`, resp.Body.String())
+}
+
+// Run the test suite
+func TestJSONNamingMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONNamingMiddlewareTestSuite))
+}
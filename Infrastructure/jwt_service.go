@@ -3,41 +3,256 @@
 package infrastructure
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
+	domain "Task-Management/Domain"
+
 	"github.com/golang-jwt/jwt"
 )
 
 var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
 
-// Claims represents the JWT claims
+// signingMethod is the algorithm used to sign and accept tokens. HS256 signs
+// with jwtSecret; RS256 uses the key pair loaded by ConfigureJWT.
+var (
+	signingMethod jwt.SigningMethod = jwt.SigningMethodHS256
+	rsaPrivateKey *rsa.PrivateKey
+	rsaPublicKey  *rsa.PublicKey
+)
+
+// Token lifetimes for regular and "remember me" sessions
+var (
+	tokenExpiry           = 24 * time.Hour
+	rememberMeTokenExpiry = 30 * 24 * time.Hour
+)
+
+// impersonationTokenExpiry keeps tokens issued to admins acting as a user
+// short-lived
+const impersonationTokenExpiry = 15 * time.Minute
+
+// confirmationTokenExpiry is how long a confirmation for a destructive admin
+// action stays usable
+const confirmationTokenExpiry = 2 * time.Minute
+
+// purposeConfirmation marks tokens that only confirm a destructive action and
+// must not authenticate requests
+const purposeConfirmation = "confirmation"
+
+// clock is consulted when issuing and checking token lifetimes
+var clock domain.Clock = SystemClock{}
+
+// Claims represents the JWT claims. ImpersonatedBy is set only on tokens an
+// admin obtained to act as the user, and names that admin. Purpose is empty
+// on session tokens.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID         string `json:"user_id"`
+	Role           string `json:"role"`
+	TokenVersion   int    `json:"token_version"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	Purpose        string `json:"purpose,omitempty"`
 	jwt.StandardClaims
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(userID, role string) (string, error) {
+// ConfigureJWT selects the signing algorithm. For RS256 the PEM encoded key
+// pair is read from the given paths; HS256 ignores them.
+func ConfigureJWT(alg, privateKeyPath, publicKeyPath string) error {
+	switch alg {
+	case "", jwt.SigningMethodHS256.Alg():
+		signingMethod = jwt.SigningMethodHS256
+		rsaPrivateKey, rsaPublicKey = nil, nil
+		return nil
+	case jwt.SigningMethodRS256.Alg():
+		privatePEM, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			return fmt.Errorf("reading JWT private key: %w", err)
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		if err != nil {
+			return fmt.Errorf("parsing JWT private key: %w", err)
+		}
+		publicPEM, err := os.ReadFile(publicKeyPath)
+		if err != nil {
+			return fmt.Errorf("reading JWT public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return fmt.Errorf("parsing JWT public key: %w", err)
+		}
+		signingMethod = jwt.SigningMethodRS256
+		rsaPrivateKey, rsaPublicKey = privateKey, publicKey
+		return nil
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+}
+
+func signingKey() interface{} {
+	if signingMethod == jwt.SigningMethodRS256 {
+		return rsaPrivateKey
+	}
+	return jwtSecret
+}
+
+func verificationKey() interface{} {
+	if signingMethod == jwt.SigningMethodRS256 {
+		return rsaPublicKey
+	}
+	return jwtSecret
+}
+
+// isExpectedMethod reports whether method belongs to the configured family
+// (HMAC or RSA) and matches the configured algorithm exactly
+func isExpectedMethod(method jwt.SigningMethod) bool {
+	switch signingMethod.(type) {
+	case *jwt.SigningMethodHMAC:
+		if _, ok := method.(*jwt.SigningMethodHMAC); !ok {
+			return false
+		}
+	case *jwt.SigningMethodRSA:
+		if _, ok := method.(*jwt.SigningMethodRSA); !ok {
+			return false
+		}
+	default:
+		return false
+	}
+	return method.Alg() == signingMethod.Alg()
+}
+
+// ConfigureTokenExpiry sets the lifetime of regular and "remember me" tokens.
+// Non-positive values leave the current setting unchanged.
+func ConfigureTokenExpiry(expiry, rememberMeExpiry time.Duration) {
+	if expiry > 0 {
+		tokenExpiry = expiry
+	}
+	if rememberMeExpiry > 0 {
+		rememberMeTokenExpiry = rememberMeExpiry
+	}
+}
+
+// ConfigureClock replaces the clock used for token lifetimes. A nil clock
+// restores the system clock.
+func ConfigureClock(c domain.Clock) {
+	if c == nil {
+		c = SystemClock{}
+	}
+	clock = c
+}
+
+// GenerateToken generates a new JWT token carrying the user's current
+// tokenVersion. When rememberMe is set the token uses the longer "remember
+// me" lifetime.
+func GenerateToken(userID, role string, tokenVersion int, rememberMe bool) (string, error) {
+	expiry := tokenExpiry
+	if rememberMe {
+		expiry = rememberMeTokenExpiry
+	}
+
+	now := clock.Now()
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:       userID,
+		Role:         role,
+		TokenVersion: tokenVersion,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
-			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: now.Add(expiry).Unix(),
+			IssuedAt:  now.Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token := jwt.NewWithClaims(signingMethod, claims)
+	return token.SignedString(signingKey())
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// GenerateImpersonationToken issues a token for the user that records the
+// admin acting as them in impersonated_by. It expires after
+// impersonationTokenExpiry.
+func GenerateImpersonationToken(userID, role string, tokenVersion int, impersonatedBy string) (string, error) {
+	now := clock.Now()
+	claims := Claims{
+		UserID:         userID,
+		Role:           role,
+		TokenVersion:   tokenVersion,
+		ImpersonatedBy: impersonatedBy,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: now.Add(impersonationTokenExpiry).Unix(),
+			IssuedAt:  now.Unix(),
+		},
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	return token.SignedString(signingKey())
+}
+
+// GenerateConfirmationToken issues a token letting userID go ahead with one
+// destructive action within confirmationTokenExpiry. It cannot be used to
+// authenticate. Each token gets a random ID so RequireConfirmation can
+// accept it only once.
+func GenerateConfirmationToken(userID string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	now := clock.Now()
+	claims := Claims{
+		UserID:  userID,
+		Purpose: purposeConfirmation,
+		StandardClaims: jwt.StandardClaims{
+			Id:        hex.EncodeToString(id),
+			ExpiresAt: now.Add(confirmationTokenExpiry).Unix(),
+			IssuedAt:  now.Unix(),
+		},
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	return token.SignedString(signingKey())
+}
+
+// ValidateConfirmationToken checks that tokenString is an unexpired
+// confirmation token issued to userID and returns its claims
+func ValidateConfirmationToken(tokenString, userID string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purposeConfirmation || claims.Id == "" {
+		return nil, errors.New("not a confirmation token")
+	}
+	if claims.UserID != userID {
+		return nil, errors.New("confirmation token was issued to another user")
+	}
+	return claims, nil
+}
+
+// ValidateToken validates a session JWT token and returns the claims.
+// Confirmation tokens are rejected.
 func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("token cannot be used for authentication")
+	}
+	return claims, nil
+}
+
+// parseToken verifies the signature and lifetime of tokenString and returns
+// its claims
+func parseToken(tokenString string) (*Claims, error) {
+	// Time-based claims are checked below against the configured clock
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		// Only accept the configured algorithm to prevent algorithm confusion
+		if !isExpectedMethod(token.Method) {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return verificationKey(), nil
 	})
 
 	if err != nil {
@@ -45,6 +260,13 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		now := clock.Now().Unix()
+		if !claims.VerifyExpiresAt(now, false) {
+			return nil, errors.New("token is expired")
+		}
+		if !claims.VerifyIssuedAt(now, false) || !claims.VerifyNotBefore(now, false) {
+			return nil, errors.New("token is not valid yet")
+		}
 		return claims, nil
 	}
 
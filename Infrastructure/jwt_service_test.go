@@ -1,7 +1,12 @@
 package infrastructure
 
 import (
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/x509"
+    "encoding/pem"
     "os"
+    "path/filepath"
     "testing"
     "time"
 
@@ -28,12 +33,35 @@ func (suite *JWTServiceTestSuite) TearDownSuite() {
     os.Unsetenv("JWT_SECRET")
 }
 
+// TearDownTest restores the default algorithm after each test
+func (suite *JWTServiceTestSuite) TearDownTest() {
+    assert.NoError(suite.T(), ConfigureJWT("HS256", "", ""))
+}
+
+// writeRSAKeyPair writes a freshly generated PEM key pair to a temp directory
+func (suite *JWTServiceTestSuite) writeRSAKeyPair() (string, string) {
+    key, err := rsa.GenerateKey(rand.Reader, 2048)
+    suite.Require().NoError(err)
+
+    publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+    suite.Require().NoError(err)
+
+    dir := suite.T().TempDir()
+    privatePath := filepath.Join(dir, "jwt.key")
+    publicPath := filepath.Join(dir, "jwt.pub")
+    privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+    publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+    suite.Require().NoError(os.WriteFile(privatePath, privatePEM, 0600))
+    suite.Require().NoError(os.WriteFile(publicPath, publicPEM, 0644))
+    return privatePath, publicPath
+}
+
 // TestGenerateToken tests token generation
 func (suite *JWTServiceTestSuite) TestGenerateToken() {
     userID := "12345"
     role := "user"
 
-    token, err := GenerateToken(userID, role)
+    token, err := GenerateToken(userID, role, 3, false)
     assert.NoError(suite.T(), err)
     assert.NotEmpty(suite.T(), token)
 
@@ -42,6 +70,7 @@ func (suite *JWTServiceTestSuite) TestGenerateToken() {
     assert.NoError(suite.T(), err)
     assert.Equal(suite.T(), userID, claims.UserID)
     assert.Equal(suite.T(), role, claims.Role)
+    assert.Equal(suite.T(), 3, claims.TokenVersion)
 }
 
 // TestValidateToken_ValidToken tests validation of a valid token
@@ -50,7 +79,7 @@ func (suite *JWTServiceTestSuite) TestValidateToken_ValidToken() {
     role := "admin"
 
     // Generate a valid token
-    token, err := GenerateToken(userID, role)
+    token, err := GenerateToken(userID, role, 0, false)
     assert.NoError(suite.T(), err)
 
     // Validate the token
@@ -97,7 +126,7 @@ func (suite *JWTServiceTestSuite) TestValidateToken_TamperedToken() {
     role := "user"
 
     // Generate a valid token
-    token, err := GenerateToken(userID, role)
+    token, err := GenerateToken(userID, role, 0, false)
     assert.NoError(suite.T(), err)
 
     // Tamper with the token
@@ -108,6 +137,186 @@ func (suite *JWTServiceTestSuite) TestValidateToken_TamperedToken() {
     assert.Nil(suite.T(), claims)
 }
 
+// TestGenerateToken_RS256 tests a token round trip with an RSA key pair
+func (suite *JWTServiceTestSuite) TestGenerateToken_RS256() {
+    privatePath, publicPath := suite.writeRSAKeyPair()
+    assert.NoError(suite.T(), ConfigureJWT("RS256", privatePath, publicPath))
+
+    token, err := GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+
+    parsed, _, err := new(jwt.Parser).ParseUnverified(token, &Claims{})
+    assert.NoError(suite.T(), err)
+    assert.Equal(suite.T(), "RS256", parsed.Header["alg"])
+
+    claims, err := ValidateToken(token)
+    assert.NoError(suite.T(), err)
+    assert.Equal(suite.T(), "12345", claims.UserID)
+}
+
+// TestValidateToken_AlgorithmMismatch tests that a token signed with a different algorithm is rejected
+func (suite *JWTServiceTestSuite) TestValidateToken_AlgorithmMismatch() {
+    hsToken, err := GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+
+    privatePath, publicPath := suite.writeRSAKeyPair()
+    assert.NoError(suite.T(), ConfigureJWT("RS256", privatePath, publicPath))
+
+    claims, err := ValidateToken(hsToken)
+    assert.Error(suite.T(), err)
+    assert.Nil(suite.T(), claims)
+
+    rsToken, err := GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+    assert.NoError(suite.T(), ConfigureJWT("HS256", "", ""))
+
+    claims, err = ValidateToken(rsToken)
+    assert.Error(suite.T(), err)
+    assert.Nil(suite.T(), claims)
+}
+
+// TestConfigureJWT_Invalid tests unsupported algorithms and missing key files
+func (suite *JWTServiceTestSuite) TestConfigureJWT_Invalid() {
+    assert.Error(suite.T(), ConfigureJWT("ES256", "", ""))
+    assert.Error(suite.T(), ConfigureJWT("RS256", "/nonexistent/jwt.key", "/nonexistent/jwt.pub"))
+}
+
+// TestValidateToken_AlgNone tests that an unsigned token with alg "none" is rejected
+func (suite *JWTServiceTestSuite) TestValidateToken_AlgNone() {
+    claims := Claims{
+        UserID: "12345",
+        Role:   "admin",
+        StandardClaims: jwt.StandardClaims{
+            ExpiresAt: time.Now().Add(time.Hour).Unix(),
+            IssuedAt:  time.Now().Unix(),
+        },
+    }
+
+    token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+    tokenString, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+    assert.NoError(suite.T(), err)
+
+    parsedClaims, err := ValidateToken(tokenString)
+    assert.Error(suite.T(), err)
+    assert.Nil(suite.T(), parsedClaims)
+}
+
+// TestValidateToken_OtherHMACAlgorithm tests that a different HMAC variant is rejected
+func (suite *JWTServiceTestSuite) TestValidateToken_OtherHMACAlgorithm() {
+    claims := Claims{
+        UserID: "12345",
+        Role:   "user",
+        StandardClaims: jwt.StandardClaims{
+            ExpiresAt: time.Now().Add(time.Hour).Unix(),
+            IssuedAt:  time.Now().Unix(),
+        },
+    }
+
+    token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+    tokenString, err := token.SignedString(jwtSecret)
+    assert.NoError(suite.T(), err)
+
+    parsedClaims, err := ValidateToken(tokenString)
+    assert.Error(suite.T(), err)
+    assert.Nil(suite.T(), parsedClaims)
+}
+
+// TestGenerateToken_RememberMe tests that remember me tokens use the longer expiry
+func (suite *JWTServiceTestSuite) TestGenerateToken_RememberMe() {
+    ConfigureTokenExpiry(time.Hour, 72*time.Hour)
+    defer ConfigureTokenExpiry(24*time.Hour, 30*24*time.Hour)
+
+    shortToken, err := GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+    longToken, err := GenerateToken("12345", "user", 0, true)
+    assert.NoError(suite.T(), err)
+
+    shortClaims, err := ValidateToken(shortToken)
+    assert.NoError(suite.T(), err)
+    longClaims, err := ValidateToken(longToken)
+    assert.NoError(suite.T(), err)
+
+    assert.Equal(suite.T(), int64(time.Hour.Seconds()), shortClaims.ExpiresAt-shortClaims.IssuedAt)
+    assert.Equal(suite.T(), int64((72 * time.Hour).Seconds()), longClaims.ExpiresAt-longClaims.IssuedAt)
+}
+
+// TestValidateToken_ExpiryBoundary tests expiry against an advancing fake clock
+func (suite *JWTServiceTestSuite) TestValidateToken_ExpiryBoundary() {
+    fakeClock := NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+    ConfigureClock(fakeClock)
+    defer ConfigureClock(nil)
+    ConfigureTokenExpiry(time.Hour, 72*time.Hour)
+    defer ConfigureTokenExpiry(24*time.Hour, 30*24*time.Hour)
+
+    token, err := GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+
+    fakeClock.Advance(time.Hour)
+    _, err = ValidateToken(token)
+    assert.NoError(suite.T(), err, "token is valid up to its expiry second")
+
+    fakeClock.Advance(time.Second)
+    claims, err := ValidateToken(token)
+    assert.Error(suite.T(), err)
+    assert.Nil(suite.T(), claims)
+}
+
+// TestValidateToken_IssuedInFuture tests that a token is rejected before its issue time
+func (suite *JWTServiceTestSuite) TestValidateToken_IssuedInFuture() {
+    fakeClock := NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+    ConfigureClock(fakeClock)
+    defer ConfigureClock(nil)
+
+    token, err := GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+
+    fakeClock.Advance(-time.Minute)
+    _, err = ValidateToken(token)
+    assert.Error(suite.T(), err)
+}
+
+// TestGenerateImpersonationToken tests that the token carries the target user and the impersonating admin and expires quickly
+func (suite *JWTServiceTestSuite) TestGenerateImpersonationToken() {
+    token, err := GenerateImpersonationToken("12345", "user", 2, "admin-1")
+    assert.NoError(suite.T(), err)
+
+    claims, err := ValidateToken(token)
+    assert.NoError(suite.T(), err)
+    assert.Equal(suite.T(), "12345", claims.UserID)
+    assert.Equal(suite.T(), "user", claims.Role)
+    assert.Equal(suite.T(), 2, claims.TokenVersion)
+    assert.Equal(suite.T(), "admin-1", claims.ImpersonatedBy)
+    assert.Equal(suite.T(), int64(impersonationTokenExpiry.Seconds()), claims.ExpiresAt-claims.IssuedAt)
+
+    // Regular tokens carry no impersonator
+    token, err = GenerateToken("12345", "user", 0, false)
+    assert.NoError(suite.T(), err)
+    claims, err = ValidateToken(token)
+    assert.NoError(suite.T(), err)
+    assert.Empty(suite.T(), claims.ImpersonatedBy)
+}
+
+// TestGenerateConfirmationToken tests that a confirmation token is accepted for its user only and never as a session token
+func (suite *JWTServiceTestSuite) TestGenerateConfirmationToken() {
+    token, err := GenerateConfirmationToken("12345")
+    assert.NoError(suite.T(), err)
+
+    claims, err := ValidateConfirmationToken(token, "12345")
+    assert.NoError(suite.T(), err)
+    assert.NotEmpty(suite.T(), claims.Id)
+    _, err = ValidateConfirmationToken(token, "67890")
+    assert.Error(suite.T(), err)
+
+    _, err = ValidateToken(token)
+    assert.Error(suite.T(), err)
+
+    // Session tokens do not confirm anything
+    token, err = GenerateToken("12345", "admin", 0, false)
+    assert.NoError(suite.T(), err)
+    _, err = ValidateConfirmationToken(token, "12345")
+    assert.Error(suite.T(), err)
+}
+
 // Run the test suite
 func TestJWTServiceTestSuite(t *testing.T) {
     suite.Run(t, new(JWTServiceTestSuite))
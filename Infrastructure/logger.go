@@ -0,0 +1,54 @@
+// Structured logging setup shared by the middleware and repositories.
+
+package infrastructure
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigureLogging installs the process-wide slog logger writing to w.
+// level is one of debug, info, warn or error and format is text or json.
+// Messages from the standard log package are routed through the same logger.
+func ConfigureLogging(level, format string, w io.Writer) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: must be debug, info, warn or error", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// RequestLogger logs the method, path, status and latency of every request at
+// debug level, so the details are only written when LOG_LEVEL=debug
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		slog.DebugContext(c.Request.Context(), "request handled",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// LoggerTestSuite groups the logging setup tests
+type LoggerTestSuite struct {
+	suite.Suite
+	original *slog.Logger
+	output   *bytes.Buffer
+}
+
+// SetupSuite runs once before all tests
+func (suite *LoggerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *LoggerTestSuite) SetupTest() {
+	suite.original = slog.Default()
+	suite.output = new(bytes.Buffer)
+}
+
+// TearDownTest runs after each test
+func (suite *LoggerTestSuite) TearDownTest() {
+	slog.SetDefault(suite.original)
+}
+
+// TestConfigureLogging_InfoOmitsDebug tests that debug logs are dropped at info level
+func (suite *LoggerTestSuite) TestConfigureLogging_InfoOmitsDebug() {
+	assert.NoError(suite.T(), ConfigureLogging("info", "text", suite.output))
+
+	slog.Debug("debug details")
+	slog.Info("server started")
+
+	assert.NotContains(suite.T(), suite.output.String(), "debug details")
+	assert.Contains(suite.T(), suite.output.String(), "server started")
+}
+
+// TestConfigureLogging_JSON tests that the json format writes one object per line
+func (suite *LoggerTestSuite) TestConfigureLogging_JSON() {
+	assert.NoError(suite.T(), ConfigureLogging("DEBUG", "json", suite.output))
+
+	slog.Debug("debug details", "task_id", "42")
+
+	var entry map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(suite.output.Bytes(), &entry))
+	assert.Equal(suite.T(), "DEBUG", entry["level"])
+	assert.Equal(suite.T(), "debug details", entry["msg"])
+	assert.Equal(suite.T(), "42", entry["task_id"])
+}
+
+// TestConfigureLogging_Invalid tests that unknown levels and formats are rejected
+func (suite *LoggerTestSuite) TestConfigureLogging_Invalid() {
+	assert.Error(suite.T(), ConfigureLogging("verbose", "text", suite.output))
+	assert.Error(suite.T(), ConfigureLogging("info", "xml", suite.output))
+	assert.Same(suite.T(), suite.original, slog.Default())
+}
+
+// TestRequestLogger tests that request details are only written at debug level
+func (suite *LoggerTestSuite) TestRequestLogger() {
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/tasks", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	assert.NoError(suite.T(), ConfigureLogging("info", "text", suite.output))
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Empty(suite.T(), suite.output.String())
+
+	assert.NoError(suite.T(), ConfigureLogging("debug", "text", suite.output))
+	req, _ = http.NewRequest(http.MethodGet, "/tasks", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Contains(suite.T(), suite.output.String(), "method=GET")
+	assert.Contains(suite.T(), suite.output.String(), "path=/tasks")
+	assert.Contains(suite.T(), suite.output.String(), "status=204")
+}
+
+// Run the test suite
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceMessage is returned to every request blocked by maintenance mode
+const maintenanceMessage = "service is under maintenance; try again later"
+
+// MaintenanceMode is a switch shared by the Maintenance middleware and the
+// admin endpoint that toggles it at runtime. It is safe for concurrent use.
+type MaintenanceMode struct {
+	enabled    atomic.Bool
+	retryAfter time.Duration
+}
+
+// NewMaintenanceMode returns a switch starting in the given state. Blocked
+// clients are told to retry after retryAfter.
+func NewMaintenanceMode(enabled bool, retryAfter time.Duration) *MaintenanceMode {
+	mode := &MaintenanceMode{retryAfter: retryAfter}
+	mode.enabled.Store(enabled)
+	return mode
+}
+
+// Enabled reports whether requests are currently being blocked
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// SetEnabled turns maintenance mode on or off
+func (m *MaintenanceMode) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Maintenance answers every request with 503 Service Unavailable and a
+// Retry-After header while mode is enabled. Routes that must stay reachable,
+// such as health probes and the toggle itself, are registered without it.
+func Maintenance(mode *MaintenanceMode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mode.Enabled() {
+			c.Next()
+			return
+		}
+		if mode.retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(mode.retryAfter/time.Second)))
+		}
+		abortWithError(c, http.StatusServiceUnavailable, maintenanceMessage)
+	}
+}
@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// MaintenanceMiddlewareTestSuite groups the maintenance mode middleware tests
+type MaintenanceMiddlewareTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+	mode   *MaintenanceMode
+}
+
+// SetupSuite runs once before all tests
+func (suite *MaintenanceMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *MaintenanceMiddlewareTestSuite) SetupTest() {
+	suite.mode = NewMaintenanceMode(false, 2*time.Minute)
+	suite.router = gin.New()
+	suite.router.GET("/tasks", Maintenance(suite.mode), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+}
+
+func (suite *MaintenanceMiddlewareTestSuite) get() *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestMaintenance_Off tests that requests pass through when maintenance mode is off
+func (suite *MaintenanceMiddlewareTestSuite) TestMaintenance_Off() {
+	resp := suite.get()
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Empty(suite.T(), resp.Header().Get("Retry-After"))
+}
+
+// TestMaintenance_On tests that requests are answered with 503 and Retry-After when maintenance mode is on
+func (suite *MaintenanceMiddlewareTestSuite) TestMaintenance_On() {
+	suite.mode.SetEnabled(true)
+
+	resp := suite.get()
+
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(suite.T(), "120", resp.Header().Get("Retry-After"))
+	assert.JSONEq(suite.T(), `{"message": "service is under maintenance; try again later"}`, resp.Body.String())
+}
+
+// TestMaintenance_Toggled tests that switching the mode off again lets requests through
+func (suite *MaintenanceMiddlewareTestSuite) TestMaintenance_Toggled() {
+	suite.mode.SetEnabled(true)
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, suite.get().Code)
+
+	suite.mode.SetEnabled(false)
+	assert.Equal(suite.T(), http.StatusOK, suite.get().Code)
+}
+
+// TestMaintenance_NoRetryAfter tests that the header is left out when no retry delay is configured
+func (suite *MaintenanceMiddlewareTestSuite) TestMaintenance_NoRetryAfter() {
+	mode := NewMaintenanceMode(true, 0)
+	router := gin.New()
+	router.GET("/tasks", Maintenance(mode), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.Code)
+	assert.Empty(suite.T(), resp.Header().Get("Retry-After"))
+}
+
+// Run the test suite
+func TestMaintenanceMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceMiddlewareTestSuite))
+}
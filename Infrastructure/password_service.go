@@ -3,12 +3,27 @@
 package infrastructure
 
 import (
+	"fmt"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// bcryptCost is the work factor used for new password hashes
+var bcryptCost = bcrypt.DefaultCost
+
+// ConfigurePasswordCost sets the bcrypt cost used for new hashes. Existing
+// hashes at a lower cost are upgraded on login, see NeedsRehash.
+func ConfigurePasswordCost(cost int) error {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost %d out of range [%d, %d]", cost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	bcryptCost = cost
+	return nil
+}
+
 // HashPassword hashes a password using bcrypt
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
 	return string(bytes), err
 }
 
@@ -17,3 +32,13 @@ func ComparePasswords(hashedPassword, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// NeedsRehash reports whether hash was made with a lower cost than the one
+// currently configured. Hashes that cannot be parsed are left alone.
+func NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	return cost < bcryptCost
+}
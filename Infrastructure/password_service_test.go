@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // PasswordServiceTestSuite groups all password service-related tests
@@ -66,6 +67,29 @@ func (suite *PasswordServiceTestSuite) TestHashPassword_UniqueHashes() {
 	assert.NotEqual(suite.T(), hashedPassword1, hashedPassword2)
 }
 
+// TestNeedsRehash tests detecting hashes made with a lower cost
+func (suite *PasswordServiceTestSuite) TestNeedsRehash() {
+	defer ConfigurePasswordCost(bcrypt.DefaultCost)
+
+	oldHash, err := HashPassword(suite.password)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), NeedsRehash(oldHash))
+
+	assert.NoError(suite.T(), ConfigurePasswordCost(bcrypt.DefaultCost+1))
+	assert.True(suite.T(), NeedsRehash(oldHash))
+
+	newHash, err := HashPassword(suite.password)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), NeedsRehash(newHash))
+	assert.False(suite.T(), NeedsRehash("not a bcrypt hash"))
+}
+
+// TestConfigurePasswordCost_OutOfRange tests that invalid costs are rejected
+func (suite *PasswordServiceTestSuite) TestConfigurePasswordCost_OutOfRange() {
+	assert.Error(suite.T(), ConfigurePasswordCost(bcrypt.MaxCost+1))
+	assert.Error(suite.T(), ConfigurePasswordCost(bcrypt.MinCost-1))
+}
+
 // Run the test suite
 func TestPasswordServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(PasswordServiceTestSuite))
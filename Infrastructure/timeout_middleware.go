@@ -0,0 +1,99 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout bounds the request context of the routes it guards to d, so
+// database calls made with ctx.Request.Context() are cancelled once it
+// passes. The handler's response is buffered; if the deadline was exceeded
+// it is discarded and 504 Gateway Timeout is returned instead.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		buffer := &bufferedWriter{ResponseWriter: original, header: original.Header().Clone()}
+		c.Writer = buffer
+		c.Next()
+		c.Writer = original
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			abortWithError(c, http.StatusGatewayTimeout, "request timed out")
+			return
+		}
+		buffer.flush()
+	}
+}
+
+// bufferedWriter holds a handler's headers, status and body until Timeout
+// decides whether to send them
+type bufferedWriter struct {
+	gin.ResponseWriter
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0
+}
+
+// flush copies the buffered response to the underlying writer
+func (w *bufferedWriter) flush() {
+	if w.status == 0 {
+		return
+	}
+	dst := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		dst[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
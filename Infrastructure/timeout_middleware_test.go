@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// TimeoutMiddlewareTestSuite groups the request timeout middleware tests
+type TimeoutMiddlewareTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// SetupSuite runs once before all tests
+func (suite *TimeoutMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *TimeoutMiddlewareTestSuite) SetupTest() {
+	suite.router = gin.New()
+}
+
+// TestTimeout_FastHandler tests that a response within the deadline is passed through
+func (suite *TimeoutMiddlewareTestSuite) TestTimeout_FastHandler() {
+	suite.router.GET("/stats", Timeout(time.Second), func(c *gin.Context) {
+		c.Header("X-Total-Count", "3")
+		c.JSON(http.StatusCreated, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/stats", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, resp.Code)
+	assert.Equal(suite.T(), "3", resp.Header().Get("X-Total-Count"))
+	assert.JSONEq(suite.T(), `{"message": "success"}`, resp.Body.String())
+}
+
+// TestTimeout_SlowHandler tests that a slow handler is cancelled and answered with 504
+func (suite *TimeoutMiddlewareTestSuite) TestTimeout_SlowHandler() {
+	cancelled := false
+	suite.router.GET("/stats", Timeout(10*time.Millisecond), func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			cancelled = true
+			c.JSON(http.StatusInternalServerError, gin.H{"error": c.Request.Context().Err().Error()})
+		case <-time.After(time.Second):
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		}
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/stats", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.True(suite.T(), cancelled)
+	assert.Equal(suite.T(), http.StatusGatewayTimeout, resp.Code)
+	assert.JSONEq(suite.T(), `{"message": "request timed out"}`, resp.Body.String())
+}
+
+// Run the test suite
+func TestTimeoutMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutMiddlewareTestSuite))
+}
@@ -0,0 +1,22 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimestampFormat has JSON responses format their timestamps with layout, so
+// clients see one precision whatever the stored value. It only records the
+// layout in the request context under "timestamp_layout"; the controllers
+// apply it to time values as they encode, so strings in user data that look
+// like timestamps are never rewritten. An empty layout or time.RFC3339Nano
+// leaves Go's encoding as it is.
+func TimestampFormat(layout string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if layout != "" && layout != time.RFC3339Nano {
+			c.Set("timestamp_layout", layout)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// TimestampMiddlewareTestSuite groups the response timestamp format tests
+type TimestampMiddlewareTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// SetupSuite runs once before all tests
+func (suite *TimestampMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// SetupTest runs before each test
+func (suite *TimestampMiddlewareTestSuite) SetupTest() {
+	suite.router = gin.New()
+}
+
+// serve registers a handler answering with the layout it sees and a string
+// that looks like a timestamp, and returns its response
+func (suite *TimestampMiddlewareTestSuite) serve(layout string) *httptest.ResponseRecorder {
+	suite.router.Use(TimestampFormat(layout))
+	suite.router.GET("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"layout": c.GetString("timestamp_layout"), "title": "2024-01-02T03:04:05.123456789Z"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestTimestampFormat_Layout tests that the layout is stored for the
+// controllers while the handler's own output is left alone
+func (suite *TimestampMiddlewareTestSuite) TestTimestampFormat_Layout() {
+	resp := suite.serve(time.RFC3339)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"layout": "2006-01-02T15:04:05Z07:00", "title": "2024-01-02T03:04:05.123456789Z"}`, resp.Body.String())
+}
+
+// TestTimestampFormat_Passthrough tests that an empty layout or RFC3339Nano
+// stores nothing
+func (suite *TimestampMiddlewareTestSuite) TestTimestampFormat_Passthrough() {
+	for _, layout := range []string{"", time.RFC3339Nano} {
+		suite.SetupTest()
+		resp := suite.serve(layout)
+
+		assert.JSONEq(suite.T(), `{"layout": "", "title": "2024-01-02T03:04:05.123456789Z"}`, resp.Body.String())
+	}
+}
+
+// Run the test suite
+func TestTimestampMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(TimestampMiddlewareTestSuite))
+}
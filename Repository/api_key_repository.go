@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// apiKeyRepository implements domain.APIKeyRepository
+type apiKeyRepository struct {
+	collection CollectionInterface
+}
+
+// NewAPIKeyRepository initializes a new API key repository
+func NewAPIKeyRepository(db *mongo.Database, opts ...Option) domain.APIKeyRepository {
+	return &apiKeyRepository{
+		collection: wrapCollection(db.Collection(collectionName(domain.APIKeyCollection, opts)), resolveOptions(opts)),
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	key.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to parse inserted ID as ObjectID")
+	}
+	key.ID = id
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"key_hash": hash}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // Return nil if no document is found
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"revoked": true, "revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// auditRepository implements domain.AuditRepository
+type auditRepository struct {
+	collection CollectionInterface
+}
+
+// NewAuditRepository initializes a new audit log repository
+func NewAuditRepository(db *mongo.Database, opts ...Option) domain.AuditRepository {
+	return &auditRepository{
+		collection: wrapCollection(db.Collection(collectionName(domain.AuditCollection, opts)), resolveOptions(opts)),
+	}
+}
+
+// Create appends entry to the audit log. Entries are never changed or
+// removed.
+func (r *auditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to parse inserted ID as ObjectID")
+	}
+	entry.ID = id
+	return nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+// cursorCloser is the part of *mongo.Cursor closeCursor needs
+type cursorCloser interface {
+	Close(ctx context.Context) error
+}
+
+// closeCursor closes cursor and reports a failure through err, which should
+// be the caller's named error result. An error already set is kept, so a
+// failed close never hides the error that ended the read.
+func closeCursor(ctx context.Context, cursor cursorCloser, err *error) {
+	if closeErr := cursor.Close(ctx); closeErr != nil && *err == nil {
+		*err = closeErr
+	}
+}
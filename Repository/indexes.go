@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userIndexes keeps active emails unique. deleted_at is part of the key so a
+// soft-deleted account does not block registering its email again.
+var userIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "email", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	},
+}
+
+// taskIndexes speeds up the per-user, per-assignee and shared-with task
+// queries, the assignee one including the unassigned queue. The text index
+// backs Search; title matches weigh more than description matches.
+var taskIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	{Keys: bson.D{{Key: "assignee_id", Value: 1}}},
+	{Keys: bson.D{{Key: "shared_with", Value: 1}}},
+	{
+		Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "description", Value: 1}}),
+	},
+}
+
+// EnsureIndexes creates the indexes the repositories rely on in db, using
+// the collection names the repositories get from opts. Creating an index
+// that already exists is a no-op. It stops at the first collection whose
+// indexes cannot be created: without them emails are not kept unique and
+// Search fails, so the caller should not serve traffic.
+func EnsureIndexes(ctx context.Context, db *mongo.Database, opts ...Option) error {
+	indexes := []struct {
+		collection string
+		models     []mongo.IndexModel
+	}{
+		{domain.UserCollection, userIndexes},
+		{domain.TaskCollection, taskIndexes},
+		{domain.TaskHistoryCollection, taskHistoryIndexes},
+	}
+	for _, index := range indexes {
+		collection := db.Collection(collectionName(index.collection, opts))
+		if _, err := collection.Indexes().CreateMany(ctx, index.models); err != nil {
+			return fmt.Errorf("failed to create %s indexes: %w", collection.Name(), err)
+		}
+	}
+	return nil
+}
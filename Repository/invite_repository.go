@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// inviteRepository implements domain.InviteRepository
+type inviteRepository struct {
+	collection CollectionInterface
+}
+
+// NewInviteRepository initializes a new invite repository
+func NewInviteRepository(db *mongo.Database, opts ...Option) domain.InviteRepository {
+	return &inviteRepository{
+		collection: wrapCollection(db.Collection(collectionName(domain.InviteCollection, opts)), resolveOptions(opts)),
+	}
+}
+
+func (r *inviteRepository) Create(ctx context.Context, invite *domain.Invite) (*domain.Invite, error) {
+	invite.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, invite)
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to parse inserted ID as ObjectID")
+	}
+	invite.ID = id
+	return invite, nil
+}
+
+// Redeem marks the unused invite with code as used by email. The check and
+// the update are one operation, so two registrations cannot share a code.
+func (r *inviteRepository) Redeem(ctx context.Context, code, email string) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"code": code, "used": false},
+		bson.M{"$set": bson.M{"used": true, "used_by": email, "used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidInvite
+	}
+	return nil
+}
+
+// Release makes the invite with code usable again after email redeemed it
+// but could not be registered. An invite redeemed by another email is left
+// alone.
+func (r *inviteRepository) Release(ctx context.Context, code, email string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"code": code, "used": true, "used_by": email},
+		bson.M{
+			"$set":   bson.M{"used": false},
+			"$unset": bson.M{"used_by": "", "used_at": ""},
+		},
+	)
+	return err
+}
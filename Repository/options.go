@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Option customizes how a repository is constructed
+type Option func(*repositoryOptions)
+
+type repositoryOptions struct {
+	collectionPrefix string
+	retries          int
+	retryBackoff     time.Duration
+}
+
+// WithCollectionPrefix prepends prefix to the repository's collection name,
+// e.g. "tenant1_" stores tasks in "tenant1_tasks"
+func WithCollectionPrefix(prefix string) Option {
+	return func(o *repositoryOptions) {
+		o.collectionPrefix = prefix
+	}
+}
+
+// WithRetries retries reads that fail with a transient error, such as a
+// dropped connection, up to retries times. The wait starts at backoff and
+// doubles after each attempt. Other errors fail straight away.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(o *repositoryOptions) {
+		o.retries = retries
+		o.retryBackoff = backoff
+	}
+}
+
+// resolveOptions applies opts to the defaults
+func resolveOptions(opts []Option) repositoryOptions {
+	var o repositoryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// collectionName applies opts to the default collection name
+func collectionName(name string, opts []Option) string {
+	return resolveOptions(opts).collectionPrefix + name
+}
+
+// wrapCollection adapts collection to CollectionInterface, adding the
+// retries selected by o
+func wrapCollection(collection *mongo.Collection, o repositoryOptions) CollectionInterface {
+	return withRetry(&MongoCollectionWrapper{collection: collection}, o.retries, o.retryBackoff)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// paginate returns find options selecting page. Paged results are ordered by
+// _id so consecutive pages neither overlap nor skip records.
+func paginate(page domain.Pagination) *options.FindOptions {
+	opts := options.Find()
+	if page.Limit() > 0 {
+		opts.SetSort(bson.D{{Key: "_id", Value: 1}}).SetSkip(page.Skip()).SetLimit(page.Limit())
+	}
+	return opts
+}
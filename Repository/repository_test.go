@@ -2,14 +2,18 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log"
 	"testing"
+	"time"
 
 	domain "Task-Management/Domain"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -56,13 +60,57 @@ func (m *MockCollection) DeleteOne(ctx context.Context, filter interface{}) (*mo
 	return args.Get(0).(*mongo.DeleteResult), args.Error(1)
 }
 
+// MockCountCollection mocks CountDocuments; any other call panics
+type MockCountCollection struct {
+	CollectionInterface
+	mock.Mock
+}
+
+func (m *MockCountCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockDocumentCollection mocks the single-document calls the repositories
+// make; any other call panics
+type MockDocumentCollection struct {
+	CollectionInterface
+	mock.Mock
+}
+
+func (m *MockDocumentCollection) InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error) {
+	args := m.Called(ctx, document)
+	result, _ := args.Get(0).(*mongo.InsertOneResult)
+	return result, args.Error(1)
+}
+
+func (m *MockDocumentCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	args := m.Called(ctx, filter)
+	document := args.Get(0)
+	if document == nil {
+		// The driver needs a document even for a result carrying an error
+		document = bson.M{}
+	}
+	return mongo.NewSingleResultFromDocument(document, args.Error(1), nil)
+}
+
+func (m *MockDocumentCollection) UpdateOne(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error) {
+	args := m.Called(ctx, filter, update)
+	result, _ := args.Get(0).(*mongo.UpdateResult)
+	return result, args.Error(1)
+}
+
 // RepositoryTestSuite groups all repository-related tests
 type RepositoryTestSuite struct {
 	suite.Suite
-	client   *mongo.Client
-	db       *mongo.Database
-	taskRepo domain.TaskRepository
-	userRepo domain.UserRepository
+	client      *mongo.Client
+	db          *mongo.Database
+	taskRepo    domain.TaskRepository
+	userRepo    domain.UserRepository
+	apiKeyRepo  domain.APIKeyRepository
+	inviteRepo  domain.InviteRepository
+	auditRepo   domain.AuditRepository
+	historyRepo domain.TaskHistoryRepository
 }
 
 // SetupSuite runs once before all tests
@@ -81,6 +129,10 @@ func (suite *RepositoryTestSuite) SetupSuite() {
 	// Initialize repositories
 	suite.taskRepo = NewTaskRepository(suite.db)
 	suite.userRepo = NewUserRepository(suite.db)
+	suite.apiKeyRepo = NewAPIKeyRepository(suite.db)
+	suite.inviteRepo = NewInviteRepository(suite.db)
+	suite.auditRepo = NewAuditRepository(suite.db)
+	suite.historyRepo = NewTaskHistoryRepository(suite.db)
 }
 
 // TearDownSuite runs once after all tests
@@ -104,6 +156,10 @@ func (suite *RepositoryTestSuite) SetupTest() {
 	if err != nil {
 		log.Fatalf("Failed to clear users collection: %v", err)
 	}
+	// Dropping the collection dropped its indexes too
+	if err := EnsureIndexes(context.Background(), suite.db); err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
 }
 
 // TaskRepository Tests
@@ -153,6 +209,492 @@ func (suite *RepositoryTestSuite) TestTaskRepository_Delete() {
 	assert.Nil(suite.T(), result)
 }
 
+func (suite *RepositoryTestSuite) TestRepositories_CollectionPrefix() {
+	prefix := "tenant1_"
+	taskRepo := NewTaskRepository(suite.db, WithCollectionPrefix(prefix))
+	userRepo := NewUserRepository(suite.db, WithCollectionPrefix(prefix))
+
+	_, err := taskRepo.Create(context.Background(), &domain.Task{Title: "Prefixed Task", UserID: primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+	_, err = userRepo.Create(context.Background(), &domain.User{Name: "Prefixed", Email: "prefixed@example.com", Password: "password"})
+	assert.NoError(suite.T(), err)
+
+	count, err := suite.db.Collection(prefix+domain.TaskCollection).CountDocuments(context.Background(), bson.M{"title": "Prefixed Task"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	count, err = suite.db.Collection(prefix+domain.UserCollection).CountDocuments(context.Background(), bson.M{"email": "prefixed@example.com"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	count, err = suite.db.Collection(domain.TaskCollection).CountDocuments(context.Background(), bson.M{"title": "Prefixed Task"})
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_GetByIDs() {
+	first, err := suite.userRepo.Create(context.Background(), &domain.User{Name: "First", Email: "first@example.com", Password: "password"})
+	assert.NoError(suite.T(), err)
+	second, err := suite.userRepo.Create(context.Background(), &domain.User{Name: "Second", Email: "second@example.com", Password: "password"})
+	assert.NoError(suite.T(), err)
+
+	users, err := suite.userRepo.GetByIDs(context.Background(), []primitive.ObjectID{first.ID, second.ID, primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 2)
+
+	assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), second.ID))
+	users, err = suite.userRepo.GetByIDs(context.Background(), []primitive.ObjectID{first.ID, second.ID})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1, "soft-deleted users are skipped")
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByIDs() {
+	first, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "First", UserID: primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+	second, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Second", UserID: primitive.NewObjectID(), DependsOn: []primitive.ObjectID{first.ID}})
+	assert.NoError(suite.T(), err)
+
+	tasks, err := suite.taskRepo.GetByIDs(context.Background(), []primitive.ObjectID{first.ID, second.ID, primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), tasks, 2)
+	for _, task := range tasks {
+		if task.ID == second.ID {
+			assert.Equal(suite.T(), []primitive.ObjectID{first.ID}, task.DependsOn)
+		}
+	}
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_DistinctTags() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Task 1", UserID: mockUserID, Tags: []string{"work", "urgent"}},
+		{Title: "Task 2", UserID: mockUserID, Tags: []string{"home", "work"}},
+		{Title: "Task 3", UserID: mockUserID},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Tags: []string{"private"}},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tags, err := suite.taskRepo.DistinctTags(context.Background(), mockUserID)
+	assert.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []string{"home", "urgent", "work"}, tags)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetUpcoming() {
+	mockUserID := primitive.NewObjectID()
+	now := time.Now()
+	for _, task := range []*domain.Task{
+		{Title: "Later", UserID: mockUserID, Status: domain.StatusPending, DueDate: now.Add(72 * time.Hour)},
+		{Title: "Soonest", UserID: mockUserID, Status: domain.StatusInProgress, DueDate: now.Add(time.Hour)},
+		{Title: "Done", UserID: mockUserID, Status: domain.StatusCompleted, DueDate: now.Add(30 * time.Minute)},
+		{Title: "No due date", UserID: mockUserID, Status: domain.StatusPending},
+		{Title: "Soon", UserID: mockUserID, Status: domain.StatusPending, DueDate: now.Add(24 * time.Hour)},
+		{Title: "Furthest", UserID: mockUserID, Status: domain.StatusPending, DueDate: now.Add(96 * time.Hour)},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Status: domain.StatusPending, DueDate: now},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetUpcoming(context.Background(), mockUserID, 3)
+	assert.NoError(suite.T(), err)
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	assert.Equal(suite.T(), []string{"Soonest", "Soon", "Later"}, titles)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetRemindersDue() {
+	userID := primitive.NewObjectID()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) *time.Time {
+		t := now.Add(offset)
+		return &t
+	}
+	for _, task := range []*domain.Task{
+		{Title: "Later", UserID: userID, Status: domain.StatusPending, RemindAt: at(-time.Minute)},
+		{Title: "Earlier", UserID: userID, Status: domain.StatusInProgress, RemindAt: at(-time.Hour)},
+		{Title: "Not yet", UserID: userID, Status: domain.StatusPending, RemindAt: at(time.Hour)},
+		{Title: "Done", UserID: userID, Status: domain.StatusCompleted, RemindAt: at(-time.Hour)},
+		{Title: "No reminder", UserID: userID, Status: domain.StatusPending},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Status: domain.StatusPending, RemindAt: at(-time.Hour)},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetRemindersDue(context.Background(), userID, now, 0)
+	assert.NoError(suite.T(), err)
+	titles := []string{}
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	assert.Equal(suite.T(), []string{"Earlier", "Later"}, titles)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_CountByUserID() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Task 1", UserID: mockUserID},
+		{Title: "Task 2", UserID: mockUserID},
+		{Title: "Someone else's", UserID: primitive.NewObjectID()},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	count, err := suite.taskRepo.CountByUserID(context.Background(), mockUserID, domain.TaskFilter{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), count)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_LatestCreatedAt() {
+	mockUserID := primitive.NewObjectID()
+
+	latest, err := suite.taskRepo.LatestCreatedAt(context.Background(), mockUserID)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), latest.IsZero())
+
+	_, err = suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Older", UserID: mockUserID})
+	assert.NoError(suite.T(), err)
+	newest, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Newer", UserID: mockUserID})
+	assert.NoError(suite.T(), err)
+
+	latest, err = suite.taskRepo.LatestCreatedAt(context.Background(), mockUserID)
+	assert.NoError(suite.T(), err)
+	assert.WithinDuration(suite.T(), newest.CreatedAt, latest, time.Millisecond)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_TitleTaken() {
+	userID := primitive.NewObjectID()
+	open, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Write Report", UserID: userID, Status: domain.StatusPending})
+	assert.NoError(suite.T(), err)
+	_, err = suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Done (v1)", UserID: userID, Status: domain.StatusCompleted})
+	assert.NoError(suite.T(), err)
+
+	taken, err := suite.taskRepo.TitleTaken(context.Background(), userID, "write report", primitive.NilObjectID)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), taken)
+
+	taken, err = suite.taskRepo.TitleTaken(context.Background(), userID, "Write Report", open.ID)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), taken, "a task does not conflict with itself")
+
+	taken, err = suite.taskRepo.TitleTaken(context.Background(), userID, "Done (v1)", primitive.NilObjectID)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), taken, "completed tasks do not conflict")
+
+	taken, err = suite.taskRepo.TitleTaken(context.Background(), primitive.NewObjectID(), "Write Report", primitive.NilObjectID)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), taken)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetCompleted() {
+	userID := primitive.NewObjectID()
+	at := func(day int) *time.Time {
+		t := time.Date(2024, 5, day, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	for _, task := range []*domain.Task{
+		{Title: "Before", UserID: userID, Status: domain.StatusCompleted, CompletedAt: at(1)},
+		{Title: "Early", UserID: userID, Status: domain.StatusCompleted, CompletedAt: at(6)},
+		{Title: "Late", UserID: userID, Status: domain.StatusCompleted, CompletedAt: at(10)},
+		{Title: "After", UserID: userID, Status: domain.StatusCompleted, CompletedAt: at(20)},
+		{Title: "Open", UserID: userID, Status: domain.StatusPending},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Status: domain.StatusCompleted, CompletedAt: at(8)},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetCompleted(context.Background(), userID, at(5), at(12), domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	assert.Equal(suite.T(), []string{"Late", "Early"}, titles, "newest completion first")
+
+	all, err := suite.taskRepo.GetCompleted(context.Background(), userID, nil, nil, domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), all, 4)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_PreviewFilter_CountMatchesListing() {
+	userID := primitive.NewObjectID()
+	for i := 0; i < 8; i++ {
+		status := domain.StatusPending
+		if i%4 == 0 {
+			status = domain.StatusCompleted
+		}
+		_, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Task", UserID: userID, Status: status, Metadata: map[string]string{"team": "ops"}})
+		assert.NoError(suite.T(), err)
+	}
+	filter := domain.TaskFilter{Statuses: []string{domain.StatusPending}, Metadata: map[string]string{"team": "ops"}}
+
+	count, err := suite.taskRepo.CountByUserID(context.Background(), userID, filter)
+	assert.NoError(suite.T(), err)
+	all, err := suite.taskRepo.GetByUserID(context.Background(), userID, filter)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(6), count)
+	assert.Len(suite.T(), all, int(count))
+
+	filter.Pagination = domain.Pagination{Page: 1, PageSize: 5}
+	sample, err := suite.taskRepo.GetByUserID(context.Background(), userID, filter)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), sample, 5)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetSharedWith() {
+	userID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Shared", UserID: otherID, SharedWith: []primitive.ObjectID{userID}},
+		{Title: "Shared with others", UserID: otherID, SharedWith: []primitive.ObjectID{primitive.NewObjectID()}},
+		{Title: "Private", UserID: otherID},
+		{Title: "Owned", UserID: userID},
+		{Title: "Owned and listed", UserID: userID, SharedWith: []primitive.ObjectID{userID}},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetSharedWith(context.Background(), userID, domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), tasks, 1) {
+		assert.Equal(suite.T(), "Shared", tasks[0].Title)
+	}
+
+	assert.NoError(suite.T(), suite.taskRepo.SetOwnerDeleted(context.Background(), otherID, true))
+	tasks, err = suite.taskRepo.GetSharedWith(context.Background(), userID, domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), tasks, "a deleted owner's tasks are no longer shared")
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_CountByUserID_Filtered() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Pending", UserID: mockUserID, Status: domain.StatusPending},
+		{Title: "Also pending", UserID: mockUserID, Status: domain.StatusPending},
+		{Title: "Done", UserID: mockUserID, Status: domain.StatusCompleted},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	count, err := suite.taskRepo.CountByUserID(context.Background(), mockUserID, domain.TaskFilter{Statuses: []string{domain.StatusPending}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), count)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_MultipleStatuses() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Pending", UserID: mockUserID, Status: domain.StatusPending},
+		{Title: "Started", UserID: mockUserID, Status: domain.StatusInProgress},
+		{Title: "Done", UserID: mockUserID, Status: domain.StatusCompleted},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{Statuses: []string{domain.StatusPending, domain.StatusInProgress}})
+	assert.NoError(suite.T(), err)
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	assert.ElementsMatch(suite.T(), []string{"Pending", "Started"}, titles)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_Metadata() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Ops billing", UserID: mockUserID, Metadata: map[string]string{"team": "ops", "cost_center": "42"}},
+		{Title: "Ops other", UserID: mockUserID, Metadata: map[string]string{"team": "ops", "cost_center": "7"}},
+		{Title: "Dev", UserID: mockUserID, Metadata: map[string]string{"team": "dev"}},
+		{Title: "Plain", UserID: mockUserID},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	titles := func(filter domain.TaskFilter) []string {
+		tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, filter)
+		assert.NoError(suite.T(), err)
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.Title)
+		}
+		return result
+	}
+
+	assert.ElementsMatch(suite.T(), []string{"Ops billing", "Ops other"}, titles(domain.TaskFilter{Metadata: map[string]string{"team": "ops"}}))
+	assert.Equal(suite.T(), []string{"Ops billing"}, titles(domain.TaskFilter{Metadata: map[string]string{"team": "ops", "cost_center": "42"}}))
+	assert.Empty(suite.T(), titles(domain.TaskFilter{Metadata: map[string]string{"team": "sales"}}))
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_Search_RanksByRelevance() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Groceries", Description: "Buy milk before the report meeting", UserID: mockUserID},
+		{Title: "Quarterly report", Description: "Finish the report draft", UserID: mockUserID},
+		{Title: "Gym", Description: "Leg day", UserID: mockUserID},
+		{Title: "Report", Description: "Someone else's", UserID: primitive.NewObjectID()},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.Search(context.Background(), mockUserID, "report", domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), tasks, 2) {
+		assert.Equal(suite.T(), "Quarterly report", tasks[0].Title)
+		assert.Equal(suite.T(), "Groceries", tasks[1].Title)
+		assert.Greater(suite.T(), tasks[0].Score, tasks[1].Score)
+	}
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_StatsByUserID() {
+	mockUserID := primitive.NewObjectID()
+	now := time.Now()
+	for _, task := range []*domain.Task{
+		{Title: "Overdue", UserID: mockUserID, Status: domain.StatusPending, DueDate: now.Add(-time.Hour)},
+		{Title: "Upcoming", UserID: mockUserID, Status: domain.StatusPending, DueDate: now.Add(time.Hour)},
+		{Title: "Late", UserID: mockUserID, Status: domain.StatusInProgress, DueDate: now.Add(-time.Hour)},
+		{Title: "Done late", UserID: mockUserID, Status: domain.StatusCompleted, DueDate: now.Add(-time.Hour)},
+		{Title: "No due date", UserID: mockUserID, Status: domain.StatusPending},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Status: domain.StatusPending, DueDate: now.Add(-time.Hour)},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	stats, err := suite.taskRepo.StatsByUserID(context.Background(), mockUserID, now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &domain.TaskStats{Total: 5, Pending: 3, InProgress: 1, Completed: 1, Overdue: 2}, stats)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetUnassigned() {
+	assigneeID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Open", UserID: primitive.NewObjectID()},
+		{Title: "Taken", UserID: primitive.NewObjectID(), AssigneeID: &assigneeID},
+		{Title: "Also open", UserID: primitive.NewObjectID()},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetUnassigned(context.Background(), domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	titles := []string{}
+	for _, task := range tasks {
+		assert.Nil(suite.T(), task.AssigneeID)
+		titles = append(titles, task.Title)
+	}
+	assert.ElementsMatch(suite.T(), []string{"Open", "Also open"}, titles)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_CountOverdueByUser() {
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	late := primitive.NewObjectID()
+	slightlyLate := primitive.NewObjectID()
+	onTime := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Late 1", UserID: late, Status: domain.StatusPending, DueDate: now.Add(-48 * time.Hour)},
+		{Title: "Late 2", UserID: late, Status: domain.StatusInProgress, DueDate: now.Add(-time.Hour)},
+		{Title: "Late but done", UserID: late, Status: domain.StatusCompleted, DueDate: now.Add(-time.Hour)},
+		{Title: "Late 3", UserID: slightlyLate, Status: domain.StatusPending, DueDate: now.Add(-time.Minute)},
+		{Title: "Upcoming", UserID: slightlyLate, Status: domain.StatusPending, DueDate: now.Add(time.Hour)},
+		{Title: "Done early", UserID: onTime, Status: domain.StatusCompleted, DueDate: now.Add(-time.Hour)},
+		{Title: "Next week", UserID: onTime, Status: domain.StatusPending, DueDate: now.Add(7 * 24 * time.Hour)},
+		{Title: "Someday", UserID: onTime, Status: domain.StatusPending},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	counts, err := suite.taskRepo.CountOverdueByUser(context.Background(), now)
+	assert.NoError(suite.T(), err)
+	// Tasks from other tests share the collection, so keep only these users
+	seeded := []domain.OverdueCount{}
+	for _, count := range counts {
+		if count.UserID == late || count.UserID == slightlyLate || count.UserID == onTime {
+			seeded = append(seeded, count)
+		}
+	}
+	assert.Equal(suite.T(), []domain.OverdueCount{
+		{UserID: late, Count: 2},
+		{UserID: slightlyLate, Count: 1},
+	}, seeded)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_TimeReportByUserID() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Planned", UserID: mockUserID, Status: domain.StatusPending, EstimatedMinutes: 30},
+		{Title: "Underway", UserID: mockUserID, Status: domain.StatusInProgress, EstimatedMinutes: 60, ActualMinutes: 20},
+		{Title: "Overran", UserID: mockUserID, Status: domain.StatusCompleted, EstimatedMinutes: 45, ActualMinutes: 90},
+		{Title: "Quick", UserID: mockUserID, Status: domain.StatusCompleted, EstimatedMinutes: 15, ActualMinutes: 10},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Status: domain.StatusCompleted, EstimatedMinutes: 500, ActualMinutes: 500},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	report, err := suite.taskRepo.TimeReportByUserID(context.Background(), mockUserID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &domain.TimeReport{
+		EstimatedMinutes: 150,
+		ActualMinutes:    120,
+		ByStatus: []domain.StatusTime{
+			{Status: domain.StatusPending, Tasks: 1, EstimatedMinutes: 30},
+			{Status: domain.StatusInProgress, Tasks: 1, EstimatedMinutes: 60, ActualMinutes: 20},
+			{Status: domain.StatusCompleted, Tasks: 2, EstimatedMinutes: 60, ActualMinutes: 100},
+		},
+	}, report)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_DeleteCompletedByUserID() {
+	mockUserID := primitive.NewObjectID()
+	var kept []primitive.ObjectID
+	for _, task := range []*domain.Task{
+		{Title: "Done 1", UserID: mockUserID, Status: domain.StatusCompleted},
+		{Title: "Done 2", UserID: mockUserID, Status: domain.StatusCompleted},
+		{Title: "Pending", UserID: mockUserID, Status: domain.StatusPending},
+		{Title: "Working", UserID: mockUserID, Status: domain.StatusInProgress},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Status: domain.StatusCompleted},
+	} {
+		created, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+		if created.Status != domain.StatusCompleted || created.UserID != mockUserID {
+			kept = append(kept, created.ID)
+		}
+	}
+
+	deleted, err := suite.taskRepo.DeleteCompletedByUserID(context.Background(), mockUserID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), deleted)
+
+	remaining, err := suite.taskRepo.GetByIDs(context.Background(), kept)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), remaining, 3)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_Delete_Twice() {
+	createdTask, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Test Task", UserID: primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+
+	err = suite.taskRepo.Delete(context.Background(), createdTask.ID)
+	assert.NoError(suite.T(), err)
+
+	err = suite.taskRepo.Delete(context.Background(), createdTask.ID)
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)
+}
+
 func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID() {
 	mockUserID := primitive.NewObjectID()
 	mockTask1 := &domain.Task{Title: "Task 1", UserID: mockUserID}
@@ -163,11 +705,117 @@ func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID() {
 	_, err = suite.taskRepo.Create(context.Background(), mockTask2)
 	assert.NoError(suite.T(), err)
 
-	tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID)
+	tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{})
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), tasks, 2)
 }
 
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_Empty() {
+	tasks, err := suite.taskRepo.GetByUserID(context.Background(), primitive.NewObjectID(), domain.TaskFilter{})
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), tasks)
+
+	body, err := json.Marshal(tasks)
+	assert.NoError(suite.T(), err)
+	assert.JSONEq(suite.T(), `[]`, string(body))
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_DueDayRange() {
+	mockUserID := primitive.NewObjectID()
+	dayStart := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	dayEnd := dayStart.Add(24*time.Hour - time.Millisecond)
+
+	for _, task := range []*domain.Task{
+		{Title: "Start of day", UserID: mockUserID, DueDate: dayStart},
+		{Title: "End of day", UserID: mockUserID, DueDate: dayEnd},
+		{Title: "Previous day", UserID: mockUserID, DueDate: dayStart.Add(-time.Millisecond)},
+		{Title: "Next day", UserID: mockUserID, DueDate: dayEnd.Add(time.Millisecond)},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	to := dayStart.Add(24*time.Hour - time.Nanosecond)
+	tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{DueFrom: &dayStart, DueTo: &to})
+	assert.NoError(suite.T(), err)
+
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	assert.ElementsMatch(suite.T(), []string{"Start of day", "End of day"}, titles)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_HasDueDate() {
+	mockUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Dated", UserID: mockUserID, DueDate: time.Now().Add(time.Hour)},
+		{Title: "Also dated", UserID: mockUserID, DueDate: time.Now().Add(-time.Hour)},
+		{Title: "Undated", UserID: mockUserID},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	titles := func(hasDueDate bool) []string {
+		tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{HasDueDate: &hasDueDate})
+		assert.NoError(suite.T(), err)
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.Title)
+		}
+		return result
+	}
+	assert.ElementsMatch(suite.T(), []string{"Dated", "Also dated"}, titles(true))
+	assert.ElementsMatch(suite.T(), []string{"Undated"}, titles(false))
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_Relation() {
+	mockUserID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+	for _, task := range []*domain.Task{
+		{Title: "Own", UserID: mockUserID},
+		{Title: "Own and assigned", UserID: mockUserID, AssigneeID: &mockUserID},
+		{Title: "Delegated", UserID: mockUserID, AssigneeID: &otherUserID},
+		{Title: "Assigned", UserID: otherUserID, AssigneeID: &mockUserID},
+		{Title: "Unrelated", UserID: otherUserID},
+	} {
+		_, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+	}
+
+	titles := func(relation string) []string {
+		tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{Relation: relation})
+		assert.NoError(suite.T(), err)
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.Title)
+		}
+		return result
+	}
+	assert.ElementsMatch(suite.T(), []string{"Own", "Own and assigned", "Delegated"}, titles(""))
+	assert.ElementsMatch(suite.T(), []string{"Own", "Own and assigned", "Delegated"}, titles(domain.RelationOwned))
+	assert.ElementsMatch(suite.T(), []string{"Own and assigned", "Assigned"}, titles(domain.RelationAssigned))
+	assert.ElementsMatch(suite.T(), []string{"Own", "Own and assigned", "Delegated", "Assigned"}, titles(domain.RelationAll))
+
+	count, err := suite.taskRepo.CountByUserID(context.Background(), mockUserID, domain.TaskFilter{Relation: domain.RelationAll})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(4), count)
+
+	// Tasks of a deleted account are no longer listed as assigned
+	assert.NoError(suite.T(), suite.taskRepo.SetOwnerDeleted(context.Background(), otherUserID, true))
+	assert.ElementsMatch(suite.T(), []string{"Own and assigned"}, titles(domain.RelationAssigned))
+
+	visible, err := suite.taskRepo.GetAllVisible(context.Background(), domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	for _, task := range visible {
+		assert.NotEqual(suite.T(), otherUserID, task.UserID)
+	}
+
+	assert.NoError(suite.T(), suite.taskRepo.SetOwnerDeleted(context.Background(), otherUserID, false))
+	assert.ElementsMatch(suite.T(), []string{"Own and assigned", "Assigned"}, titles(domain.RelationAssigned))
+}
+
 func (suite *RepositoryTestSuite) TestTaskRepository_GetAll() {
 	mockTask1 := &domain.Task{Title: "Task 1", UserID: primitive.NewObjectID()}
 	mockTask2 := &domain.Task{Title: "Task 2", UserID: primitive.NewObjectID()}
@@ -177,11 +825,153 @@ func (suite *RepositoryTestSuite) TestTaskRepository_GetAll() {
 	_, err = suite.taskRepo.Create(context.Background(), mockTask2)
 	assert.NoError(suite.T(), err)
 
-	tasks, err := suite.taskRepo.GetAll(context.Background())
+	tasks, err := suite.taskRepo.GetAll(context.Background(), domain.Pagination{})
 	assert.NoError(suite.T(), err)
 	assert.GreaterOrEqual(suite.T(), len(tasks), 2)
 }
 
+func (suite *RepositoryTestSuite) TestTaskRepository_UpdateFields() {
+	task, err := suite.taskRepo.Create(context.Background(), &domain.Task{
+		Title:     "Targeted",
+		Status:    domain.StatusPending,
+		UserID:    primitive.NewObjectID(),
+		Checklist: []domain.ChecklistItem{{Text: "Draft"}},
+	})
+	assert.NoError(suite.T(), err)
+	created, err := suite.taskRepo.GetByID(context.Background(), task.ID)
+	assert.NoError(suite.T(), err)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(suite.T(), suite.taskRepo.UpdateFields(context.Background(), task.ID, map[string]interface{}{
+		"status":           domain.StatusInProgress,
+		"checklist.0.done": true,
+	}))
+
+	updated, err := suite.taskRepo.GetByID(context.Background(), task.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.StatusInProgress, updated.Status)
+	assert.True(suite.T(), updated.Checklist[0].Done)
+	assert.Equal(suite.T(), created.Title, updated.Title)
+	assert.Equal(suite.T(), created.UserID, updated.UserID)
+	assert.Equal(suite.T(), created.CreatedAt, updated.CreatedAt)
+	assert.True(suite.T(), updated.UpdatedAt.After(created.UpdatedAt))
+
+	err = suite.taskRepo.UpdateFields(context.Background(), primitive.NewObjectID(), map[string]interface{}{"status": domain.StatusCompleted})
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)
+	err = suite.taskRepo.UpdateFields(context.Background(), task.ID, map[string]interface{}{"updated_at": time.Now()})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_UpdateTags() {
+	mockUserID := primitive.NewObjectID()
+	var ids []primitive.ObjectID
+	for _, task := range []*domain.Task{
+		{Title: "Tagged", UserID: mockUserID, Tags: []string{"draft", "keep"}},
+		{Title: "Untagged", UserID: mockUserID},
+		{Title: "Someone else's", UserID: primitive.NewObjectID(), Tags: []string{"draft"}},
+	} {
+		created, err := suite.taskRepo.Create(context.Background(), task)
+		assert.NoError(suite.T(), err)
+		ids = append(ids, created.ID)
+	}
+
+	matched, err := suite.taskRepo.UpdateTags(context.Background(), mockUserID, ids, []string{"final", "keep", "final"}, []string{"draft"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), matched)
+
+	// A tag that looks like a field path is stored as written
+	_, err = suite.taskRepo.UpdateTags(context.Background(), mockUserID, ids[1:2], []string{"$title"}, nil)
+	assert.NoError(suite.T(), err)
+	_, err = suite.taskRepo.UpdateTags(context.Background(), mockUserID, ids[1:2], nil, []string{"$title"})
+	assert.NoError(suite.T(), err)
+
+	tasks, err := suite.taskRepo.GetByIDs(context.Background(), ids)
+	assert.NoError(suite.T(), err)
+	for _, task := range tasks {
+		switch task.ID {
+		case ids[0]:
+			assert.ElementsMatch(suite.T(), []string{"keep", "final"}, task.Tags)
+		case ids[1]:
+			assert.ElementsMatch(suite.T(), []string{"final", "keep"}, task.Tags)
+		case ids[2]:
+			assert.Equal(suite.T(), []string{"draft"}, task.Tags)
+		}
+	}
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_Reorder() {
+	mockUserID := primitive.NewObjectID()
+	var ids []primitive.ObjectID
+	for _, title := range []string{"A", "B", "C"} {
+		created, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: title, UserID: mockUserID})
+		assert.NoError(suite.T(), err)
+		ids = append(ids, created.ID)
+	}
+	other, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Someone else's", UserID: primitive.NewObjectID(), Order: 7})
+	assert.NoError(suite.T(), err)
+
+	matched, err := suite.taskRepo.Reorder(context.Background(), mockUserID, []primitive.ObjectID{ids[2], ids[0], ids[1], other.ID})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), matched)
+
+	titles := func(tasks []*domain.Task) []string {
+		result := []string{}
+		for _, task := range tasks {
+			result = append(result, task.Title)
+		}
+		return result
+	}
+	tasks, err := suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{Sort: domain.TaskSortOrder})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"C", "A", "B"}, titles(tasks))
+	assert.Equal(suite.T(), []int{0, 1, 2}, []int{tasks[0].Order, tasks[1].Order, tasks[2].Order})
+
+	// Paging keeps the manual order
+	tasks, err = suite.taskRepo.GetByUserID(context.Background(), mockUserID, domain.TaskFilter{Sort: domain.TaskSortOrder, Pagination: domain.Pagination{Page: 2, PageSize: 2}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"B"}, titles(tasks))
+
+	unchanged, err := suite.taskRepo.GetByID(context.Background(), other.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 7, unchanged.Order)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetByUserID_Newest() {
+	userID := primitive.NewObjectID()
+	for _, title := range []string{"First", "Second", "Third"} {
+		_, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: title, UserID: userID})
+		assert.NoError(suite.T(), err)
+	}
+
+	tasks, err := suite.taskRepo.GetByUserID(context.Background(), userID, domain.TaskFilter{Sort: domain.TaskSortNewest, Pagination: domain.Pagination{Page: 1, PageSize: 2}})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), tasks, 2) {
+		assert.Equal(suite.T(), "Third", tasks[0].Title)
+		assert.Equal(suite.T(), "Second", tasks[1].Title)
+	}
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_GetAll_Paginated() {
+	all, err := suite.taskRepo.GetAll(context.Background(), domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	for i := len(all); i < 3; i++ {
+		_, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Paged", UserID: primitive.NewObjectID()})
+		assert.NoError(suite.T(), err)
+	}
+
+	first, err := suite.taskRepo.GetAll(context.Background(), domain.Pagination{Page: 1, PageSize: 2})
+	assert.NoError(suite.T(), err)
+	second, err := suite.taskRepo.GetAll(context.Background(), domain.Pagination{Page: 2, PageSize: 2})
+	assert.NoError(suite.T(), err)
+
+	assert.Len(suite.T(), first, 2)
+	assert.NotEmpty(suite.T(), second)
+	for _, task := range second {
+		assert.NotEqual(suite.T(), first[0].ID, task.ID)
+		assert.NotEqual(suite.T(), first[1].ID, task.ID)
+	}
+}
+
 func (suite *RepositoryTestSuite) TestTaskRepository_Update() {
 	mockTask := &domain.Task{Title: "Original Title", UserID: primitive.NewObjectID()}
 	createdTask, err := suite.taskRepo.Create(context.Background(), mockTask)
@@ -250,6 +1040,148 @@ func (suite *RepositoryTestSuite) TestUserRepository_Delete() {
 	assert.Nil(suite.T(), result)
 }
 
+func (suite *RepositoryTestSuite) TestUserRepository_Delete_IsSoft() {
+	createdUser, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "soft-delete@example.com"})
+	assert.NoError(suite.T(), err)
+
+	err = suite.userRepo.Delete(context.Background(), createdUser.ID)
+	assert.NoError(suite.T(), err)
+
+	count, err := suite.db.Collection(domain.UserCollection).CountDocuments(context.Background(), bson.M{"_id": createdUser.ID})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count, "the document is kept")
+
+	byEmail, err := suite.userRepo.GetByEmail(context.Background(), "soft-delete@example.com")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), byEmail)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_Restore() {
+	createdUser, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "restore@example.com"})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), createdUser.ID))
+
+	err = suite.userRepo.Restore(context.Background(), createdUser.ID, time.Now().Add(-time.Hour))
+	assert.NoError(suite.T(), err)
+
+	byEmail, err := suite.userRepo.GetByEmail(context.Background(), "restore@example.com")
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), byEmail, "a restored user can log in again")
+	assert.Nil(suite.T(), byEmail.DeletedAt)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_Restore_EmailTaken() {
+	deleted, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "taken@example.com"})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), deleted.ID))
+	_, err = suite.userRepo.Create(context.Background(), &domain.User{Email: "taken@example.com"})
+	assert.NoError(suite.T(), err)
+
+	err = suite.userRepo.Restore(context.Background(), deleted.ID, time.Now().Add(-time.Hour))
+	assert.ErrorIs(suite.T(), err, domain.ErrUserAlreadyExists)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_Restore_GracePeriodOver() {
+	createdUser, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "expired@example.com"})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), createdUser.ID))
+
+	err = suite.userRepo.Restore(context.Background(), createdUser.ID, time.Now().Add(time.Hour))
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)
+
+	err = suite.userRepo.Restore(context.Background(), primitive.NewObjectID(), time.Time{})
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_Purge() {
+	kept, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "kept@example.com"})
+	assert.NoError(suite.T(), err)
+	deleted, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "purged@example.com"})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), deleted.ID))
+	_, err = suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Orphan", UserID: deleted.ID})
+	assert.NoError(suite.T(), err)
+	_, err = suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Kept", UserID: kept.ID})
+	assert.NoError(suite.T(), err)
+
+	none, err := suite.userRepo.ListDeletedBefore(context.Background(), time.Now().Add(-time.Hour))
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), none)
+
+	ids, err := suite.userRepo.ListDeletedBefore(context.Background(), time.Now().Add(time.Second))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []primitive.ObjectID{deleted.ID}, ids)
+
+	removedTasks, err := suite.taskRepo.DeleteByUserIDs(context.Background(), ids)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), removedTasks)
+
+	purged, err := suite.userRepo.Purge(context.Background(), []primitive.ObjectID{deleted.ID, kept.ID})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), purged, "live accounts are never purged")
+
+	count, err := suite.db.Collection(domain.UserCollection).CountDocuments(context.Background(), bson.M{"_id": deleted.ID})
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), count)
+
+	keptTasks, err := suite.taskRepo.GetByUserID(context.Background(), kept.ID, domain.TaskFilter{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keptTasks, 1)
+}
+
+func (suite *RepositoryTestSuite) TestTaskRepository_ForEachByUserID() {
+	userID := primitive.NewObjectID()
+	for _, title := range []string{"First", "Second", "Third"} {
+		_, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: title, UserID: userID})
+		assert.NoError(suite.T(), err)
+	}
+	_, err := suite.taskRepo.Create(context.Background(), &domain.Task{Title: "Other", UserID: primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+
+	var titles []string
+	err = suite.taskRepo.ForEachByUserID(context.Background(), userID, func(task *domain.Task) error {
+		titles = append(titles, task.Title)
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"First", "Second", "Third"}, titles)
+
+	stop := errors.New("stop")
+	visited := 0
+	err = suite.taskRepo.ForEachByUserID(context.Background(), userID, func(task *domain.Task) error {
+		visited++
+		return stop
+	})
+	assert.ErrorIs(suite.T(), err, stop)
+	assert.Equal(suite.T(), 1, visited)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_GetAll_ExcludesDeleted() {
+	kept, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "kept@example.com"})
+	assert.NoError(suite.T(), err)
+	deleted, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "deleted@example.com"})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), deleted.ID))
+
+	ids := func(users []*domain.User) []primitive.ObjectID {
+		var result []primitive.ObjectID
+		for _, user := range users {
+			result = append(result, user.ID)
+		}
+		return result
+	}
+
+	users, err := suite.userRepo.GetAll(context.Background(), domain.UserFilter{})
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), ids(users), kept.ID)
+	assert.NotContains(suite.T(), ids(users), deleted.ID)
+
+	users, err = suite.userRepo.GetAll(context.Background(), domain.UserFilter{IncludeDeleted: true})
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), ids(users), kept.ID)
+	assert.Contains(suite.T(), ids(users), deleted.ID)
+}
+
 func (suite *RepositoryTestSuite) TestUserRepository_GetByID_NotFound() {
 	nonExistentID := primitive.NewObjectID()
 
@@ -276,6 +1208,80 @@ func (suite *RepositoryTestSuite) TestUserRepository_Update_Error() {
 	assert.Error(suite.T(), err)
 }
 
+func (suite *RepositoryTestSuite) TestUserRepository_Update_KeepsPasswordWhenEmpty() {
+	createdUser, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "avatar@example.com", Password: "storedHash"})
+	assert.NoError(suite.T(), err)
+
+	err = suite.userRepo.Update(context.Background(), &domain.User{ID: createdUser.ID, Email: "avatar@example.com", AvatarURL: "https://cdn.example.com/a.png"})
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.userRepo.GetByID(context.Background(), createdUser.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "storedHash", result.Password)
+	assert.Equal(suite.T(), "https://cdn.example.com/a.png", result.AvatarURL)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_IncrementTokenVersion() {
+	createdUser, err := suite.userRepo.Create(context.Background(), &domain.User{Email: "revoke@example.com"})
+	assert.NoError(suite.T(), err)
+	stale, err := suite.userRepo.GetByID(context.Background(), createdUser.ID)
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.userRepo.IncrementTokenVersion(context.Background(), createdUser.ID))
+
+	// Saving a copy loaded before the revocation must not restore the old version
+	stale.Name = "Renamed"
+	assert.NoError(suite.T(), suite.userRepo.Update(context.Background(), stale))
+
+	result, err := suite.userRepo.GetByID(context.Background(), createdUser.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, result.TokenVersion)
+	assert.Equal(suite.T(), "Renamed", result.Name)
+
+	err = suite.userRepo.IncrementTokenVersion(context.Background(), primitive.NewObjectID())
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_Search() {
+	for _, user := range []*domain.User{
+		{Name: "Johnathan Smith", Email: "jsmith@example.com"},
+		{Name: "Mary Major", Email: "mary.johnson@corp.example"},
+		{Name: "Alex Doe", Email: "alex@example.com"},
+		{Name: "John Deleted", Email: "gone@example.com"},
+	} {
+		created, err := suite.userRepo.Create(context.Background(), user)
+		assert.NoError(suite.T(), err)
+		if user.Name == "John Deleted" {
+			assert.NoError(suite.T(), suite.userRepo.Delete(context.Background(), created.ID))
+		}
+	}
+	names := func(users []*domain.User) []string {
+		result := []string{}
+		for _, user := range users {
+			result = append(result, user.Name)
+		}
+		return result
+	}
+
+	// Partial name, in either case, and partial email both match
+	users, err := suite.userRepo.Search(context.Background(), "JOHN", domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Johnathan Smith", "Mary Major"}, names(users))
+
+	users, err = suite.userRepo.Search(context.Background(), "corp.ex", domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Mary Major"}, names(users))
+
+	// Regex metacharacters are matched literally
+	users, err = suite.userRepo.Search(context.Background(), "a.*e", domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), names(users))
+
+	users, err = suite.userRepo.Search(context.Background(), "john", domain.Pagination{Page: 2, PageSize: 1})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Mary Major"}, names(users))
+}
+
 func (suite *RepositoryTestSuite) TestUserRepository_GetAll() {
 	mockUser1 := &domain.User{Email: "user1@example.com"}
 	mockUser2 := &domain.User{Email: "user2@example.com"}
@@ -285,11 +1291,322 @@ func (suite *RepositoryTestSuite) TestUserRepository_GetAll() {
 	_, err = suite.userRepo.Create(context.Background(), mockUser2)
 	assert.NoError(suite.T(), err)
 
-	users, err := suite.userRepo.GetAll(context.Background())
+	users, err := suite.userRepo.GetAll(context.Background(), domain.UserFilter{})
 	assert.NoError(suite.T(), err)
 	assert.GreaterOrEqual(suite.T(), len(users), 2)
 }
 
+func (suite *RepositoryTestSuite) TestUserRepository_CreateMany() {
+	users := []*domain.User{{Email: "bulk1@example.com"}, {Email: "bulk2@example.com"}}
+
+	created, err := suite.userRepo.CreateMany(context.Background(), users)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), created, 2)
+
+	for _, user := range created {
+		assert.False(suite.T(), user.ID.IsZero())
+		result, err := suite.userRepo.GetByID(context.Background(), user.ID)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), user.Email, result.Email)
+	}
+}
+
+func (suite *RepositoryTestSuite) TestUserRepository_GetAll_CreatedAtRange() {
+	collection := suite.db.Collection(domain.UserCollection)
+	for _, seed := range []struct {
+		email     string
+		createdAt time.Time
+	}{
+		{"before@example.com", time.Date(2023, 12, 31, 23, 59, 0, 0, time.UTC)},
+		{"start@example.com", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"inside@example.com", time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"after@example.com", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		_, err := collection.InsertOne(context.Background(), &domain.User{Email: seed.email, Role: domain.RoleUser, CreatedAt: seed.createdAt})
+		assert.NoError(suite.T(), err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+	users, err := suite.userRepo.GetAll(context.Background(), domain.UserFilter{Role: domain.RoleUser, CreatedFrom: &from, CreatedTo: &to})
+	assert.NoError(suite.T(), err)
+
+	var emails []string
+	for _, user := range users {
+		emails = append(emails, user.Email)
+	}
+	assert.ElementsMatch(suite.T(), []string{"start@example.com", "inside@example.com"}, emails)
+}
+
+// APIKeyRepository Tests
+func (suite *RepositoryTestSuite) TestAPIKeyRepository_CreateAndGetByHash() {
+	created, err := suite.apiKeyRepo.Create(context.Background(), &domain.APIKey{Name: "billing", KeyHash: "hash-create"})
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.apiKeyRepo.GetByHash(context.Background(), "hash-create")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), created.ID, result.ID)
+	assert.False(suite.T(), result.Revoked)
+}
+
+func (suite *RepositoryTestSuite) TestAPIKeyRepository_Revoke() {
+	created, err := suite.apiKeyRepo.Create(context.Background(), &domain.APIKey{Name: "billing", KeyHash: "hash-revoke"})
+	assert.NoError(suite.T(), err)
+
+	err = suite.apiKeyRepo.Revoke(context.Background(), created.ID)
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.apiKeyRepo.GetByHash(context.Background(), "hash-revoke")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Revoked)
+	assert.NotNil(suite.T(), result.RevokedAt)
+}
+
+func (suite *RepositoryTestSuite) TestAPIKeyRepository_Revoke_NotFound() {
+	err := suite.apiKeyRepo.Revoke(context.Background(), primitive.NewObjectID())
+
+	assert.ErrorIs(suite.T(), err, domain.ErrAPIKeyNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestEnsureIndexes_CollectionPrefix() {
+	err := EnsureIndexes(context.Background(), suite.db, WithCollectionPrefix("tenant1_"))
+	assert.NoError(suite.T(), err)
+
+	specs, err := suite.db.Collection("tenant1_" + domain.TaskCollection).Indexes().ListSpecifications(context.Background())
+	assert.NoError(suite.T(), err)
+	var names []string
+	for _, spec := range specs {
+		names = append(names, spec.Name)
+	}
+	assert.Contains(suite.T(), names, "user_id_1")
+}
+
+// InviteRepository Tests
+func (suite *RepositoryTestSuite) TestInviteRepository_Redeem() {
+	_, err := suite.inviteRepo.Create(context.Background(), &domain.Invite{Code: "code-redeem", CreatedBy: primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.inviteRepo.Redeem(context.Background(), "code-redeem", "first@example.com"))
+	assert.ErrorIs(suite.T(), suite.inviteRepo.Redeem(context.Background(), "code-redeem", "second@example.com"), domain.ErrInvalidInvite)
+	assert.ErrorIs(suite.T(), suite.inviteRepo.Redeem(context.Background(), "code-unknown", "third@example.com"), domain.ErrInvalidInvite)
+}
+
+func (suite *RepositoryTestSuite) TestInviteRepository_Release() {
+	_, err := suite.inviteRepo.Create(context.Background(), &domain.Invite{Code: "code-release", CreatedBy: primitive.NewObjectID()})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.inviteRepo.Redeem(context.Background(), "code-release", "first@example.com"))
+
+	// Only the email that redeemed the invite can give it back
+	assert.NoError(suite.T(), suite.inviteRepo.Release(context.Background(), "code-release", "other@example.com"))
+	assert.ErrorIs(suite.T(), suite.inviteRepo.Redeem(context.Background(), "code-release", "second@example.com"), domain.ErrInvalidInvite)
+
+	assert.NoError(suite.T(), suite.inviteRepo.Release(context.Background(), "code-release", "first@example.com"))
+	assert.NoError(suite.T(), suite.inviteRepo.Redeem(context.Background(), "code-release", "second@example.com"))
+}
+
+// AuditRepository Tests
+func (suite *RepositoryTestSuite) TestAuditRepository_Create() {
+	entry := &domain.AuditEntry{Action: domain.AuditUserImpersonated, ActorID: primitive.NewObjectID(), TargetID: primitive.NewObjectID()}
+
+	assert.NoError(suite.T(), suite.auditRepo.Create(context.Background(), entry))
+	assert.False(suite.T(), entry.ID.IsZero())
+
+	var stored domain.AuditEntry
+	err := suite.db.Collection(domain.AuditCollection).FindOne(context.Background(), bson.M{"_id": entry.ID}).Decode(&stored)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entry.ActorID, stored.ActorID)
+	assert.Equal(suite.T(), entry.TargetID, stored.TargetID)
+}
+
+// TaskHistoryRepository Tests
+func (suite *RepositoryTestSuite) TestTaskHistoryRepository_GetByTaskID() {
+	taskID, actorID := primitive.NewObjectID(), primitive.NewObjectID()
+	first := &domain.TaskRevision{
+		TaskID:  taskID,
+		ActorID: actorID,
+		Changes: []string{"title"},
+		Before:  &domain.Task{ID: taskID, Title: "Draft"},
+		After:   &domain.Task{ID: taskID, Title: "Final"},
+	}
+	second := &domain.TaskRevision{TaskID: taskID, ActorID: actorID, Changes: []string{"status"}}
+	other := &domain.TaskRevision{TaskID: primitive.NewObjectID(), ActorID: actorID, Changes: []string{"title"}}
+	for _, revision := range []*domain.TaskRevision{first, second, other} {
+		assert.NoError(suite.T(), suite.historyRepo.Create(context.Background(), revision))
+		assert.False(suite.T(), revision.ID.IsZero())
+	}
+
+	revisions, err := suite.historyRepo.GetByTaskID(context.Background(), taskID, domain.Pagination{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), revisions, 2)
+	assert.Equal(suite.T(), first.ID, revisions[0].ID)
+	assert.Equal(suite.T(), "Draft", revisions[0].Before.Title)
+	assert.Equal(suite.T(), "Final", revisions[0].After.Title)
+	assert.Equal(suite.T(), second.ID, revisions[1].ID)
+
+	page, err := suite.historyRepo.GetByTaskID(context.Background(), taskID, domain.Pagination{Page: 2, PageSize: 1})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page, 1)
+	assert.Equal(suite.T(), second.ID, page[0].ID)
+}
+
+// fakeCursor is a cursorCloser whose Close fails with err
+type fakeCursor struct {
+	err error
+}
+
+func (c fakeCursor) Close(ctx context.Context) error {
+	return c.err
+}
+
+// Test closeCursor: a close failure is reported only when nothing else failed
+func TestCloseCursor(t *testing.T) {
+	closeFailed := errors.New("close failed")
+	readFailed := errors.New("read failed")
+
+	var err error
+	closeCursor(context.Background(), fakeCursor{}, &err)
+	assert.NoError(t, err)
+
+	err = nil
+	closeCursor(context.Background(), fakeCursor{err: closeFailed}, &err)
+	assert.ErrorIs(t, err, closeFailed)
+
+	err = readFailed
+	closeCursor(context.Background(), fakeCursor{err: closeFailed}, &err)
+	assert.ErrorIs(t, err, readFailed)
+}
+
+// Test withRetry: a read that hits a network error once succeeds on the retry
+func TestWithRetry_TransientError(t *testing.T) {
+	collection := new(MockCountCollection)
+	networkErr := mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}}
+	collection.On("CountDocuments", mock.Anything, mock.Anything).Return(int64(0), networkErr).Once()
+	collection.On("CountDocuments", mock.Anything, mock.Anything).Return(int64(3), nil).Once()
+	repo := &taskRepository{collection: withRetry(collection, 2, time.Millisecond)}
+
+	count, err := repo.CountByUserID(context.Background(), primitive.NewObjectID(), domain.TaskFilter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	collection.AssertNumberOfCalls(t, "CountDocuments", 2)
+}
+
+// Test withRetry: other errors fail without a retry, and retries are bounded
+func TestWithRetry_FailsFast(t *testing.T) {
+	collection := new(MockCountCollection)
+	collection.On("CountDocuments", mock.Anything, mock.Anything).Return(int64(0), errors.New("bad query")).Once()
+	repo := &taskRepository{collection: withRetry(collection, 2, time.Millisecond)}
+
+	_, err := repo.CountByUserID(context.Background(), primitive.NewObjectID(), domain.TaskFilter{})
+	assert.EqualError(t, err, "bad query")
+	collection.AssertNumberOfCalls(t, "CountDocuments", 1)
+
+	collection = new(MockCountCollection)
+	networkErr := mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}}
+	collection.On("CountDocuments", mock.Anything, mock.Anything).Return(int64(0), networkErr)
+	repo = &taskRepository{collection: withRetry(collection, 2, time.Millisecond)}
+
+	_, err = repo.CountByUserID(context.Background(), primitive.NewObjectID(), domain.TaskFilter{})
+	assert.Equal(t, networkErr, err)
+	collection.AssertNumberOfCalls(t, "CountDocuments", 3)
+}
+
+// Test userRepository: Create assigns the inserted ID
+func TestUserRepository_Create_Mock(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	id := primitive.NewObjectID()
+	collection.On("InsertOne", mock.Anything, mock.Anything).Return(&mongo.InsertOneResult{InsertedID: id}, nil)
+	repo := &userRepository{collection: collection}
+
+	user, err := repo.Create(context.Background(), &domain.User{Email: "test@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.False(t, user.CreatedAt.IsZero())
+}
+
+// Test userRepository: a duplicate email is reported as ErrUserAlreadyExists
+func TestUserRepository_Create_DuplicateKey(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	collection.On("InsertOne", mock.Anything, mock.Anything).Return(nil, duplicate)
+	repo := &userRepository{collection: collection}
+
+	user, err := repo.Create(context.Background(), &domain.User{Email: "taken@example.com"})
+
+	assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	assert.Nil(t, user)
+}
+
+// Test userRepository: other insert failures are passed through
+func TestUserRepository_Create_InsertFails(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	collection.On("InsertOne", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+	repo := &userRepository{collection: collection}
+
+	_, err := repo.Create(context.Background(), &domain.User{Email: "test@example.com"})
+
+	assert.EqualError(t, err, "database error")
+}
+
+// Test userRepository: lookups that match nothing return no user and no error
+func TestUserRepository_GetByID_NotFound_Mock(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	id := primitive.NewObjectID()
+	collection.On("FindOne", mock.Anything, bson.M{"_id": id, "deleted_at": nil}).Return(nil, mongo.ErrNoDocuments)
+	collection.On("FindOne", mock.Anything, bson.M{"email": "missing@example.com", "deleted_at": nil}).Return(nil, mongo.ErrNoDocuments)
+	repo := &userRepository{collection: collection}
+
+	user, err := repo.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+
+	user, err = repo.GetByEmail(context.Background(), "missing@example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+// Test userRepository: a found document is decoded
+func TestUserRepository_GetByEmail_Found_Mock(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	id := primitive.NewObjectID()
+	collection.On("FindOne", mock.Anything, mock.Anything).Return(bson.M{"_id": id, "email": "test@example.com", "role": "user"}, nil)
+	repo := &userRepository{collection: collection}
+
+	user, err := repo.GetByEmail(context.Background(), "test@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, "test@example.com", user.Email)
+}
+
+// Test userRepository: updates that match no user are reported as not found
+func TestUserRepository_NotFound_Mock(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	collection.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	repo := &userRepository{collection: collection}
+
+	err := repo.IncrementTokenVersion(context.Background(), primitive.NewObjectID())
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	err = repo.Restore(context.Background(), primitive.NewObjectID(), time.Now())
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	err = repo.Update(context.Background(), &domain.User{ID: primitive.NewObjectID()})
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+}
+
+// Test taskRepository: updating a task that does not exist reports ErrTaskNotFound
+func TestTaskRepository_Update_NotFound_Mock(t *testing.T) {
+	collection := new(MockDocumentCollection)
+	collection.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	repo := &taskRepository{collection: collection}
+
+	err := repo.Update(context.Background(), &domain.Task{ID: primitive.NewObjectID(), Title: "Gone"})
+
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
 // Run the test suite
 func TestRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(RepositoryTestSuite))
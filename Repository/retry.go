@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// retryingCollection retries the reads of the wrapped collection when they
+// fail with a transient error. Writes pass straight through: an insert or a
+// conditional update may have been applied before the error was seen, so
+// repeating it is not safe.
+type retryingCollection struct {
+	CollectionInterface
+	retries int
+	backoff time.Duration
+}
+
+// withRetry wraps collection so transient read failures are retried up to
+// retries times. Zero or fewer retries returns collection unchanged.
+func withRetry(collection CollectionInterface, retries int, backoff time.Duration) CollectionInterface {
+	if retries <= 0 {
+		return collection
+	}
+	return &retryingCollection{CollectionInterface: collection, retries: retries, backoff: backoff}
+}
+
+// isTransient reports whether err is a network blip or timeout worth
+// retrying, as opposed to an error the same request would hit again
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) && labeled.HasErrorLabel("TransientTransactionError") {
+		return true
+	}
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err)
+}
+
+// do runs op until it succeeds, fails with a non-transient error or the
+// retries run out, doubling the wait between attempts
+func (c *retryingCollection) do(ctx context.Context, op func() error) error {
+	wait := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if attempt == c.retries || !isTransient(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+func (c *retryingCollection) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	var result *mongo.SingleResult
+	c.do(ctx, func() error {
+		result = c.CollectionInterface.FindOne(ctx, filter)
+		return result.Err()
+	})
+	return result
+}
+
+func (c *retryingCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	var cursor *mongo.Cursor
+	err := c.do(ctx, func() (err error) {
+		cursor, err = c.CollectionInterface.Find(ctx, filter, opts...)
+		return err
+	})
+	return cursor, err
+}
+
+func (c *retryingCollection) Distinct(ctx context.Context, fieldName string, filter interface{}) ([]interface{}, error) {
+	var values []interface{}
+	err := c.do(ctx, func() (err error) {
+		values, err = c.CollectionInterface.Distinct(ctx, fieldName, filter)
+		return err
+	})
+	return values, err
+}
+
+func (c *retryingCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	var count int64
+	err := c.do(ctx, func() (err error) {
+		count, err = c.CollectionInterface.CountDocuments(ctx, filter)
+		return err
+	})
+	return count, err
+}
+
+func (c *retryingCollection) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	var cursor *mongo.Cursor
+	err := c.do(ctx, func() (err error) {
+		cursor, err = c.CollectionInterface.Aggregate(ctx, pipeline)
+		return err
+	})
+	return cursor, err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// taskHistoryIndexes serves GetByTaskID's lookup and ordering
+var taskHistoryIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}},
+}
+
+// taskHistoryRepository implements domain.TaskHistoryRepository
+type taskHistoryRepository struct {
+	collection CollectionInterface
+}
+
+// NewTaskHistoryRepository initializes a new task history repository
+func NewTaskHistoryRepository(db *mongo.Database, opts ...Option) domain.TaskHistoryRepository {
+	return &taskHistoryRepository{
+		collection: wrapCollection(db.Collection(collectionName(domain.TaskHistoryCollection, opts)), resolveOptions(opts)),
+	}
+}
+
+// Create appends revision to the history. Revisions are never changed or
+// removed.
+func (r *taskHistoryRepository) Create(ctx context.Context, revision *domain.TaskRevision) error {
+	revision.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, revision)
+	if err != nil {
+		return err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to parse inserted ID as ObjectID")
+	}
+	revision.ID = id
+	return nil
+}
+
+// GetByTaskID returns the revisions of task taskID, oldest first
+func (r *taskHistoryRepository) GetByTaskID(ctx context.Context, taskID primitive.ObjectID, page domain.Pagination) (revisions []*domain.TaskRevision, err error) {
+	opts := paginate(page).SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	revisions = []*domain.TaskRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"regexp"
 	"time"
 
 	domain "Task-Management/Domain"
@@ -10,15 +12,22 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // CollectionInterface abstracts MongoDB collection operations
 type CollectionInterface interface {
 	InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, documents []interface{}) (*mongo.InsertManyResult, error)
 	FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult
-	Find(ctx context.Context, filter interface{}) (*mongo.Cursor, error)
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
 	UpdateOne(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error)
+	UpdateMany(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error)
 	DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error)
+	DeleteMany(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error)
+	Distinct(ctx context.Context, fieldName string, filter interface{}) ([]interface{}, error)
+	CountDocuments(ctx context.Context, filter interface{}) (int64, error)
+	Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error)
 }
 
 // MongoCollectionWrapper wraps *mongo.Collection to implement CollectionInterface
@@ -30,30 +39,76 @@ func (m *MongoCollectionWrapper) InsertOne(ctx context.Context, document interfa
 	return m.collection.InsertOne(ctx, document)
 }
 
+func (m *MongoCollectionWrapper) InsertMany(ctx context.Context, documents []interface{}) (*mongo.InsertManyResult, error) {
+	return m.collection.InsertMany(ctx, documents)
+}
+
 func (m *MongoCollectionWrapper) FindOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
 	return m.collection.FindOne(ctx, filter)
 }
 
-func (m *MongoCollectionWrapper) Find(ctx context.Context, filter interface{}) (*mongo.Cursor, error) {
-	return m.collection.Find(ctx, filter)
+func (m *MongoCollectionWrapper) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return m.collection.Find(ctx, filter, opts...)
 }
 
 func (m *MongoCollectionWrapper) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
 	return m.collection.DeleteOne(ctx, filter)
 }
 
+func (m *MongoCollectionWrapper) DeleteMany(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
+	return m.collection.DeleteMany(ctx, filter)
+}
+
+func (m *MongoCollectionWrapper) Distinct(ctx context.Context, fieldName string, filter interface{}) ([]interface{}, error) {
+	return m.collection.Distinct(ctx, fieldName, filter)
+}
+
+func (m *MongoCollectionWrapper) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	return m.collection.CountDocuments(ctx, filter)
+}
+
+func (m *MongoCollectionWrapper) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	return m.collection.Aggregate(ctx, pipeline)
+}
+
 func (m *MongoCollectionWrapper) UpdateOne(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error) {
 	return m.collection.UpdateOne(ctx, filter, update)
 }
 
+func (m *MongoCollectionWrapper) UpdateMany(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error) {
+	return m.collection.UpdateMany(ctx, filter, update)
+}
+
 // TaskRepository defines the expected behavior for the task repository
 type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) (*domain.Task, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Task, error)
-	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Task, error)
-	GetAll(ctx context.Context) ([]*domain.Task, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Task, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) ([]*domain.Task, error)
+	ForEachByUserID(ctx context.Context, userID primitive.ObjectID, fn func(*domain.Task) error) error
+	GetUpcoming(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*domain.Task, error)
+	GetCompleted(ctx context.Context, userID primitive.ObjectID, from, to *time.Time, page domain.Pagination) ([]*domain.Task, error)
+	Search(ctx context.Context, userID primitive.ObjectID, query string, page domain.Pagination) ([]*domain.Task, error)
+	GetRemindersDue(ctx context.Context, userID primitive.ObjectID, now time.Time, limit int64) ([]*domain.Task, error)
+	DistinctTags(ctx context.Context, userID primitive.ObjectID) ([]string, error)
+	CountByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) (int64, error)
+	LatestCreatedAt(ctx context.Context, userID primitive.ObjectID) (time.Time, error)
+	TitleTaken(ctx context.Context, userID primitive.ObjectID, title string, excludeID primitive.ObjectID) (bool, error)
+	StatsByUserID(ctx context.Context, userID primitive.ObjectID, now time.Time) (*domain.TaskStats, error)
+	TimeReportByUserID(ctx context.Context, userID primitive.ObjectID) (*domain.TimeReport, error)
+	CountOverdueByUser(ctx context.Context, now time.Time) ([]domain.OverdueCount, error)
+	GetAll(ctx context.Context, page domain.Pagination) ([]*domain.Task, error)
+	GetAllVisible(ctx context.Context, page domain.Pagination) ([]*domain.Task, error)
+	GetUnassigned(ctx context.Context, page domain.Pagination) ([]*domain.Task, error)
+	GetSharedWith(ctx context.Context, userID primitive.ObjectID, page domain.Pagination) ([]*domain.Task, error)
 	Update(ctx context.Context, task *domain.Task) error
+	UpdateFields(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) error
+	UpdateTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, add, remove []string) (int64, error)
+	Reorder(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (int64, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteCompletedByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	DeleteByUserIDs(ctx context.Context, userIDs []primitive.ObjectID) (int64, error)
+	SetOwnerDeleted(ctx context.Context, userID primitive.ObjectID, deleted bool) error
 }
 
 type taskRepository struct {
@@ -61,9 +116,9 @@ type taskRepository struct {
 }
 
 // NewTaskRepository initializes a new task repository
-func NewTaskRepository(db *mongo.Database) TaskRepository {
+func NewTaskRepository(db *mongo.Database, opts ...Option) TaskRepository {
 	return &taskRepository{
-		collection: &MongoCollectionWrapper{collection: db.Collection(domain.TaskCollection)},
+		collection: wrapCollection(db.Collection(collectionName(domain.TaskCollection, opts)), resolveOptions(opts)),
 	}
 }
 
@@ -100,42 +155,481 @@ func (r *taskRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*d
 	return &task, nil
 }
 
-func (r *taskRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+// GetByIDs fetches every task in ids with a single query. Unknown IDs are
+// skipped, so the result may be shorter than ids.
+func (r *taskRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) (tasks []*domain.Task, err error) {
+	if len(ids) == 0 {
+		return []*domain.Task{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// buildTaskFilter translates a domain.TaskFilter into a MongoDB query. A task
+// without a due date stores either nothing or the zero time.
+func buildTaskFilter(filter domain.TaskFilter) bson.M {
+	query := bson.M{}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+	dueDate := bson.M{}
+	if filter.DueFrom != nil {
+		dueDate["$gte"] = *filter.DueFrom
+	}
+	if filter.DueTo != nil {
+		dueDate["$lte"] = *filter.DueTo
+	}
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			dueDate["$gt"] = time.Time{}
+		} else {
+			dueDate["$in"] = bson.A{nil, time.Time{}}
+		}
+	}
+	if len(dueDate) > 0 {
+		query["due_date"] = dueDate
+	}
+	for key, value := range filter.Metadata {
+		query["metadata."+key] = value
+	}
+	return query
+}
+
+// matchRelation restricts query to the user's tasks selected by relation:
+// owned by them, assigned to them, or either. Tasks whose owner deleted
+// their account are left out.
+func matchRelation(query bson.M, userID primitive.ObjectID, relation string) {
+	query["owner_deleted"] = bson.M{"$ne": true}
+	switch relation {
+	case domain.RelationAssigned:
+		query["assignee_id"] = userID
+	case domain.RelationAll:
+		query["$or"] = bson.A{bson.M{"user_id": userID}, bson.M{"assignee_id": userID}}
+	default:
+		query["user_id"] = userID
+	}
+}
+
+// GetByUserID lists the user's tasks matching filter. No match yields an
+// empty slice rather than nil, so it marshals to [] instead of null.
+func (r *taskRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) (tasks []*domain.Task, err error) {
+	query := buildTaskFilter(filter)
+	matchRelation(query, userID, filter.Relation)
+	slog.DebugContext(ctx, "listing tasks", "query", query, "page", filter.Pagination.Page, "page_size", filter.Pagination.PageSize)
+	opts := paginate(filter.Pagination)
+	switch filter.Sort {
+	case domain.TaskSortOrder:
+		opts.SetSort(bson.D{{Key: "order", Value: 1}, {Key: "_id", Value: 1}})
+	case domain.TaskSortNewest:
+		opts.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	}
+	cursor, err := r.collection.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if closeErr := cursor.Close(ctx); closeErr != nil {
-			err = closeErr
+	defer closeCursor(ctx, cursor, &err)
+
+	tasks = []*domain.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ForEachByUserID calls fn with each task owned by the user, oldest first,
+// decoding them one at a time as the cursor advances. It stops at the first
+// error fn returns.
+func (r *taskRepository) ForEachByUserID(ctx context.Context, userID primitive.ObjectID, fn func(*domain.Task) error) (err error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	for cursor.Next(ctx) {
+		var task domain.Task
+		if err := cursor.Decode(&task); err != nil {
+			return err
+		}
+		if err := fn(&task); err != nil {
+			return err
 		}
-	}()
+	}
+	return cursor.Err()
+}
+
+// GetCompleted lists the user's completed tasks whose completed_at lies
+// within the inclusive from and to bounds, most recently completed first.
+// Nil bounds are open. No match yields an empty slice.
+func (r *taskRepository) GetCompleted(ctx context.Context, userID primitive.ObjectID, from, to *time.Time, page domain.Pagination) (tasks []*domain.Task, err error) {
+	query := bson.M{"user_id": userID, "status": domain.StatusCompleted}
+	completedAt := bson.M{}
+	if from != nil {
+		completedAt["$gte"] = *from
+	}
+	if to != nil {
+		completedAt["$lte"] = *to
+	}
+	if len(completedAt) > 0 {
+		query["completed_at"] = completedAt
+	}
+	opts := paginate(page).SetSort(bson.D{{Key: "completed_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	tasks = []*domain.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetUpcoming returns up to limit of the user's unfinished tasks that have a
+// due date, nearest due date first. A zero limit returns them all.
+func (r *taskRepository) GetUpcoming(ctx context.Context, userID primitive.ObjectID, limit int64) (tasks []*domain.Task, err error) {
+	query := bson.M{
+		"user_id":  userID,
+		"status":   bson.M{"$ne": domain.StatusCompleted},
+		"due_date": bson.M{"$gt": time.Time{}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Search returns the user's tasks whose title or description match query
+// according to the text index, most relevant first. Each task's Score holds
+// its text score.
+func (r *taskRepository) Search(ctx context.Context, userID primitive.ObjectID, query string, page domain.Pagination) (tasks []*domain.Task, err error) {
+	filter := bson.M{
+		"user_id": userID,
+		"$text":   bson.M{"$search": query},
+	}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}})
+	if page.Limit() > 0 {
+		opts.SetSkip(page.Skip()).SetLimit(page.Limit())
+	}
+	slog.DebugContext(ctx, "searching tasks", "query", filter, "page", page.Page, "page_size", page.PageSize)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetRemindersDue returns up to limit of the user's unfinished tasks whose
+// reminder time is at or before now, earliest reminder first. Tasks without
+// a reminder never match. A zero limit returns them all.
+func (r *taskRepository) GetRemindersDue(ctx context.Context, userID primitive.ObjectID, now time.Time, limit int64) (tasks []*domain.Task, err error) {
+	query := bson.M{
+		"user_id":   userID,
+		"status":    bson.M{"$ne": domain.StatusCompleted},
+		"remind_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "remind_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
 
-	var tasks []*domain.Task
+	tasks = []*domain.Task{}
 	if err := cursor.All(ctx, &tasks); err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
-func (r *taskRepository) GetAll(ctx context.Context) ([]*domain.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+// DistinctTags returns each tag used on the user's tasks once, in no
+// particular order
+func (r *taskRepository) DistinctTags(ctx context.Context, userID primitive.ObjectID) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "tags", bson.M{"user_id": userID})
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if closeErr := cursor.Close(ctx); closeErr != nil {
-			err = closeErr
+
+	tags := make([]string, 0, len(values))
+	for _, value := range values {
+		// Tasks saved without tags store null, which Distinct reports too
+		if tag, ok := value.(string); ok {
+			tags = append(tags, tag)
 		}
-	}()
+	}
+	return tags, nil
+}
+
+// CountByUserID returns the number of the user's tasks matching filter
+func (r *taskRepository) CountByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) (int64, error) {
+	query := buildTaskFilter(filter)
+	matchRelation(query, userID, filter.Relation)
+	slog.DebugContext(ctx, "counting tasks", "query", query)
+	return r.collection.CountDocuments(ctx, query)
+}
+
+// LatestCreatedAt returns when the user's most recently created task was
+// created, or the zero time if they own none
+func (r *taskRepository) LatestCreatedAt(ctx context.Context, userID primitive.ObjectID) (latest time.Time, err error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"created_at": 1}).
+		SetLimit(1)
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer closeCursor(ctx, cursor, &err)
 
-	var tasks []*domain.Task
+	var tasks []domain.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return time.Time{}, err
+	}
+	if len(tasks) == 0 {
+		return time.Time{}, nil
+	}
+	return tasks[0].CreatedAt, nil
+}
+
+// TitleTaken reports whether one of the user's unfinished tasks other than
+// excludeID has title, ignoring case
+func (r *taskRepository) TitleTaken(ctx context.Context, userID primitive.ObjectID, title string, excludeID primitive.ObjectID) (bool, error) {
+	query := bson.M{
+		"user_id": userID,
+		"title":   primitive.Regex{Pattern: "^" + regexp.QuoteMeta(title) + "$", Options: "i"},
+		"status":  bson.M{"$ne": domain.StatusCompleted},
+		"_id":     bson.M{"$ne": excludeID},
+	}
+	count, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// StatsByUserID counts the user's tasks per status in a single aggregation.
+// Unfinished tasks due before now are also counted as overdue.
+func (r *taskRepository) StatsByUserID(ctx context.Context, userID primitive.ObjectID, now time.Time) (stats *domain.TaskStats, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$status",
+			"count": bson.M{"$sum": 1},
+			"overdue": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					// A missing or zero due date sorts below time.Time{}
+					bson.M{"$gt": bson.A{"$due_date", time.Time{}}},
+					bson.M{"$lt": bson.A{"$due_date", now}},
+					bson.M{"$ne": bson.A{"$status", domain.StatusCompleted}},
+				}},
+				1,
+				0,
+			}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	var groups []struct {
+		Status  string `bson:"_id"`
+		Count   int64  `bson:"count"`
+		Overdue int64  `bson:"overdue"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	stats = &domain.TaskStats{}
+	for _, group := range groups {
+		stats.Total += group.Count
+		stats.Overdue += group.Overdue
+		switch group.Status {
+		case domain.StatusPending:
+			stats.Pending = group.Count
+		case domain.StatusInProgress:
+			stats.InProgress = group.Count
+		case domain.StatusCompleted:
+			stats.Completed = group.Count
+		}
+	}
+	return stats, nil
+}
+
+// TimeReportByUserID sums the estimated and actual minutes of a user's tasks
+// per status with a single aggregation
+func (r *taskRepository) TimeReportByUserID(ctx context.Context, userID primitive.ObjectID) (report *domain.TimeReport, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$status",
+			"tasks":     bson.M{"$sum": 1},
+			"estimated": bson.M{"$sum": "$estimated_minutes"},
+			"actual":    bson.M{"$sum": "$actual_minutes"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	var groups []struct {
+		Status    string `bson:"_id"`
+		Tasks     int64  `bson:"tasks"`
+		Estimated int64  `bson:"estimated"`
+		Actual    int64  `bson:"actual"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	report = &domain.TimeReport{}
+	for _, status := range []string{domain.StatusPending, domain.StatusInProgress, domain.StatusCompleted} {
+		entry := domain.StatusTime{Status: status}
+		for _, group := range groups {
+			if group.Status == status {
+				entry.Tasks = group.Tasks
+				entry.EstimatedMinutes = group.Estimated
+				entry.ActualMinutes = group.Actual
+			}
+		}
+		report.EstimatedMinutes += entry.EstimatedMinutes
+		report.ActualMinutes += entry.ActualMinutes
+		report.ByStatus = append(report.ByStatus, entry)
+	}
+	return report, nil
+}
+
+// CountOverdueByUser counts the unfinished tasks due before now per owner,
+// most overdue first. Tasks without a due date are never overdue, and users
+// without overdue tasks are left out.
+func (r *taskRepository) CountOverdueByUser(ctx context.Context, now time.Time) (counts []domain.OverdueCount, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"due_date": bson.M{"$gt": time.Time{}, "$lt": now},
+			"status":   bson.M{"$ne": domain.StatusCompleted},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$user_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	counts = []domain.OverdueCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *taskRepository) GetAll(ctx context.Context, page domain.Pagination) (tasks []*domain.Task, err error) {
+	slog.DebugContext(ctx, "listing all tasks", "page", page.Page, "page_size", page.PageSize)
+	cursor, err := r.collection.Find(ctx, bson.M{}, paginate(page))
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetAllVisible lists every user's tasks like GetAll, leaving out those
+// whose owner deleted their account
+func (r *taskRepository) GetAllVisible(ctx context.Context, page domain.Pagination) (tasks []*domain.Task, err error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_deleted": bson.M{"$ne": true}}, paginate(page))
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	tasks = []*domain.Task{}
 	if err := cursor.All(ctx, &tasks); err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
+// GetUnassigned lists every user's tasks that have no assignee. A nil match
+// covers both a stored null and a missing field.
+func (r *taskRepository) GetUnassigned(ctx context.Context, page domain.Pagination) (tasks []*domain.Task, err error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"assignee_id": nil}, paginate(page))
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	tasks = []*domain.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetSharedWith lists the tasks other users have shared with userID, leaving
+// out those of deleted accounts. No match yields an empty slice.
+func (r *taskRepository) GetSharedWith(ctx context.Context, userID primitive.ObjectID, page domain.Pagination) (tasks []*domain.Task, err error) {
+	query := bson.M{
+		"shared_with":   userID,
+		"user_id":       bson.M{"$ne": userID},
+		"owner_deleted": bson.M{"$ne": true},
+	}
+	cursor, err := r.collection.Find(ctx, query, paginate(page))
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	tasks = []*domain.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Update replaces the stored task with task. A task that no longer exists is
+// reported as ErrTaskNotFound.
 func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
 	task.UpdatedAt = time.Now()
 	result, err := r.collection.UpdateOne(
@@ -147,18 +641,142 @@ func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
 		return err
 	}
 	if result.MatchedCount == 0 {
-		return errors.New("no document found to update")
+		return domain.ErrTaskNotFound
 	}
 	return nil
 }
 
+// UpdateFields sets the given fields of task id, such as "status" or
+// "checklist.2.done", with a single $set and bumps updated_at. Unlike Update
+// it does not write the rest of the document, so concurrent changes to other
+// fields are kept, and the fields are changed together or not at all.
+func (r *taskRepository) UpdateFields(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return errors.New("no fields to update")
+	}
+	set := bson.M{"updated_at": time.Now()}
+	for field, value := range fields {
+		if field == "" || field == "_id" || field == "updated_at" {
+			return errors.New("field cannot be updated: " + field)
+		}
+		set[field] = value
+	}
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// UpdateTags adds and removes tags on those of ids owned by userID and
+// returns how many tasks matched. MongoDB cannot $addToSet and $pull the same
+// field in one update, so both are applied by a single update pipeline: tags
+// not already present are appended in order, then removed ones are filtered
+// out, so a tag named in both ends up removed. The tags are passed through
+// $literal so that one starting with "$" is not read as a field path.
+func (r *taskRepository) UpdateTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, add, remove []string) (int64, error) {
+	existing := bson.M{"$ifNull": bson.A{"$tags", bson.A{}}}
+	added := bson.M{"$filter": bson.M{
+		"input": bson.M{"$literal": uniqueStrings(add)},
+		"cond":  bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$this", existing}}}},
+	}}
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "user_id": userID},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"tags": bson.M{"$filter": bson.M{
+				"input": bson.M{"$concatArrays": bson.A{existing, added}},
+				"cond": bson.M{"$not": bson.A{bson.M{"$in": bson.A{
+					"$$this", bson.M{"$literal": uniqueStrings(remove)},
+				}}}},
+			}},
+			"updated_at": time.Now(),
+		}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+// uniqueStrings returns values without repeats, keeping the first occurrence
+// of each. The result is never nil, so it encodes as an array.
+func uniqueStrings(values []string) []string {
+	unique := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// Reorder gives each of the user's tasks in ids its index in ids as its
+// order, in a single UpdateMany. The update pipeline looks the position up
+// per document, so the whole list is written at once.
+func (r *taskRepository) Reorder(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "user_id": userID},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"order":      bson.M{"$indexOfArray": bson.A{ids, "$_id"}},
+			"updated_at": time.Now(),
+		}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
 func (r *taskRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
 		return err
 	}
 	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+		return domain.ErrTaskNotFound
 	}
 	return nil
 }
+
+// DeleteCompletedByUserID removes all of the user's completed tasks and
+// returns how many were deleted
+func (r *taskRepository) DeleteCompletedByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID, "status": domain.StatusCompleted})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteByUserIDs removes every task owned by one of userIDs and returns how
+// many were deleted
+func (r *taskRepository) DeleteByUserIDs(ctx context.Context, userIDs []primitive.ObjectID) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// SetOwnerDeleted marks or unmarks every task of the user as belonging to a
+// deleted account
+func (r *taskRepository) SetOwnerDeleted(ctx context.Context, userID primitive.ObjectID, deleted bool) error {
+	update := bson.M{"$unset": bson.M{"owner_deleted": ""}}
+	if deleted {
+		update = bson.M{"$set": bson.M{"owner_deleted": true}}
+	}
+	_, err := r.collection.UpdateMany(ctx, bson.M{"user_id": userID}, update)
+	return err
+}
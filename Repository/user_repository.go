@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"regexp"
 	"time"
 
 	domain "Task-Management/Domain"
@@ -10,24 +12,30 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // userRepository implements domain.UserRepository
 type userRepository struct {
-	collection *mongo.Collection
+	collection CollectionInterface
 }
 
-func NewUserRepository(db *mongo.Database) domain.UserRepository {
+func NewUserRepository(db *mongo.Database, opts ...Option) domain.UserRepository {
 	return &userRepository{
-		collection: db.Collection(domain.UserCollection),
+		collection: wrapCollection(db.Collection(collectionName(domain.UserCollection, opts)), resolveOptions(opts)),
 	}
 }
 
+// Create inserts user. An email already taken by an active account is
+// reported as ErrUserAlreadyExists.
 func (r *userRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
 	result, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrUserAlreadyExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -40,9 +48,34 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) (*domain
 	return user, nil
 }
 
+// CreateMany inserts all users in a single InsertMany call
+func (r *userRepository) CreateMany(ctx context.Context, users []*domain.User) ([]*domain.User, error) {
+	now := time.Now()
+	documents := make([]interface{}, len(users))
+	for i, user := range users {
+		user.CreatedAt = now
+		user.UpdatedAt = now
+		documents[i] = user
+	}
+
+	result, err := r.collection.InsertMany(ctx, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, insertedID := range result.InsertedIDs {
+		id, ok := insertedID.(primitive.ObjectID)
+		if !ok {
+			return nil, errors.New("failed to parse inserted ID as ObjectID")
+		}
+		users[i].ID = id
+	}
+	return users, nil
+}
+
 func (r *userRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
 	var user domain.User
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil // Return nil if no document is found
@@ -52,9 +85,28 @@ func (r *userRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*d
 	return &user, nil
 }
 
+// GetByIDs fetches every user in ids with a single query. Unknown and
+// soft-deleted users are skipped, so the result may be shorter than ids.
+func (r *userRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) (users []*domain.User, err error) {
+	if len(ids) == 0 {
+		return []*domain.User{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.collection.FindOne(ctx, bson.M{"email": email, "deleted_at": nil}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil // Return nil if no document is found
@@ -64,26 +116,89 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return &user, nil
 }
 
-func (r *userRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+// buildUserFilter translates a domain.UserFilter into a MongoDB query
+func buildUserFilter(filter domain.UserFilter) bson.M {
+	query := bson.M{}
+	if !filter.IncludeDeleted {
+		// Matches documents where deleted_at is missing or null
+		query["deleted_at"] = nil
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	if filter.CreatedFrom != nil || filter.CreatedTo != nil {
+		createdAt := bson.M{}
+		if filter.CreatedFrom != nil {
+			createdAt["$gte"] = *filter.CreatedFrom
+		}
+		if filter.CreatedTo != nil {
+			createdAt["$lte"] = *filter.CreatedTo
+		}
+		query["created_at"] = createdAt
+	}
+	return query
+}
+
+func (r *userRepository) GetAll(ctx context.Context, filter domain.UserFilter) (users []*domain.User, err error) {
+	query := buildUserFilter(filter)
+	slog.DebugContext(ctx, "listing users", "query", query, "page", filter.Pagination.Page, "page_size", filter.Pagination.PageSize)
+	cursor, err := r.collection.Find(ctx, query, paginate(filter.Pagination))
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Search finds the users whose name or email contains query, ignoring case,
+// ordered by name. Soft-deleted users are skipped.
+func (r *userRepository) Search(ctx context.Context, query string, page domain.Pagination) (users []*domain.User, err error) {
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+	filter := bson.M{
+		"deleted_at": nil,
+		"$or": bson.A{
+			bson.M{"name": pattern},
+			bson.M{"email": pattern},
+		},
+	}
+	opts := paginate(page).SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	slog.DebugContext(ctx, "searching users", "query", filter, "page", page.Page, "page_size", page.PageSize)
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
+	defer closeCursor(ctx, cursor, &err)
 
-	var users []*domain.User
+	users = []*domain.User{}
 	if err := cursor.All(ctx, &users); err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
+// Update writes every field of user except token_version, which only
+// IncrementTokenVersion changes. Otherwise saving a user loaded before a
+// revocation would restore the old version and revive its tokens.
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
+	raw, err := bson.Marshal(user)
+	if err != nil {
+		return err
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	delete(fields, "token_version")
+
 	result, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": user.ID},
-		bson.M{"$set": user},
+		bson.M{"$set": fields},
 	)
 	if err != nil {
 		return err
@@ -94,7 +209,86 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// IncrementTokenVersion invalidates every token issued to the user so far
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$inc": bson.M{"token_version": 1}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes the user by stamping deleted_at. The document is kept
+// so the account remains visible to admins listing deleted users.
 func (r *userRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	now := time.Now()
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}},
+	)
 	return err
 }
+
+// Restore undoes the soft delete of user id if it was deleted after
+// deletedAfter. A user that is not deleted, or was deleted earlier, is
+// reported as ErrUserNotFound, and one whose email an active account has
+// taken since as ErrUserAlreadyExists.
+func (r *userRepository) Restore(ctx context.Context, id primitive.ObjectID, deletedAfter time.Time) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$gte": deletedAfter}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrUserAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// ListDeletedBefore returns the IDs of the users soft-deleted before before
+func (r *userRepository) ListDeletedBefore(ctx context.Context, before time.Time) (ids []primitive.ObjectID, err error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor, &err)
+
+	var users []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	ids = make([]primitive.ObjectID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids, nil
+}
+
+// Purge permanently removes those of ids that are still soft-deleted and
+// returns how many were removed
+func (r *userRepository) Purge(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,62 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+
+	domain "Task-Management/Domain"
+	infrastructure "Task-Management/Infrastructure"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type apiKeyUseCase struct {
+	apiKeyRepo     domain.APIKeyRepository
+	generateAPIKey func() (string, error)
+	hashAPIKey     func(string) string
+}
+
+func NewAPIKeyUseCase(apiKeyRepo domain.APIKeyRepository) domain.APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo:     apiKeyRepo,
+		generateAPIKey: infrastructure.GenerateAPIKey, // Default implementation
+		hashAPIKey:     infrastructure.HashAPIKey,     // Default implementation
+	}
+}
+
+// GenerateKey issues a new key for the named service. The plain key is only
+// available from this call; the repository stores its hash.
+func (a *apiKeyUseCase) GenerateKey(ctx context.Context, name string) (*domain.APIKey, string, error) {
+	if name == "" {
+		return nil, "", errors.New("api key name is required")
+	}
+
+	rawKey, err := a.generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, err := a.apiKeyRepo.Create(ctx, &domain.APIKey{
+		Name:    name,
+		KeyHash: a.hashAPIKey(rawKey),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return key, rawKey, nil
+}
+
+func (a *apiKeyUseCase) RevokeKey(ctx context.Context, id primitive.ObjectID) error {
+	return a.apiKeyRepo.Revoke(ctx, id)
+}
+
+func (a *apiKeyUseCase) ValidateKey(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	key, err := a.apiKeyRepo.GetByHash(ctx, a.hashAPIKey(rawKey))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.Revoked {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	return key, nil
+}
@@ -0,0 +1,127 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "Task-Management/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockAPIKeyRepository is a mock implementation of the APIKeyRepository interface
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// APIKeyUseCaseTestSuite groups all API key use case-related tests
+type APIKeyUseCaseTestSuite struct {
+	suite.Suite
+	mockRepo *MockAPIKeyRepository
+	useCase  *apiKeyUseCase
+}
+
+// SetupTest runs before each test
+func (suite *APIKeyUseCaseTestSuite) SetupTest() {
+	suite.mockRepo = new(MockAPIKeyRepository)
+	suite.useCase = &apiKeyUseCase{
+		apiKeyRepo:     suite.mockRepo,
+		generateAPIKey: func() (string, error) { return "tm_plain", nil },
+		hashAPIKey:     func(key string) string { return "hash:" + key },
+	}
+}
+
+// TestGenerateKey_StoresHash tests that only the hash is persisted and the plain key is returned
+func (suite *APIKeyUseCaseTestSuite) TestGenerateKey_StoresHash() {
+	suite.mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(key *domain.APIKey) bool {
+		return key.Name == "billing" && key.KeyHash == "hash:tm_plain"
+	})).Return(&domain.APIKey{ID: primitive.NewObjectID(), Name: "billing", KeyHash: "hash:tm_plain"}, nil)
+
+	key, rawKey, err := suite.useCase.GenerateKey(context.Background(), "billing")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "tm_plain", rawKey)
+	assert.Equal(suite.T(), "billing", key.Name)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestGenerateKey_NameRequired tests that a name must be supplied
+func (suite *APIKeyUseCaseTestSuite) TestGenerateKey_NameRequired() {
+	key, rawKey, err := suite.useCase.GenerateKey(context.Background(), "")
+
+	assert.Nil(suite.T(), key)
+	assert.Empty(suite.T(), rawKey)
+	assert.EqualError(suite.T(), err, "api key name is required")
+}
+
+// TestValidateKey_Valid tests validating an active key
+func (suite *APIKeyUseCaseTestSuite) TestValidateKey_Valid() {
+	stored := &domain.APIKey{ID: primitive.NewObjectID(), Name: "billing"}
+	suite.mockRepo.On("GetByHash", mock.Anything, "hash:tm_valid").Return(stored, nil)
+
+	key, err := suite.useCase.ValidateKey(context.Background(), "tm_valid")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), stored, key)
+}
+
+// TestValidateKey_Unknown tests validating a key that was never issued
+func (suite *APIKeyUseCaseTestSuite) TestValidateKey_Unknown() {
+	suite.mockRepo.On("GetByHash", mock.Anything, "hash:tm_unknown").Return(nil, nil)
+
+	key, err := suite.useCase.ValidateKey(context.Background(), "tm_unknown")
+
+	assert.Nil(suite.T(), key)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)
+}
+
+// TestValidateKey_Revoked tests that a revoked key is rejected
+func (suite *APIKeyUseCaseTestSuite) TestValidateKey_Revoked() {
+	stored := &domain.APIKey{ID: primitive.NewObjectID(), Name: "billing", Revoked: true}
+	suite.mockRepo.On("GetByHash", mock.Anything, "hash:tm_revoked").Return(stored, nil)
+
+	key, err := suite.useCase.ValidateKey(context.Background(), "tm_revoked")
+
+	assert.Nil(suite.T(), key)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)
+}
+
+// TestRevokeKey_RepositoryError tests that revocation errors are passed through
+func (suite *APIKeyUseCaseTestSuite) TestRevokeKey_RepositoryError() {
+	id := primitive.NewObjectID()
+	suite.mockRepo.On("Revoke", mock.Anything, id).Return(errors.New("repository error"))
+
+	err := suite.useCase.RevokeKey(context.Background(), id)
+
+	assert.EqualError(suite.T(), err, "repository error")
+}
+
+// Run the test suite
+func TestAPIKeyUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyUseCaseTestSuite))
+}
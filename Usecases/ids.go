@@ -0,0 +1,16 @@
+package Usecases
+
+import (
+	domain "Task-Management/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// requireID returns domain.ErrInvalidID for the zero ObjectID. Without the
+// check a missing ID would be passed on and silently look up the zero ID.
+func requireID(id primitive.ObjectID) error {
+	if id.IsZero() {
+		return domain.ErrInvalidID
+	}
+	return nil
+}
@@ -3,78 +3,1636 @@ package Usecases
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	domain "Task-Management/Domain"
+	infrastructure "Task-Management/Infrastructure"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type taskUseCase struct {
-	taskRepo domain.TaskRepository
+	taskRepo      domain.TaskRepository
+	userRepo      domain.UserRepository
+	clock         domain.Clock
+	location      *time.Location
+	strictDelete  bool
+	taskQuota     int
+	createGap     time.Duration
+	batchLimit    int
+	maxTags       int
+	maxTagLength  int
+	maxMetaKeys   int
+	maxMetaKeyLen int
+	maxMetaValLen int
+	defaultStatus string
+	defaultDueIn  time.Duration
+	dueEndOfDay   bool
+	autoAssign    bool
+	sharedTasks   bool
+	noRegression  bool
+	uniqueTitles  bool
+	auditRepo     domain.AuditRepository
+	historyRepo   domain.TaskHistoryRepository
 }
 
-func NewTaskUseCase(taskRepo domain.TaskRepository) domain.TaskUseCase {
-	return &taskUseCase{
-		taskRepo: taskRepo,
+// previewSampleSize caps the tasks returned with a filter preview
+const previewSampleSize = 5
+
+// defaultBatchLimit caps the IDs per GetTasksByIDs call unless
+// WithBatchLimit overrides it
+const defaultBatchLimit = 100
+
+// Tag limits applied unless WithTagLimits overrides them
+const (
+	defaultMaxTags      = 10
+	defaultMaxTagLength = 32
+)
+
+// Metadata limits applied unless WithMetadataLimits overrides them
+const (
+	defaultMaxMetadataKeys        = 20
+	defaultMaxMetadataKeyLength   = 64
+	defaultMaxMetadataValueLength = 256
+)
+
+// TaskUseCaseOption customizes the task use case
+type TaskUseCaseOption func(*taskUseCase)
+
+// WithStrictDelete makes DeleteTask report ErrTaskNotFound for a task that
+// does not exist instead of treating the delete as already done
+func WithStrictDelete(strict bool) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.strictDelete = strict
+	}
+}
+
+// WithUserRepository gives the use case access to task owners, which is
+// required by GetAllTasksWithOwners
+func WithUserRepository(userRepo domain.UserRepository) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.userRepo = userRepo
+	}
+}
+
+// WithClock replaces the system clock used for due date validation
+func WithClock(clock domain.Clock) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.clock = clock
+	}
+}
+
+// WithLocation sets the timezone used to resolve calendar days, such as the
+// due date filter, the agenda buckets and end-of-day default due dates.
+// Defaults to UTC.
+func WithLocation(loc *time.Location) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		if loc != nil {
+			t.location = loc
+		}
+	}
+}
+
+// WithTaskQuota limits how many tasks a non-admin user may own. Zero or a
+// negative value disables the limit. Admins are recognized through the user
+// repository, so without WithUserRepository every owner is counted.
+func WithTaskQuota(quota int) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.taskQuota = quota
+	}
+}
+
+// WithCreateInterval sets the minimum time a non-admin user must wait
+// between two task creations, measured from the creation time of their
+// newest task. Zero or a negative value disables the throttle. Admins are
+// recognized through the user repository, as for WithTaskQuota.
+func WithCreateInterval(interval time.Duration) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.createGap = interval
+	}
+}
+
+// WithDefaultStatus sets the status given to new tasks that do not specify
+// one. Unknown statuses keep the default, pending.
+func WithDefaultStatus(status string) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		if domain.IsValidStatus(status) {
+			t.defaultStatus = status
+		}
+	}
+}
+
+// WithDefaultDueDate gives new tasks without a due date one computed from
+// the current time: in later, then moved to the end of that day in the
+// configured timezone when endOfDay is set. With neither, tasks keep the
+// date they were sent with.
+func WithDefaultDueDate(in time.Duration, endOfDay bool) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		if in > 0 {
+			t.defaultDueIn = in
+		}
+		t.dueEndOfDay = endOfDay
+	}
+}
+
+// WithAutoAssign makes the creator the assignee of new tasks that are created
+// without one, and the new owner the assignee of transferred tasks. When off,
+// such tasks stay unassigned.
+func WithAutoAssign(enabled bool) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.autoAssign = enabled
+	}
+}
+
+// WithSharedTasks lets every user read every task. Changes stay limited to
+// the owner and admins. When off, users only see their own tasks.
+func WithSharedTasks(shared bool) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.sharedTasks = shared
+	}
+}
+
+// WithUniqueTitles rejects creating or updating an unfinished task whose
+// title, ignoring case, matches another unfinished task of the same owner
+// with ErrDuplicateTaskTitle. Completed tasks never conflict.
+func WithUniqueTitles(enabled bool) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.uniqueTitles = enabled
+	}
+}
+
+// WithNoStatusRegression rejects updates that move an in-progress task back
+// to pending with ErrStatusRegression. When off, that move is allowed.
+func WithNoStatusRegression(enabled bool) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.noRegression = enabled
+	}
+}
+
+// WithTaskAuditRepository records admin overrides such as ForceCompleteTask
+func WithTaskAuditRepository(auditRepo domain.AuditRepository) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.auditRepo = auditRepo
+	}
+}
+
+// WithTaskHistoryRepository records a revision for every UpdateTask that
+// changes a task and enables GetTaskHistory
+func WithTaskHistoryRepository(historyRepo domain.TaskHistoryRepository) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		t.historyRepo = historyRepo
+	}
+}
+
+// WithBatchLimit sets how many IDs one GetTasksByIDs call may name. Values
+// below one keep the default.
+func WithBatchLimit(limit int) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		if limit > 0 {
+			t.batchLimit = limit
+		}
+	}
+}
+
+// WithTagLimits caps how many tags one task may carry and how many characters
+// each tag may have. Values below one keep the defaults.
+func WithTagLimits(maxTags, maxTagLength int) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		if maxTags > 0 {
+			t.maxTags = maxTags
+		}
+		if maxTagLength > 0 {
+			t.maxTagLength = maxTagLength
+		}
+	}
+}
+
+// WithMetadataLimits caps how many metadata keys one task may carry and how
+// many characters each key and value may have. Values below one keep the
+// defaults.
+func WithMetadataLimits(maxKeys, maxKeyLength, maxValueLength int) TaskUseCaseOption {
+	return func(t *taskUseCase) {
+		if maxKeys > 0 {
+			t.maxMetaKeys = maxKeys
+		}
+		if maxKeyLength > 0 {
+			t.maxMetaKeyLen = maxKeyLength
+		}
+		if maxValueLength > 0 {
+			t.maxMetaValLen = maxValueLength
+		}
+	}
+}
+
+func NewTaskUseCase(taskRepo domain.TaskRepository, opts ...TaskUseCaseOption) domain.TaskUseCase {
+	t := &taskUseCase{
+		taskRepo:      taskRepo,
+		clock:         infrastructure.SystemClock{},
+		location:      time.UTC,
+		batchLimit:    defaultBatchLimit,
+		maxTags:       defaultMaxTags,
+		maxTagLength:  defaultMaxTagLength,
+		maxMetaKeys:   defaultMaxMetadataKeys,
+		maxMetaKeyLen: defaultMaxMetadataKeyLength,
+		maxMetaValLen: defaultMaxMetadataValueLength,
+		defaultStatus: domain.StatusPending,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *taskUseCase) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	if task.DueDate.IsZero() && (t.defaultDueIn > 0 || t.dueEndOfDay) {
+		task.DueDate = t.defaultDueDate()
+	}
+
+	// Validate task
+	if task.Title == "" {
+		return nil, errors.New("task title is required")
+	}
+	// A task may have no due date, which is not one in the past
+	if !task.DueDate.IsZero() && task.DueDate.Before(t.clock.Now()) {
+		return nil, errors.New("due date cannot be in the past")
+	}
+	if err := t.validateReminder(task); err != nil {
+		return nil, err
+	}
+	if task.EstimatedMinutes < 0 || task.ActualMinutes < 0 {
+		return nil, domain.ErrNegativeMinutes
+	}
+	if err := t.validateTags(task.Tags); err != nil {
+		return nil, err
+	}
+	if err := t.validateMetadata(task.Metadata); err != nil {
+		return nil, err
+	}
+	if task.Status != "" && !domain.IsValidStatus(task.Status) {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	// Set initial status unless the client chose one
+	if task.Status == "" {
+		task.Status = t.defaultStatus
+	}
+
+	dependencies, err := t.checkDependencies(ctx, task, task.UserID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocked(task.Status, dependencies); err != nil {
+		return nil, err
+	}
+	if err := t.checkQuota(ctx, task.UserID); err != nil {
+		return nil, err
+	}
+	if err := t.checkCreateInterval(ctx, task.UserID); err != nil {
+		return nil, err
+	}
+	if err := t.checkUniqueTitle(ctx, task); err != nil {
+		return nil, err
+	}
+
+	task.CompletedAt = nil
+	if task.Status == domain.StatusCompleted {
+		now := t.clock.Now()
+		task.CompletedAt = &now
+	}
+	if t.autoAssign && task.AssigneeID == nil {
+		creator := task.UserID
+		task.AssigneeID = &creator
+	}
+	// Shares are managed through ShareTask, which checks the users exist
+	task.SharedWith = nil
+	task.SharedEditors = nil
+
+	return t.taskRepo.Create(ctx, task)
+}
+
+// defaultDueDate returns the due date WithDefaultDueDate selects for a task
+// created now
+func (t *taskUseCase) defaultDueDate() time.Time {
+	due := t.clock.Now().In(t.location).Add(t.defaultDueIn)
+	if t.dueEndOfDay {
+		_, due = dayRange(due, t.location)
+	}
+	return due
+}
+
+// validateReminder checks that an optional reminder lies in the future and,
+// when the task has a due date, strictly before it
+func (t *taskUseCase) validateReminder(task *domain.Task) error {
+	if task.RemindAt == nil {
+		return nil
+	}
+	if !task.RemindAt.After(t.clock.Now()) {
+		return domain.ErrReminderInPast
+	}
+	if !task.DueDate.IsZero() && !task.RemindAt.Before(task.DueDate) {
+		return domain.ErrReminderAfterDueDate
+	}
+	return nil
+}
+
+// validateTags checks tags against the configured count and length limits.
+// Length is counted in characters, not bytes.
+func (t *taskUseCase) validateTags(tags []string) error {
+	if len(tags) > t.maxTags {
+		return fmt.Errorf("%w: at most %d allowed", domain.ErrTooManyTags, t.maxTags)
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > t.maxTagLength {
+			return fmt.Errorf("%w: %q exceeds %d characters", domain.ErrTagTooLong, tag, t.maxTagLength)
+		}
+	}
+	return nil
+}
+
+// validateMetadata applies the metadata limits to a task's custom fields
+func (t *taskUseCase) validateMetadata(metadata map[string]string) error {
+	if len(metadata) > t.maxMetaKeys {
+		return fmt.Errorf("%w: at most %d allowed", domain.ErrTooManyMetadataKeys, t.maxMetaKeys)
+	}
+	for key, value := range metadata {
+		if !domain.IsValidMetadataKey(key) {
+			return fmt.Errorf("%w: %q", domain.ErrInvalidMetadataKey, key)
+		}
+		if utf8.RuneCountInString(key) > t.maxMetaKeyLen {
+			return fmt.Errorf("%w: key %q exceeds %d characters", domain.ErrMetadataTooLong, key, t.maxMetaKeyLen)
+		}
+		if utf8.RuneCountInString(value) > t.maxMetaValLen {
+			return fmt.Errorf("%w: value of %q exceeds %d characters", domain.ErrMetadataTooLong, key, t.maxMetaValLen)
+		}
+	}
+	return nil
+}
+
+// checkQuota returns ErrTaskQuotaExceeded when the owner already has
+// taskQuota tasks. Admins are exempt.
+func (t *taskUseCase) checkQuota(ctx context.Context, userID primitive.ObjectID) error {
+	if t.taskQuota <= 0 {
+		return nil
+	}
+	if admin, err := t.isAdmin(ctx, userID); err != nil || admin {
+		return err
+	}
+
+	count, err := t.taskRepo.CountByUserID(ctx, userID, domain.TaskFilter{})
+	if err != nil {
+		return err
+	}
+	if count >= int64(t.taskQuota) {
+		return domain.ErrTaskQuotaExceeded
+	}
+	return nil
+}
+
+// checkCreateInterval returns ErrTaskCreateThrottled when the owner's newest
+// task was created less than createGap ago. Admins are exempt.
+func (t *taskUseCase) checkCreateInterval(ctx context.Context, userID primitive.ObjectID) error {
+	if t.createGap <= 0 {
+		return nil
+	}
+	if admin, err := t.isAdmin(ctx, userID); err != nil || admin {
+		return err
+	}
+
+	latest, err := t.taskRepo.LatestCreatedAt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !latest.IsZero() && t.clock.Now().Sub(latest) < t.createGap {
+		return domain.ErrTaskCreateThrottled
+	}
+	return nil
+}
+
+// checkUniqueTitle returns ErrDuplicateTaskTitle when unique titles are
+// enforced and another unfinished task of the owner already has task's title.
+// The check is a query, so two concurrent writes may still both pass it.
+func (t *taskUseCase) checkUniqueTitle(ctx context.Context, task *domain.Task) error {
+	if !t.uniqueTitles || task.Status == domain.StatusCompleted {
+		return nil
+	}
+	taken, err := t.taskRepo.TitleTaken(ctx, task.UserID, task.Title, task.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return domain.ErrDuplicateTaskTitle
+	}
+	return nil
+}
+
+// isAdmin reports whether userID is an admin. Without a user repository no
+// one is.
+func (t *taskUseCase) isAdmin(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	if t.userRepo == nil {
+		return false, nil
+	}
+	user, err := t.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && user.Role == domain.RoleAdmin, nil
+}
+
+// GetTaskByID returns task id if callerID may read it. Tasks the caller
+// cannot see are reported as ErrTaskNotFound.
+func (t *taskUseCase) GetTaskByID(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool) (*domain.Task, error) {
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || !t.canRead(task, callerID, asAdmin) {
+		return nil, domain.ErrTaskNotFound
+	}
+	t.markOverdue(task)
+	return task, nil
+}
+
+// markOverdue sets IsOverdue on each task as of the use case clock
+func (t *taskUseCase) markOverdue(tasks ...*domain.Task) {
+	now := t.clock.Now()
+	for _, task := range tasks {
+		task.IsOverdue = task.OverdueAt(now)
+	}
+}
+
+// listWithOverdue passes a repository listing on with IsOverdue set
+func (t *taskUseCase) listWithOverdue(tasks []*domain.Task, err error) ([]*domain.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	t.markOverdue(tasks...)
+	return tasks, nil
+}
+
+// canRead reports whether callerID may see task: its owner, admins and the
+// users it is shared with always can, everyone else only in shared mode.
+// Once the owner deleted their account only admins can.
+func (t *taskUseCase) canRead(task *domain.Task, callerID primitive.ObjectID, asAdmin bool) bool {
+	if task.OwnerDeleted {
+		return asAdmin
+	}
+	return asAdmin || task.UserID == callerID || t.sharedTasks || containsID(task.SharedWith, callerID)
+}
+
+// checkWrite returns nil if callerID may change task: its owner, admins and
+// the users it is shared with for writing. Others get ErrNotTaskOwner if they
+// can see the task and ErrTaskNotFound otherwise.
+func (t *taskUseCase) checkWrite(task *domain.Task, callerID primitive.ObjectID, asAdmin bool) error {
+	if !task.OwnerDeleted && containsID(task.SharedEditors, callerID) {
+		return nil
+	}
+	return t.checkOwner(task, callerID, asAdmin)
+}
+
+// checkOwner returns nil if callerID owns task or is an admin, and otherwise
+// reports the task like checkWrite
+func (t *taskUseCase) checkOwner(task *domain.Task, callerID primitive.ObjectID, asAdmin bool) error {
+	if asAdmin || task.UserID == callerID {
+		return nil
+	}
+	if t.canRead(task, callerID, asAdmin) {
+		return domain.ErrNotTaskOwner
+	}
+	return domain.ErrTaskNotFound
+}
+
+// containsID reports whether ids includes id
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeID returns ids without id
+func removeID(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	kept := make([]primitive.ObjectID, 0, len(ids))
+	for _, candidate := range ids {
+		if candidate != id {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// GetTasksByIDs fetches several tasks in one query. Tasks userID may read are
+// returned; the other IDs are reported as missing or forbidden. Repeated IDs
+// are looked up once, and more than the batch limit yields ErrBatchTooLarge.
+func (t *taskUseCase) GetTasksByIDs(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (*domain.BatchGetTasksResult, error) {
+	unique := uniqueIDs(ids)
+	if len(unique) > t.batchLimit {
+		return nil, domain.ErrBatchTooLarge
+	}
+
+	tasks, err := t.taskRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*domain.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	result := &domain.BatchGetTasksResult{
+		Tasks:     []*domain.Task{},
+		Missing:   []primitive.ObjectID{},
+		Forbidden: []primitive.ObjectID{},
+	}
+	for _, id := range unique {
+		task, ok := byID[id]
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, id)
+		case !t.canRead(task, userID, false):
+			result.Forbidden = append(result.Forbidden, id)
+		default:
+			t.markOverdue(task)
+			result.Tasks = append(result.Tasks, task)
+		}
+	}
+	return result, nil
+}
+
+// BulkTagTasks adds and removes tags across those of ids owned by userID.
+// IDs that do not exist or belong to someone else are skipped rather than
+// failing the request. The batch limit of GetTasksByIDs applies. Added tags
+// must pass the tag length limit, and a task the change would leave with more
+// tags than allowed is not updated. The count is checked against the tags
+// read here, so a concurrent edit of the same task can still go past it.
+func (t *taskUseCase) BulkTagTasks(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, add, remove []string) (*domain.BulkTagResult, error) {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, domain.ErrNoTagChanges
+	}
+	if err := t.validateTags(add); err != nil {
+		return nil, err
+	}
+	unique := uniqueIDs(ids)
+	if len(unique) > t.batchLimit {
+		return nil, domain.ErrBatchTooLarge
+	}
+
+	tasks, err := t.taskRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[primitive.ObjectID]*domain.Task, len(tasks))
+	for _, task := range tasks {
+		if task.UserID == userID {
+			owned[task.ID] = task
+		}
+	}
+
+	result := &domain.BulkTagResult{Skipped: []primitive.ObjectID{}, OverLimit: []primitive.ObjectID{}}
+	targets := make([]primitive.ObjectID, 0, len(owned))
+	retagged := make([]*domain.Task, 0, len(owned))
+	for _, id := range unique {
+		task := owned[id]
+		if task == nil {
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		after := *task
+		after.Tags = applyTags(task.Tags, add, remove)
+		if len(after.Tags) > t.maxTags {
+			result.OverLimit = append(result.OverLimit, id)
+			continue
+		}
+		targets = append(targets, id)
+		retagged = append(retagged, &after)
+	}
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	for i, id := range targets {
+		if err := t.recordRevision(ctx, owned[id], retagged[i], userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Updated, err = t.taskRepo.UpdateTags(ctx, userID, targets, add, remove); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyTags returns tags with the missing ones of add appended and those in
+// remove taken out, as UpdateTags stores them; a tag in both ends up removed
+func applyTags(tags, add, remove []string) []string {
+	present := make(map[string]bool, len(tags)+len(add))
+	combined := append([]string{}, tags...)
+	for _, tag := range tags {
+		present[tag] = true
+	}
+	for _, tag := range add {
+		if !present[tag] {
+			present[tag] = true
+			combined = append(combined, tag)
+		}
+	}
+
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+	result := []string{}
+	for _, tag := range combined {
+		if !removed[tag] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// uniqueIDs returns ids without repeats, keeping the first occurrence of each
+func uniqueIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	unique := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// ReorderTasks sets the manual order of userID's tasks to the order of ids.
+// IDs that do not exist or belong to someone else are skipped, as in
+// BulkTagTasks, and the remaining tasks are numbered from zero. Tasks left out
+// of ids keep their position. The batch limit of GetTasksByIDs applies.
+func (t *taskUseCase) ReorderTasks(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (*domain.ReorderResult, error) {
+	unique := uniqueIDs(ids)
+	if len(unique) > t.batchLimit {
+		return nil, domain.ErrBatchTooLarge
+	}
+
+	tasks, err := t.taskRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[primitive.ObjectID]bool, len(tasks))
+	for _, task := range tasks {
+		if task.UserID == userID {
+			owned[task.ID] = true
+		}
+	}
+
+	result := &domain.ReorderResult{Skipped: []primitive.ObjectID{}}
+	targets := make([]primitive.ObjectID, 0, len(owned))
+	for _, id := range unique {
+		if owned[id] {
+			targets = append(targets, id)
+		} else {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	if result.Updated, err = t.taskRepo.Reorder(ctx, userID, targets); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CloneTask creates a new task for userID from the user's task id, copying
+// its title, description and tags and applying overrides. The due date is
+// copied only while it is still ahead, so that tasks past due can be cloned.
+// Status and timestamps start fresh. A task owned by someone else is reported
+// as ErrTaskNotFound.
+func (t *taskUseCase) CloneTask(ctx context.Context, id, userID primitive.ObjectID, overrides domain.CloneTaskRequest) (*domain.Task, error) {
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+
+	source, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || source.UserID != userID {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	clone := &domain.Task{
+		Title:       source.Title,
+		Description: source.Description,
+		Tags:        append([]string(nil), source.Tags...),
+		Metadata:    copyMetadata(source.Metadata),
+		UserID:      userID,
+	}
+	if source.DueDate.After(t.clock.Now()) {
+		clone.DueDate = source.DueDate
+	}
+	if overrides.Title != nil {
+		clone.Title = *overrides.Title
+	}
+	if overrides.Description != nil {
+		clone.Description = *overrides.Description
+	}
+	if overrides.DueDate != nil {
+		clone.DueDate = *overrides.DueDate
+	}
+	if overrides.Tags != nil {
+		clone.Tags = overrides.Tags
+	}
+	return t.CreateTask(ctx, clone)
+}
+
+// copyMetadata returns a copy of metadata, so a clone does not share the
+// source's map
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
+
+// TransferTask hands task id over to toUserID. Only the owner, or an admin
+// when asAdmin is set, may transfer a task; others are answered like
+// checkOwner. A target user that does not exist is reported as
+// ErrUserNotFound, which requires WithUserRepository. Dependencies, shares
+// and the assignee are cleared because they were chosen by the previous
+// owner, and the title must be free among the new owner's tasks when unique
+// titles are enforced.
+func (t *taskUseCase) TransferTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, toUserID primitive.ObjectID) (*domain.Task, error) {
+	if t.userRepo == nil {
+		return nil, errors.New("user repository is not configured")
+	}
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+	if err := requireID(toUserID); err != nil {
+		return nil, err
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := t.checkOwner(task, callerID, asAdmin); err != nil {
+		return nil, err
+	}
+
+	target, err := t.userRepo.GetByID(ctx, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if task.UserID == toUserID {
+		t.markOverdue(task)
+		return task, nil
+	}
+	if err := t.checkQuota(ctx, toUserID); err != nil {
+		return nil, err
+	}
+
+	before := *task
+	task.UserID = toUserID
+	task.DependsOn = nil
+	task.SharedWith = nil
+	task.SharedEditors = nil
+	task.AssigneeID = nil
+	if t.autoAssign {
+		task.AssigneeID = &toUserID
+	}
+	if err := t.checkUniqueTitle(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := t.recordRevision(ctx, &before, task, callerID); err != nil {
+		return nil, err
+	}
+	if err := t.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	t.markOverdue(task)
+	return task, nil
+}
+
+// ShareTask gives userID access to task id, reading only or also writing as
+// permission says. Sharing again changes the permission. Only the owner and
+// admins may share a task.
+func (t *taskUseCase) ShareTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, userID primitive.ObjectID, permission string) (*domain.Task, error) {
+	if t.userRepo == nil {
+		return nil, errors.New("user repository is not configured")
+	}
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+	if err := requireID(userID); err != nil {
+		return nil, err
+	}
+	if permission == "" {
+		permission = domain.SharePermissionRead
+	}
+	if permission != domain.SharePermissionRead && permission != domain.SharePermissionWrite {
+		return nil, domain.ErrInvalidSharePermission
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := t.checkOwner(task, callerID, asAdmin); err != nil {
+		return nil, err
 	}
+	if task.UserID == userID {
+		return nil, domain.ErrShareWithOwner
+	}
+
+	user, err := t.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	before := *task
+	if !containsID(task.SharedWith, userID) {
+		task.SharedWith = append(task.SharedWith, userID)
+	}
+	task.SharedEditors = removeID(task.SharedEditors, userID)
+	if permission == domain.SharePermissionWrite {
+		task.SharedEditors = append(task.SharedEditors, userID)
+	}
+	if err := t.recordRevision(ctx, &before, task, callerID); err != nil {
+		return nil, err
+	}
+	if err := t.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	t.markOverdue(task)
+	return task, nil
 }
 
-func (t *taskUseCase) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
-	// Validate task
-	if task.Title == "" {
-		return nil, errors.New("task title is required")
+// UnshareTask takes away userID's access to task id. Removing a user the task
+// is not shared with succeeds without a change.
+func (t *taskUseCase) UnshareTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, userID primitive.ObjectID) (*domain.Task, error) {
+	if err := requireID(id); err != nil {
+		return nil, err
 	}
-	if task.DueDate.Before(time.Now()) {
-		return nil, errors.New("due date cannot be in the past")
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := t.checkOwner(task, callerID, asAdmin); err != nil {
+		return nil, err
+	}
+	if !containsID(task.SharedWith, userID) {
+		t.markOverdue(task)
+		return task, nil
 	}
 
-	// Set initial status
-	task.Status = domain.StatusPending
+	before := *task
+	task.SharedWith = removeID(task.SharedWith, userID)
+	task.SharedEditors = removeID(task.SharedEditors, userID)
+	if err := t.recordRevision(ctx, &before, task, callerID); err != nil {
+		return nil, err
+	}
+	if err := t.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	t.markOverdue(task)
+	return task, nil
+}
 
-	return t.taskRepo.Create(ctx, task)
+// ToggleChecklistItem flips the done state of the checklist item at index on
+// task id. Like UpdateTask, which can rewrite the whole checklist, it is open
+// to whoever checkWrite lets change the task.
+func (t *taskUseCase) ToggleChecklistItem(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, index int) (*domain.Task, error) {
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := t.checkWrite(task, callerID, asAdmin); err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(task.Checklist) {
+		return nil, domain.ErrChecklistIndexOutOfRange
+	}
+
+	// The checklist is copied so the revision keeps the state before the toggle
+	before := *task
+	done := !task.Checklist[index].Done
+	task.Checklist = append([]domain.ChecklistItem(nil), task.Checklist...)
+	task.Checklist[index].Done = done
+	if err := t.recordRevision(ctx, &before, task, callerID); err != nil {
+		return nil, err
+	}
+	if err := t.taskRepo.UpdateFields(ctx, id, map[string]interface{}{fmt.Sprintf("checklist.%d.done", index): done}); err != nil {
+		return nil, err
+	}
+	t.markOverdue(task)
+	return task, nil
+}
+
+func (t *taskUseCase) GetTasksByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) ([]*domain.Task, error) {
+	return t.listWithOverdue(t.taskRepo.GetByUserID(ctx, userID, t.resolveFilter(filter)))
+}
+
+// CountTasksByUserID returns how many of the user's tasks match filter
+// without fetching them
+func (t *taskUseCase) CountTasksByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) (int64, error) {
+	return t.taskRepo.CountByUserID(ctx, userID, t.resolveFilter(filter))
+}
+
+// PreviewTaskFilter reports how many of the user's tasks filter matches and
+// returns the first few of them, so a bulk operation can be checked before
+// it runs. Nothing is changed.
+func (t *taskUseCase) PreviewTaskFilter(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) (*domain.FilterPreview, error) {
+	filter = t.resolveFilter(filter)
+	count, err := t.taskRepo.CountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Pagination = domain.Pagination{Page: 1, PageSize: previewSampleSize}
+	sample, err := t.listWithOverdue(t.taskRepo.GetByUserID(ctx, userID, filter))
+	if err != nil {
+		return nil, err
+	}
+	return &domain.FilterPreview{Count: count, Sample: sample}, nil
+}
+
+// resolveFilter turns a DueOn day into due date bounds in the configured
+// timezone
+func (t *taskUseCase) resolveFilter(filter domain.TaskFilter) domain.TaskFilter {
+	if filter.DueOn != nil {
+		from, to := dayRange(*filter.DueOn, t.location)
+		filter.DueFrom, filter.DueTo = &from, &to
+	}
+	return filter
+}
+
+// SearchTasks returns the user's tasks matching the search terms in query,
+// most relevant first
+func (t *taskUseCase) SearchTasks(ctx context.Context, userID primitive.ObjectID, query string, page domain.Pagination) ([]*domain.Task, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrEmptySearchQuery
+	}
+	return t.listWithOverdue(t.taskRepo.Search(ctx, userID, query, page))
+}
+
+// GetUpcomingTasks returns the user's next limit unfinished tasks by due date
+func (t *taskUseCase) GetUpcomingTasks(ctx context.Context, userID primitive.ObjectID, limit int) ([]*domain.Task, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	return t.listWithOverdue(t.taskRepo.GetUpcoming(ctx, userID, int64(limit)))
+}
+
+// GetDueReminders returns the user's next limit unfinished tasks whose
+// reminder time has arrived, earliest reminder first
+func (t *taskUseCase) GetDueReminders(ctx context.Context, userID primitive.ObjectID, limit int) ([]*domain.Task, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	return t.listWithOverdue(t.taskRepo.GetRemindersDue(ctx, userID, t.clock.Now(), int64(limit)))
+}
+
+// GetAgenda sorts the user's unfinished tasks with a due date into overdue,
+// today, the rest of this week and later, using the configured timezone
+func (t *taskUseCase) GetAgenda(ctx context.Context, userID primitive.ObjectID) (*domain.Agenda, error) {
+	tasks, err := t.listWithOverdue(t.taskRepo.GetUpcoming(ctx, userID, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	now := t.clock.Now().In(t.location)
+	_, endOfToday := dayRange(now, t.location)
+	daysToSunday := (7 - int(now.Weekday())) % 7
+	_, endOfWeek := dayRange(now.AddDate(0, 0, daysToSunday), t.location)
+
+	agenda := &domain.Agenda{
+		Overdue:  []*domain.Task{},
+		Today:    []*domain.Task{},
+		ThisWeek: []*domain.Task{},
+		Later:    []*domain.Task{},
+	}
+	for _, task := range tasks {
+		switch {
+		case task.DueDate.Before(now):
+			agenda.Overdue = append(agenda.Overdue, task)
+		case !task.DueDate.After(endOfToday):
+			agenda.Today = append(agenda.Today, task)
+		case !task.DueDate.After(endOfWeek):
+			agenda.ThisWeek = append(agenda.ThisWeek, task)
+		default:
+			agenda.Later = append(agenda.Later, task)
+		}
+	}
+	return agenda, nil
+}
+
+// GetTagsByUserID returns the distinct tags on the user's tasks, sorted
+func (t *taskUseCase) GetTagsByUserID(ctx context.Context, userID primitive.ObjectID) ([]string, error) {
+	tags, err := t.taskRepo.DistinctTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// GetUnassignedTasks lists the tasks of every user that nobody is assigned
+// to, for triage
+func (t *taskUseCase) GetUnassignedTasks(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
+	return t.listWithOverdue(t.taskRepo.GetUnassigned(ctx, page))
+}
+
+// GetUsersWithOverdueTasks lists the users who own at least one overdue,
+// unfinished task, most overdue first. Counts whose owner no longer exists or
+// has been soft-deleted are dropped. It requires WithUserRepository.
+func (t *taskUseCase) GetUsersWithOverdueTasks(ctx context.Context) ([]*domain.UserOverdue, error) {
+	if t.userRepo == nil {
+		return nil, errors.New("user repository is not configured")
+	}
+
+	counts, err := t.taskRepo.CountOverdueByUser(ctx, t.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return []*domain.UserOverdue{}, nil
+	}
+
+	userIDs := make([]primitive.ObjectID, len(counts))
+	for i, count := range counts {
+		userIDs[i] = count.UserID
+	}
+	users, err := t.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*domain.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	result := make([]*domain.UserOverdue, 0, len(counts))
+	for _, count := range counts {
+		user, ok := byID[count.UserID]
+		if !ok {
+			continue
+		}
+		result = append(result, &domain.UserOverdue{
+			TaskOwner:    domain.TaskOwner{ID: user.ID, Name: user.Name, Email: user.Email},
+			OverdueTasks: count.Count,
+		})
+	}
+	return result, nil
+}
+
+// GetTasksSharedWithMe lists the tasks other users have shared with userID.
+// The user's own tasks are never included.
+func (t *taskUseCase) GetTasksSharedWithMe(ctx context.Context, userID primitive.ObjectID, page domain.Pagination) ([]*domain.Task, error) {
+	return t.listWithOverdue(t.taskRepo.GetSharedWith(ctx, userID, page))
+}
+
+// GetSharedTasks lists every user's tasks for shared mode, except those of
+// deleted accounts. It returns ErrSharedTasksDisabled when shared mode is
+// off.
+func (t *taskUseCase) GetSharedTasks(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
+	if !t.sharedTasks {
+		return nil, domain.ErrSharedTasksDisabled
+	}
+	return t.listWithOverdue(t.taskRepo.GetAllVisible(ctx, page))
+}
+
+// GetActivityByUserID returns the user's activity timeline, newest first.
+// Events are derived from the user's tasks: each task contributes its
+// creation and, once completed, its completion. Tasks completed before
+// completion times were recorded use their last update instead, and sort
+// after those that have one.
+//
+// A page of events can only come from the newest creations and completions
+// up to the end of that page, so only those are loaded.
+func (t *taskUseCase) GetActivityByUserID(ctx context.Context, userID primitive.ObjectID, page domain.Pagination) ([]domain.ActivityEvent, error) {
+	window := domain.Pagination{Page: 1, PageSize: int(page.Skip() + page.Limit())}
+	created, err := t.taskRepo.GetByUserID(ctx, userID, domain.TaskFilter{Sort: domain.TaskSortNewest, Pagination: window})
+	if err != nil {
+		return nil, err
+	}
+	completed, err := t.taskRepo.GetCompleted(ctx, userID, nil, nil, window)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.ActivityEvent, 0, len(created)+len(completed))
+	for _, task := range created {
+		events = append(events, domain.ActivityEvent{Type: domain.ActivityTaskCreated, TaskID: task.ID, TaskTitle: task.Title, At: task.CreatedAt})
+	}
+	for _, task := range completed {
+		at := task.UpdatedAt
+		if task.CompletedAt != nil {
+			at = *task.CompletedAt
+		}
+		events = append(events, domain.ActivityEvent{Type: domain.ActivityTaskCompleted, TaskID: task.ID, TaskTitle: task.Title, At: at})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.After(events[j].At)
+	})
+
+	start := page.Skip()
+	if start > int64(len(events)) {
+		start = int64(len(events))
+	}
+	end := int64(len(events))
+	if page.Limit() > 0 && start+page.Limit() < end {
+		end = start + page.Limit()
+	}
+	return events[start:end], nil
+}
+
+// GetCompletedTasks returns the user's tasks completed within the window
+// filter selects, most recently completed first. A window that starts after
+// it ends is reported as ErrInvalidDateRange.
+func (t *taskUseCase) GetCompletedTasks(ctx context.Context, userID primitive.ObjectID, filter domain.CompletedFilter) ([]*domain.Task, error) {
+	if filter.FromDay != nil {
+		from, _ := dayRange(*filter.FromDay, t.location)
+		filter.From = &from
+	}
+	if filter.ToDay != nil {
+		_, to := dayRange(*filter.ToDay, t.location)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	return t.listWithOverdue(t.taskRepo.GetCompleted(ctx, userID, filter.From, filter.To, filter.Pagination))
 }
 
-func (t *taskUseCase) GetTaskByID(ctx context.Context, id primitive.ObjectID) (*domain.Task, error) {
-	return t.taskRepo.GetByID(ctx, id)
+// GetTaskStatsByUserID returns the status breakdown of a user's tasks. It
+// returns ErrUserNotFound when the user does not exist, which requires
+// WithUserRepository.
+func (t *taskUseCase) GetTaskStatsByUserID(ctx context.Context, userID primitive.ObjectID) (*domain.TaskStats, error) {
+	if t.userRepo == nil {
+		return nil, errors.New("user repository is not configured")
+	}
+
+	user, err := t.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return t.taskRepo.StatsByUserID(ctx, userID, t.clock.Now())
+}
+
+// GetTimeReport summarizes estimated against actual minutes over the user's
+// tasks, per status
+func (t *taskUseCase) GetTimeReport(ctx context.Context, userID primitive.ObjectID) (*domain.TimeReport, error) {
+	return t.taskRepo.TimeReportByUserID(ctx, userID)
 }
 
-func (t *taskUseCase) GetTasksByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Task, error) {
-	return t.taskRepo.GetByUserID(ctx, userID)
+// dayRange returns the first and last instant of day's calendar date in loc.
+// Only the year, month and day of day are used.
+func dayRange(day time.Time, loc *time.Location) (time.Time, time.Time) {
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1).Add(-time.Nanosecond)
 }
 
-func (t *taskUseCase) GetAllTasks(ctx context.Context) ([]*domain.Task, error) {
+func (t *taskUseCase) GetAllTasks(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
 	// Fetch all tasks from the repository
-	tasks, err := t.taskRepo.GetAll(ctx)
+	return t.listWithOverdue(t.taskRepo.GetAll(ctx, page))
+}
+
+// GetAllTasksWithOwners returns every task with its owner inlined. Owners
+// are loaded in one batched query rather than once per task.
+func (t *taskUseCase) GetAllTasksWithOwners(ctx context.Context, page domain.Pagination) ([]*domain.TaskWithOwner, error) {
+	if t.userRepo == nil {
+		return nil, errors.New("user repository is not configured")
+	}
+
+	tasks, err := t.listWithOverdue(t.taskRepo.GetAll(ctx, page))
 	if err != nil {
 		return nil, err
 	}
-	return tasks, nil
+
+	seen := make(map[primitive.ObjectID]bool)
+	var userIDs []primitive.ObjectID
+	for _, task := range tasks {
+		if !seen[task.UserID] {
+			seen[task.UserID] = true
+			userIDs = append(userIDs, task.UserID)
+		}
+	}
+
+	users, err := t.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[primitive.ObjectID]*domain.TaskOwner, len(users))
+	for _, user := range users {
+		owners[user.ID] = &domain.TaskOwner{ID: user.ID, Name: user.Name, Email: user.Email}
+	}
+
+	result := make([]*domain.TaskWithOwner, len(tasks))
+	for i, task := range tasks {
+		result[i] = &domain.TaskWithOwner{Task: *task, User: owners[task.UserID]}
+	}
+	return result, nil
 }
 
-func (t *taskUseCase) UpdateTask(ctx context.Context, task *domain.Task) error {
+// UpdateTask replaces task if callerID owns it or is an admin. The owner is
+// kept from the stored task.
+func (t *taskUseCase) UpdateTask(ctx context.Context, task *domain.Task, callerID primitive.ObjectID, asAdmin bool) error {
+	if err := requireID(task.ID); err != nil {
+		return err
+	}
+
 	// Validate task
 	if task.Title == "" {
 		return errors.New("task title is required")
 	}
-	if task.DueDate.Before(time.Now()) {
+	if !task.DueDate.IsZero() && task.DueDate.Before(t.clock.Now()) {
 		return errors.New("due date cannot be in the past")
 	}
+	if err := t.validateReminder(task); err != nil {
+		return err
+	}
+	if task.EstimatedMinutes < 0 || task.ActualMinutes < 0 {
+		return domain.ErrNegativeMinutes
+	}
+	if err := t.validateTags(task.Tags); err != nil {
+		return err
+	}
+	if err := t.validateMetadata(task.Metadata); err != nil {
+		return err
+	}
 
 	// Validate status transition
 	existingTask, err := t.taskRepo.GetByID(ctx, task.ID)
 	if err != nil {
 		return err
 	}
+	if existingTask == nil {
+		return domain.ErrTaskNotFound
+	}
+	if err := t.checkWrite(existingTask, callerID, asAdmin); err != nil {
+		return err
+	}
+	task.UserID = existingTask.UserID
+	task.CreatedAt = existingTask.CreatedAt
+	task.SharedWith = existingTask.SharedWith
+	task.SharedEditors = existingTask.SharedEditors
+	// The position is only changed through ReorderTasks
+	task.Order = existingTask.Order
+	task.CompletedAt = completedAt(existingTask, task.Status, t.clock.Now())
 
 	// Only allow status transitions from pending to in_progress to completed
 	if existingTask.Status == domain.StatusCompleted && task.Status != domain.StatusCompleted {
 		return errors.New("cannot change status of completed task")
 	}
+	if t.noRegression && existingTask.Status == domain.StatusInProgress && task.Status == domain.StatusPending {
+		return domain.ErrStatusRegression
+	}
+
+	dependencies, err := t.checkDependencies(ctx, task, existingTask.UserID, existingTask.DependsOn)
+	if err != nil {
+		return err
+	}
+	if err := checkBlocked(task.Status, dependencies); err != nil {
+		return err
+	}
+
+	if err := t.checkUniqueTitle(ctx, task); err != nil {
+		return err
+	}
 
+	if err := t.recordRevision(ctx, existingTask, task, callerID); err != nil {
+		return err
+	}
 	return t.taskRepo.Update(ctx, task)
 }
 
-func (t *taskUseCase) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
-	return t.taskRepo.Delete(ctx, id)
+// completedAt returns the completion time a task moving from existing to
+// status should carry: kept while it stays completed, now when it becomes
+// completed and none otherwise
+func completedAt(existing *domain.Task, status string, now time.Time) *time.Time {
+	if status != domain.StatusCompleted {
+		return nil
+	}
+	if existing.Status == domain.StatusCompleted {
+		return existing.CompletedAt
+	}
+	return &now
+}
+
+// untrackedTaskFields are left out of revisions: they are managed by the
+// system rather than by the caller's update, or never stored. The order is a
+// display preference that ReorderTasks rewrites for many tasks at once, so it
+// is not recorded either.
+var untrackedTaskFields = map[string]bool{
+	"id":           true,
+	"order":        true,
+	"created_at":   true,
+	"updated_at":   true,
+	"completed_at": true,
+	"score":        true,
+	"is_overdue":   true,
+}
+
+// recordRevision writes the change from before to after made by actorID to
+// the task history. Like the audit log it is written before the task, so no
+// saved update goes unrecorded. Updates that change nothing are skipped.
+func (t *taskUseCase) recordRevision(ctx context.Context, before, after *domain.Task, actorID primitive.ObjectID) error {
+	if t.historyRepo == nil {
+		return nil
+	}
+	changes := changedFields(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	snapshot := *after
+	return t.historyRepo.Create(ctx, &domain.TaskRevision{
+		TaskID:  before.ID,
+		ActorID: actorID,
+		Changes: changes,
+		Before:  before,
+		After:   &snapshot,
+	})
+}
+
+// changedFields lists by JSON name the fields whose values differ between
+// before and after. Times are compared as instants, and an empty list or map
+// equals a missing one.
+func changedFields(before, after *domain.Task) []string {
+	b, a := reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem()
+	changes := []string{}
+	for i := 0; i < b.NumField(); i++ {
+		name := strings.Split(b.Type().Field(i).Tag.Get("json"), ",")[0]
+		if untrackedTaskFields[name] {
+			continue
+		}
+		if !sameValue(b.Field(i).Interface(), a.Field(i).Interface()) {
+			changes = append(changes, name)
+		}
+	}
+	return changes
+}
+
+// sameValue compares two values of one Task field
+func sameValue(x, y interface{}) bool {
+	switch x := x.(type) {
+	case time.Time:
+		return x.Equal(y.(time.Time))
+	case *time.Time:
+		y := y.(*time.Time)
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Equal(*y)
+	}
+	vx, vy := reflect.ValueOf(x), reflect.ValueOf(y)
+	if (vx.Kind() == reflect.Slice || vx.Kind() == reflect.Map) && vx.Len() == 0 && vy.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// GetTaskHistory returns the revisions of task id, oldest first, to its
+// owner or an admin. It requires WithTaskHistoryRepository.
+func (t *taskUseCase) GetTaskHistory(ctx context.Context, id, callerID primitive.ObjectID, asAdmin bool, page domain.Pagination) ([]*domain.TaskRevision, error) {
+	if t.historyRepo == nil {
+		return nil, errors.New("task history repository is not configured")
+	}
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := t.checkOwner(task, callerID, asAdmin); err != nil {
+		return nil, err
+	}
+	return t.historyRepo.GetByTaskID(ctx, id, page)
+}
+
+// checkDependencies verifies that task's dependencies exist, belong to
+// ownerID and do not lead back to task, and returns them. Dependencies listed
+// in stored, the ones the task already had, may since have been deleted or
+// transferred; those are dropped from task.DependsOn instead of failing the
+// update, so only newly added dependencies are rejected.
+func (t *taskUseCase) checkDependencies(ctx context.Context, task *domain.Task, ownerID primitive.ObjectID, stored []primitive.ObjectID) ([]*domain.Task, error) {
+	if len(task.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(task.DependsOn))
+	var ids []primitive.ObjectID
+	for _, id := range task.DependsOn {
+		if !task.ID.IsZero() && id == task.ID {
+			return nil, domain.ErrSelfDependency
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := t.taskRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*domain.Task, len(found))
+	for _, dependency := range found {
+		if dependency.UserID == ownerID {
+			byID[dependency.ID] = dependency
+		}
+	}
+
+	stale := make(map[primitive.ObjectID]bool)
+	for _, id := range stored {
+		stale[id] = true
+	}
+	var dependencies []*domain.Task
+	kept := make([]primitive.ObjectID, 0, len(task.DependsOn))
+	for _, id := range task.DependsOn {
+		if byID[id] == nil {
+			if !stale[id] {
+				return nil, domain.ErrInvalidDependency
+			}
+			continue
+		}
+		kept = append(kept, id)
+	}
+	for _, id := range ids {
+		if dependency := byID[id]; dependency != nil {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	task.DependsOn = kept
+
+	// A new task has no ID yet, so nothing can depend on it
+	if task.ID.IsZero() {
+		return dependencies, nil
+	}
+
+	// Walk the dependency graph one level at a time looking for task.ID
+	visited := make(map[primitive.ObjectID]bool)
+	frontier := dependencies
+	for len(frontier) > 0 {
+		var next []primitive.ObjectID
+		for _, node := range frontier {
+			for _, id := range node.DependsOn {
+				if id == task.ID {
+					return nil, domain.ErrDependencyCycle
+				}
+				if !visited[id] {
+					visited[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		if frontier, err = t.taskRepo.GetByIDs(ctx, next); err != nil {
+			return nil, err
+		}
+	}
+
+	return dependencies, nil
+}
+
+// checkBlocked returns a TaskBlockedError listing the unfinished
+// dependencies when status is completed
+func checkBlocked(status string, dependencies []*domain.Task) error {
+	if status != domain.StatusCompleted {
+		return nil
+	}
+	var blocking []primitive.ObjectID
+	for _, dependency := range dependencies {
+		if dependency.Status != domain.StatusCompleted {
+			blocking = append(blocking, dependency.ID)
+		}
+	}
+	if len(blocking) > 0 {
+		return &domain.TaskBlockedError{BlockingTaskIDs: blocking}
+	}
+	return nil
+}
+
+// DeleteTask removes task id if callerID owns it or is an admin. It is
+// idempotent by default: deleting a task that is already gone succeeds, so
+// clients can safely retry. Tasks in progress are only deleted when force is
+// set.
+func (t *taskUseCase) DeleteTask(ctx context.Context, id, callerID primitive.ObjectID, asAdmin, force bool) error {
+	if err := requireID(id); err != nil {
+		return err
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if task != nil {
+		if err := t.checkOwner(task, callerID, asAdmin); err != nil {
+			return err
+		}
+		if !force && task.Status == domain.StatusInProgress {
+			return domain.ErrTaskInProgress
+		}
+	}
+
+	err = t.taskRepo.Delete(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) && !t.strictDelete {
+		return nil
+	}
+	return err
+}
+
+// ForceCompleteTask marks task id completed on behalf of adminID whatever its
+// owner or current status. The override is written to the audit log before
+// the task is changed.
+func (t *taskUseCase) ForceCompleteTask(ctx context.Context, id, adminID primitive.ObjectID) (*domain.Task, error) {
+	if t.auditRepo == nil {
+		return nil, errors.New("audit repository is not configured")
+	}
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	entry := &domain.AuditEntry{Action: domain.AuditTaskForceCompleted, ActorID: adminID, TargetID: id}
+	if err := t.auditRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	before := *task
+	task.CompletedAt = completedAt(task, domain.StatusCompleted, t.clock.Now())
+	task.Status = domain.StatusCompleted
+	if err := t.recordRevision(ctx, &before, task, adminID); err != nil {
+		return nil, err
+	}
+	if err := t.taskRepo.UpdateFields(ctx, id, map[string]interface{}{
+		"status":       domain.StatusCompleted,
+		"completed_at": task.CompletedAt,
+	}); err != nil {
+		return nil, err
+	}
+	t.markOverdue(task)
+	return task, nil
+}
+
+// DeleteCompletedTasks removes every completed task owned by userID and
+// returns how many were deleted
+func (t *taskUseCase) DeleteCompletedTasks(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return t.taskRepo.DeleteCompletedByUserID(ctx, userID)
 }
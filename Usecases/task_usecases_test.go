@@ -3,10 +3,12 @@ package Usecases
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	domain "Task-Management/Domain"
+	infrastructure "Task-Management/Infrastructure"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -18,10 +20,10 @@ import (
 type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) (*domain.Task, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Task, error)
-	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Task, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) ([]*domain.Task, error)
 	Update(ctx context.Context, task *domain.Task) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	GetAll(ctx context.Context) ([]*domain.Task, error)
+	GetAll(ctx context.Context, page domain.Pagination) ([]*domain.Task, error)
 }
 
 // TaskUseCase represents the use case for managing tasks
@@ -52,13 +54,13 @@ func (uc *TaskUseCase) GetTaskByID(ctx context.Context, id primitive.ObjectID) (
 }
 
 // GetTasksByUserID retrieves tasks by user ID
-func (uc *TaskUseCase) GetTasksByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Task, error) {
-	return uc.repo.GetByUserID(ctx, userID)
+func (uc *TaskUseCase) GetTasksByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) ([]*domain.Task, error) {
+	return uc.repo.GetByUserID(ctx, userID, filter)
 }
 
 // GetAllTasks retrieves all tasks
-func (uc *TaskUseCase) GetAllTasks(ctx context.Context) ([]*domain.Task, error) {
-	return uc.repo.GetAll(ctx)
+func (uc *TaskUseCase) GetAllTasks(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
+	return uc.repo.GetAll(ctx, page)
 }
 
 // UpdateTask updates an existing task
@@ -73,7 +75,7 @@ func (uc *TaskUseCase) UpdateTask(ctx context.Context, task *domain.Task) error
 }
 
 // DeleteTask deletes a task by its ID
-func (uc *TaskUseCase) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+func (uc *TaskUseCase) DeleteTask(ctx context.Context, id primitive.ObjectID, force bool) error {
 	return uc.repo.Delete(ctx, id)
 }
 
@@ -92,13 +94,137 @@ func (m *MockTaskRepository) GetByID(ctx context.Context, id primitive.ObjectID)
 	return args.Get(0).(*domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Task, error) {
+func (m *MockTaskRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Task, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) ForEachByUserID(ctx context.Context, userID primitive.ObjectID, fn func(*domain.Task) error) error {
+	args := m.Called(ctx, userID, fn)
+	if tasks, ok := args.Get(0).([]*domain.Task); ok {
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTaskRepository) LatestCreatedAt(ctx context.Context, userID primitive.ObjectID) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetCompleted(ctx context.Context, userID primitive.ObjectID, from, to *time.Time, page domain.Pagination) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, from, to, page)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetSharedWith(ctx context.Context, userID primitive.ObjectID, page domain.Pagination) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) TitleTaken(ctx context.Context, userID primitive.ObjectID, title string, excludeID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, userID, title, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetUpcoming(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetRemindersDue(ctx context.Context, userID primitive.ObjectID, now time.Time, limit int64) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, now, limit)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) UpdateFields(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) error {
+	args := m.Called(ctx, id, fields)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) UpdateTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, add, remove []string) (int64, error) {
+	args := m.Called(ctx, userID, ids, add, remove)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) Reorder(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) DeleteByUserIDs(ctx context.Context, userIDs []primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) SetOwnerDeleted(ctx context.Context, userID primitive.ObjectID, deleted bool) error {
+	args := m.Called(ctx, userID, deleted)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) Search(ctx context.Context, userID primitive.ObjectID, query string, page domain.Pagination) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, query, page)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) DistinctTags(ctx context.Context, userID primitive.ObjectID) ([]string, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountByUserID(ctx context.Context, userID primitive.ObjectID, filter domain.TaskFilter) (int64, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) StatsByUserID(ctx context.Context, userID primitive.ObjectID, now time.Time) (*domain.TaskStats, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TaskStats), args.Error(1)
+}
+
+func (m *MockTaskRepository) TimeReportByUserID(ctx context.Context, userID primitive.ObjectID) (*domain.TimeReport, error) {
 	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TimeReport), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountOverdueByUser(ctx context.Context, now time.Time) ([]domain.OverdueCount, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OverdueCount), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetUnassigned(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) GetAll(ctx context.Context) ([]*domain.Task, error) {
-	args := m.Called(ctx)
+func (m *MockTaskRepository) GetAll(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetAllVisible(ctx context.Context, page domain.Pagination) ([]*domain.Task, error) {
+	args := m.Called(ctx, page)
 	return args.Get(0).([]*domain.Task), args.Error(1)
 }
 
@@ -112,6 +238,26 @@ func (m *MockTaskRepository) Delete(ctx context.Context, id primitive.ObjectID)
 	return args.Error(0)
 }
 
+func (m *MockTaskRepository) DeleteCompletedByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockTaskHistoryRepository is a mock implementation of the TaskHistoryRepository interface
+type MockTaskHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaskHistoryRepository) Create(ctx context.Context, revision *domain.TaskRevision) error {
+	args := m.Called(ctx, revision)
+	return args.Error(0)
+}
+
+func (m *MockTaskHistoryRepository) GetByTaskID(ctx context.Context, taskID primitive.ObjectID, page domain.Pagination) ([]*domain.TaskRevision, error) {
+	args := m.Called(ctx, taskID, page)
+	return args.Get(0).([]*domain.TaskRevision), args.Error(1)
+}
+
 // TaskUseCaseTestSuite groups all task use case-related tests
 type TaskUseCaseTestSuite struct {
 	suite.Suite
@@ -170,9 +316,9 @@ func (suite *TaskUseCaseTestSuite) TestGetTasksByUserID_Success() {
 	tasks := []*domain.Task{
 		{ID: primitive.NewObjectID(), Title: "Task 1", UserID: userID},
 	}
-	suite.mockRepo.On("GetByUserID", mock.Anything, userID).Return(tasks, nil)
+	suite.mockRepo.On("GetByUserID", mock.Anything, userID, domain.TaskFilter{}).Return(tasks, nil)
 
-	results, err := suite.useCase.GetTasksByUserID(context.Background(), userID)
+	results, err := suite.useCase.GetTasksByUserID(context.Background(), userID, domain.TaskFilter{})
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, 1)
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -184,9 +330,9 @@ func (suite *TaskUseCaseTestSuite) TestGetAllTasks_Success() {
 		{ID: primitive.NewObjectID(), Title: "Task 1"},
 		{ID: primitive.NewObjectID(), Title: "Task 2"},
 	}
-	suite.mockRepo.On("GetAll", mock.Anything).Return(tasks, nil)
+	suite.mockRepo.On("GetAll", mock.Anything, domain.Pagination{}).Return(tasks, nil)
 
-	results, err := suite.useCase.GetAllTasks(context.Background())
+	results, err := suite.useCase.GetAllTasks(context.Background(), domain.Pagination{})
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, 2)
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -206,9 +352,9 @@ func (suite *TaskUseCaseTestSuite) TestGetTaskByID_NotFound() {
 // TestGetTasksByUserID_Empty tests fetching tasks by user ID when no tasks exist
 func (suite *TaskUseCaseTestSuite) TestGetTasksByUserID_Empty() {
 	userID := primitive.NewObjectID()
-	suite.mockRepo.On("GetByUserID", mock.Anything, userID).Return([]*domain.Task{}, nil)
+	suite.mockRepo.On("GetByUserID", mock.Anything, userID, domain.TaskFilter{}).Return([]*domain.Task{}, nil)
 
-	results, err := suite.useCase.GetTasksByUserID(context.Background(), userID)
+	results, err := suite.useCase.GetTasksByUserID(context.Background(), userID, domain.TaskFilter{})
 	assert.NoError(suite.T(), err)
 	assert.Empty(suite.T(), results)
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -220,10 +366,12 @@ func TestUpdateTask(t *testing.T) {
 	taskUseCase := NewTaskUseCase(mockTaskRepo)
 
 	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
 	existingTask := &domain.Task{
 		ID:      taskID,
 		Title:   "Existing Task",
 		Status:  domain.StatusPending,
+		UserID:  ownerID,
 		DueDate: time.Now().Add(24 * time.Hour), // Ensure due date is in the future
 	}
 	updatedTask := &domain.Task{
@@ -240,10 +388,11 @@ func TestUpdateTask(t *testing.T) {
 	mockTaskRepo.On("Update", mock.Anything, updatedTask).Return(nil)
 
 	// Call UpdateTask
-	err := taskUseCase.UpdateTask(context.Background(), updatedTask)
+	err := taskUseCase.UpdateTask(context.Background(), updatedTask, ownerID, false)
 
 	// Assertions
 	assert.NoError(t, err)
+	assert.Equal(t, ownerID, updatedTask.UserID)
 	mockTaskRepo.AssertExpectations(t)
 }
 
@@ -263,7 +412,7 @@ func (suite *TaskUseCaseTestSuite) TestDeleteTask_Success() {
 	taskID := primitive.NewObjectID()
 	suite.mockRepo.On("Delete", mock.Anything, taskID).Return(nil)
 
-	err := suite.useCase.DeleteTask(context.Background(), taskID)
+	err := suite.useCase.DeleteTask(context.Background(), taskID, false)
 	assert.NoError(suite.T(), err)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
@@ -299,6 +448,2306 @@ func (suite *TaskUseCaseTestSuite) TestUpdateTask_RepositoryError() {
 	assert.EqualError(suite.T(), err, "repository error")
 }
 
+// TestCreateTask_DueDateBoundary tests due date validation against a fake clock
+func TestCreateTask_DueDateBoundary(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	fakeClock := infrastructure.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(fakeClock))
+
+	dueDate := fakeClock.Now().Add(time.Minute)
+	mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{Title: "Test Task"}, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate})
+	assert.NoError(t, err)
+
+	fakeClock.Set(dueDate)
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate})
+	assert.NoError(t, err, "a due date equal to now is not in the past")
+
+	fakeClock.Advance(time.Nanosecond)
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate})
+	assert.EqualError(t, err, "due date cannot be in the past")
+	mockTaskRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+// TestTask_WithoutDueDate tests that a task without a due date can be created
+// and updated, so has_due_date=false has tasks to match
+func TestTask_WithoutDueDate(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{Title: "Someday"}, nil)
+	_, err := taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Someday", UserID: ownerID})
+	assert.NoError(t, err)
+
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Someday", Status: domain.StatusPending}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(&domain.Task{ID: task.ID, UserID: ownerID, Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil)
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), task, ownerID, false))
+}
+
+// TestCreateTask_DefaultStatus tests that new tasks get the configured default status
+func TestCreateTask_DefaultStatus(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithDefaultStatus(domain.StatusInProgress))
+
+	mockTaskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == domain.StatusInProgress
+	})).Return(&domain.Task{Title: "Test Task", Status: domain.StatusInProgress}, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestCreateTask_StatusOverride tests that a valid client status wins over the default and an unknown one is rejected
+func TestCreateTask_StatusOverride(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithDefaultStatus(domain.StatusInProgress))
+
+	mockTaskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == domain.StatusCompleted
+	})).Return(&domain.Task{Title: "Test Task", Status: domain.StatusCompleted}, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", Status: domain.StatusCompleted, DueDate: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", Status: "blocked", DueDate: time.Now().Add(time.Hour)})
+	assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+	mockTaskRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// TestCreateTask_CompletedBlockedByDependencies tests that a task cannot be
+// created completed, by the client or the default status, while a dependency
+// is unfinished
+func TestCreateTask_CompletedBlockedByDependencies(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithDefaultStatus(domain.StatusCompleted))
+
+	userID := primitive.NewObjectID()
+	openID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{openID}).Return([]*domain.Task{
+		{ID: openID, UserID: userID, Status: domain.StatusPending},
+	}, nil)
+
+	for _, status := range []string{"", domain.StatusCompleted} {
+		_, err := taskUseCase.CreateTask(context.Background(), &domain.Task{
+			Title:     "Release",
+			UserID:    userID,
+			Status:    status,
+			DueDate:   time.Now().Add(time.Hour),
+			DependsOn: []primitive.ObjectID{openID},
+		})
+
+		var blocked *domain.TaskBlockedError
+		assert.ErrorAs(t, err, &blocked)
+		assert.Equal(t, []primitive.ObjectID{openID}, blocked.BlockingTaskIDs)
+	}
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_DueDateBoundary tests that updates are validated against the fake clock
+func TestUpdateTask_DueDateBoundary(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	fakeClock := infrastructure.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(fakeClock))
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	task := &domain.Task{ID: taskID, Title: "Test Task", Status: domain.StatusPending, DueDate: fakeClock.Now().Add(time.Hour)}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil)
+
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), task, ownerID, false))
+
+	fakeClock.Advance(time.Hour + time.Second)
+	assert.EqualError(t, taskUseCase.UpdateTask(context.Background(), task, ownerID, false), "due date cannot be in the past")
+	mockTaskRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+// TestCreateTask_ReminderValidation tests that reminders must be in the future and before the due date
+func TestCreateTask_ReminderValidation(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	fakeClock := infrastructure.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(fakeClock))
+
+	dueDate := fakeClock.Now().Add(24 * time.Hour)
+	remindAt := func(d time.Duration) *time.Time {
+		at := fakeClock.Now().Add(d)
+		return &at
+	}
+	mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{Title: "Test Task"}, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate, RemindAt: remindAt(time.Hour)})
+	assert.NoError(t, err)
+
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate, RemindAt: remindAt(-time.Minute)})
+	assert.ErrorIs(t, err, domain.ErrReminderInPast)
+
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate, RemindAt: remindAt(0)})
+	assert.ErrorIs(t, err, domain.ErrReminderInPast, "a reminder at the current instant has already passed")
+
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate, RemindAt: remindAt(25 * time.Hour)})
+	assert.ErrorIs(t, err, domain.ErrReminderAfterDueDate)
+
+	_, err = taskUseCase.CreateTask(context.Background(), &domain.Task{Title: "Test Task", DueDate: dueDate, RemindAt: &dueDate})
+	assert.ErrorIs(t, err, domain.ErrReminderAfterDueDate, "a reminder at the due date is not before it")
+	mockTaskRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// TestUpdateTask_ReminderValidation tests that updates reject a reminder after the due date or in the past
+func TestUpdateTask_ReminderValidation(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	fakeClock := infrastructure.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(fakeClock))
+
+	taskID := primitive.NewObjectID()
+	remindAt := fakeClock.Now().Add(2 * time.Hour)
+	task := &domain.Task{ID: taskID, Title: "Test Task", Status: domain.StatusPending, DueDate: fakeClock.Now().Add(time.Hour), RemindAt: &remindAt}
+
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), task, primitive.NewObjectID(), false), domain.ErrReminderAfterDueDate)
+
+	task.DueDate = fakeClock.Now().Add(3 * time.Hour)
+	fakeClock.Advance(2*time.Hour + time.Second)
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), task, primitive.NewObjectID(), false), domain.ErrReminderInPast)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestGetTasksByUserID_DueOn tests that a due day is resolved in the configured timezone
+func TestGetTasksByUserID_DueOn(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithLocation(newYork))
+
+	userID := primitive.NewObjectID()
+	day := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	mockTaskRepo.On("GetByUserID", mock.Anything, userID, mock.Anything).Return([]*domain.Task{}, nil)
+
+	_, err = taskUseCase.GetTasksByUserID(context.Background(), userID, domain.TaskFilter{DueOn: &day})
+	assert.NoError(t, err)
+
+	filter := mockTaskRepo.Calls[0].Arguments.Get(2).(domain.TaskFilter)
+	assert.True(t, filter.DueFrom.Equal(time.Date(2024, 12, 31, 5, 0, 0, 0, time.UTC)), "start of day in New York")
+	assert.True(t, filter.DueTo.Equal(time.Date(2025, 1, 1, 4, 59, 59, 999999999, time.UTC)), "end of day in New York")
+
+	// A task due at 23:00 local is still inside the day
+	lateTask := time.Date(2024, 12, 31, 23, 0, 0, 0, newYork)
+	assert.False(t, lateTask.Before(*filter.DueFrom) || lateTask.After(*filter.DueTo))
+}
+
+// TestPreviewTaskFilter tests that the preview counts every match but only
+// samples a capped page of them
+func TestPreviewTaskFilter(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	filter := domain.TaskFilter{Statuses: []string{domain.StatusPending}}
+	sampled := filter
+	sampled.Pagination = domain.Pagination{Page: 1, PageSize: previewSampleSize}
+	sample := make([]*domain.Task, previewSampleSize)
+	for i := range sample {
+		sample[i] = &domain.Task{Title: "Pending", Status: domain.StatusPending}
+	}
+	mockTaskRepo.On("CountByUserID", mock.Anything, userID, filter).Return(int64(12), nil)
+	mockTaskRepo.On("GetByUserID", mock.Anything, userID, sampled).Return(sample, nil)
+
+	preview, err := taskUseCase.PreviewTaskFilter(context.Background(), userID, filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), preview.Count)
+	assert.Len(t, preview.Sample, previewSampleSize)
+	mockTaskRepo.AssertExpectations(t)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestGetTasksSharedWithMe tests that shared tasks are listed page by page
+// and marked overdue like any other listing
+func TestGetTasksSharedWithMe(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)))
+
+	userID := primitive.NewObjectID()
+	page := domain.Pagination{Page: 2, PageSize: 10}
+	shared := &domain.Task{Title: "Shared", UserID: primitive.NewObjectID(), SharedWith: []primitive.ObjectID{userID}, Status: domain.StatusPending, DueDate: now.Add(-time.Hour)}
+	mockTaskRepo.On("GetSharedWith", mock.Anything, userID, page).Return([]*domain.Task{shared}, nil)
+
+	tasks, err := taskUseCase.GetTasksSharedWithMe(context.Background(), userID, page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Task{shared}, tasks)
+	assert.True(t, tasks[0].IsOverdue)
+}
+
+// TestCountTasksByUserID tests that counts use the same filter resolution as listings
+func TestCountTasksByUserID(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	mockTaskRepo.On("CountByUserID", mock.Anything, userID, domain.TaskFilter{Statuses: []string{domain.StatusPending}}).Return(int64(3), nil)
+	mockTaskRepo.On("CountByUserID", mock.Anything, userID, mock.Anything).Return(int64(1), nil)
+
+	count, err := taskUseCase.CountTasksByUserID(context.Background(), userID, domain.TaskFilter{Statuses: []string{domain.StatusPending}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	day := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	count, err = taskUseCase.CountTasksByUserID(context.Background(), userID, domain.TaskFilter{DueOn: &day})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	filter := mockTaskRepo.Calls[1].Arguments.Get(2).(domain.TaskFilter)
+	assert.True(t, filter.DueFrom.Equal(day))
+	assert.True(t, filter.DueTo.Equal(day.Add(24*time.Hour-time.Nanosecond)))
+}
+
+// TestUpdateTask_CompletionBlockedByDependencies tests that unfinished dependencies block completion
+func TestUpdateTask_CompletionBlockedByDependencies(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	doneID := primitive.NewObjectID()
+	openID := primitive.NewObjectID()
+	task := &domain.Task{
+		ID:        taskID,
+		Title:     "Release",
+		Status:    domain.StatusCompleted,
+		DueDate:   time.Now().Add(24 * time.Hour),
+		DependsOn: []primitive.ObjectID{doneID, openID},
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, Status: domain.StatusInProgress}, nil)
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{doneID, openID}).Return([]*domain.Task{
+		{ID: doneID, UserID: userID, Status: domain.StatusCompleted},
+		{ID: openID, UserID: userID, Status: domain.StatusPending},
+	}, nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), task, userID, false)
+
+	var blocked *domain.TaskBlockedError
+	assert.ErrorAs(t, err, &blocked)
+	assert.Equal(t, []primitive.ObjectID{openID}, blocked.BlockingTaskIDs)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_CompletionWithFinishedDependencies tests completing once every dependency is done
+func TestUpdateTask_CompletionWithFinishedDependencies(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	doneID := primitive.NewObjectID()
+	task := &domain.Task{
+		ID:        taskID,
+		Title:     "Release",
+		Status:    domain.StatusCompleted,
+		DueDate:   time.Now().Add(24 * time.Hour),
+		DependsOn: []primitive.ObjectID{doneID},
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, Status: domain.StatusInProgress}, nil)
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{doneID}).Return([]*domain.Task{
+		{ID: doneID, UserID: userID, Status: domain.StatusCompleted},
+	}, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil)
+
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), task, userID, false))
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestUpdateTask_NotFound tests that updating a missing task reports
+// ErrTaskNotFound instead of dereferencing the missing task
+func TestUpdateTask_NotFound(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return((*domain.Task)(nil), nil)
+
+	update := &domain.Task{ID: taskID, Title: "Gone", Status: domain.StatusCompleted, DueDate: time.Now().Add(time.Hour)}
+	assert.NotPanics(t, func() {
+		err := taskUseCase.UpdateTask(context.Background(), update, primitive.NewObjectID(), false)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_DeletedConcurrently tests that a task deleted between the
+// read and the write is still reported as ErrTaskNotFound
+func TestUpdateTask_DeletedConcurrently(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrTaskNotFound)
+
+	update := &domain.Task{ID: taskID, Title: "Gone", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	err := taskUseCase.UpdateTask(context.Background(), update, userID, false)
+
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+// TestUpdateTask_SetsCompletedAt tests that completing a task records when,
+// and that later updates keep that time
+func TestUpdateTask_SetsCompletedAt(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := infrastructure.NewFakeClock(now)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(clock))
+
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	existing := &domain.Task{ID: taskID, UserID: userID, Title: "Report", Status: domain.StatusInProgress}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(existing, nil).Once()
+	mockTaskRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	update := &domain.Task{ID: taskID, Title: "Report", Status: domain.StatusCompleted, DueDate: now.Add(time.Hour)}
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), update, userID, false))
+	if assert.NotNil(t, update.CompletedAt) {
+		assert.Equal(t, now, *update.CompletedAt)
+	}
+
+	clock.Advance(time.Hour)
+	completed := *update
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&completed, nil).Once()
+	spoofed := now.Add(-24 * time.Hour)
+	retitled := &domain.Task{ID: taskID, Title: "Final report", Status: domain.StatusCompleted, DueDate: now.Add(2 * time.Hour), CompletedAt: &spoofed}
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), retitled, userID, false))
+	if assert.NotNil(t, retitled.CompletedAt) {
+		assert.Equal(t, now, *retitled.CompletedAt, "a completed task keeps its completion time")
+	}
+}
+
+// TestCreateTask_CompletedAt tests that only tasks created completed get a completion time
+func TestCreateTask_CompletedAt(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)))
+	mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+	done := &domain.Task{Title: "Done", UserID: primitive.NewObjectID(), Status: domain.StatusCompleted, DueDate: now.Add(time.Hour)}
+	_, err := taskUseCase.CreateTask(context.Background(), done)
+	assert.NoError(t, err)
+	if assert.NotNil(t, done.CompletedAt) {
+		assert.Equal(t, now, *done.CompletedAt)
+	}
+
+	spoofed := now.Add(-time.Hour)
+	open := &domain.Task{Title: "Open", UserID: primitive.NewObjectID(), DueDate: now.Add(time.Hour), CompletedAt: &spoofed}
+	_, err = taskUseCase.CreateTask(context.Background(), open)
+	assert.NoError(t, err)
+	assert.Nil(t, open.CompletedAt)
+}
+
+// TestGetCompletedTasks tests that calendar days are resolved into the
+// window passed to the repository
+func TestGetCompletedTasks(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithLocation(loc))
+
+	userID := primitive.NewObjectID()
+	fromDay := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)
+	wantFrom := time.Date(2024, 5, 6, 0, 0, 0, 0, loc)
+	wantTo := time.Date(2024, 5, 12, 23, 59, 59, 999999999, loc)
+	page := domain.Pagination{Page: 1, PageSize: 10}
+	tasks := []*domain.Task{{Title: "Report", Status: domain.StatusCompleted}}
+	mockTaskRepo.On("GetCompleted", mock.Anything, userID, &wantFrom, &wantTo, page).Return(tasks, nil)
+
+	result, err := taskUseCase.GetCompletedTasks(context.Background(), userID, domain.CompletedFilter{FromDay: &fromDay, ToDay: &toDay, Pagination: page})
+
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+// TestGetCompletedTasks_InvalidRange tests that a window ending before it starts is rejected
+func TestGetCompletedTasks_InvalidRange(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	from := time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	_, err := taskUseCase.GetCompletedTasks(context.Background(), primitive.NewObjectID(), domain.CompletedFilter{From: &from, To: &to})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+	mockTaskRepo.AssertNotCalled(t, "GetCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_SelfDependency tests rejecting a task that depends on itself
+func TestUpdateTask_SelfDependency(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	task := &domain.Task{ID: taskID, Title: "Loop", DueDate: time.Now().Add(time.Hour), DependsOn: []primitive.ObjectID{taskID}}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusPending}, nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), task, ownerID, false)
+	assert.ErrorIs(t, err, domain.ErrSelfDependency)
+}
+
+// TestUpdateTask_DependencyCycle tests rejecting A -> B -> C -> A
+func TestUpdateTask_DependencyCycle(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	taskA := primitive.NewObjectID()
+	taskB := primitive.NewObjectID()
+	taskC := primitive.NewObjectID()
+	task := &domain.Task{ID: taskA, Title: "A", DueDate: time.Now().Add(time.Hour), DependsOn: []primitive.ObjectID{taskB}}
+	mockTaskRepo.On("GetByID", mock.Anything, taskA).Return(&domain.Task{ID: taskA, UserID: userID, Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{taskB}).Return([]*domain.Task{
+		{ID: taskB, UserID: userID, DependsOn: []primitive.ObjectID{taskC}},
+	}, nil)
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{taskC}).Return([]*domain.Task{
+		{ID: taskC, UserID: userID, DependsOn: []primitive.ObjectID{taskA}},
+	}, nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), task, userID, false)
+	assert.ErrorIs(t, err, domain.ErrDependencyCycle)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_DeletedDependency tests that deleting a dependency does not
+// stop later updates of the task that depended on it
+func TestUpdateTask_DeletedDependency(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	goneID := primitive.NewObjectID()
+	keptID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, goneID).Return(&domain.Task{ID: goneID, UserID: userID, Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("Delete", mock.Anything, goneID).Return(nil)
+	assert.NoError(t, taskUseCase.DeleteTask(context.Background(), goneID, userID, false, false))
+
+	task := &domain.Task{
+		ID:        taskID,
+		Title:     "Release",
+		Status:    domain.StatusInProgress,
+		DueDate:   time.Now().Add(24 * time.Hour),
+		DependsOn: []primitive.ObjectID{goneID, keptID},
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{
+		ID:        taskID,
+		UserID:    userID,
+		Status:    domain.StatusPending,
+		DependsOn: []primitive.ObjectID{goneID, keptID},
+	}, nil)
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{goneID, keptID}).Return([]*domain.Task{
+		{ID: keptID, UserID: userID, Status: domain.StatusPending},
+	}, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil)
+
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), task, userID, false))
+	assert.Equal(t, []primitive.ObjectID{keptID}, task.DependsOn)
+
+	// A dependency that is missing and was not there before is still rejected
+	newID := primitive.NewObjectID()
+	task.DependsOn = []primitive.ObjectID{keptID, newID}
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{keptID, newID}).Return([]*domain.Task{
+		{ID: keptID, UserID: userID, Status: domain.StatusPending},
+	}, nil)
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), task, userID, false), domain.ErrInvalidDependency)
+}
+
+// TestCreateTask_ForeignDependency tests rejecting dependencies owned by another user
+func TestCreateTask_ForeignDependency(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	otherID := primitive.NewObjectID()
+	depID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Mine", UserID: primitive.NewObjectID(), DueDate: time.Now().Add(time.Hour), DependsOn: []primitive.ObjectID{depID}}
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{depID}).Return([]*domain.Task{{ID: depID, UserID: otherID}}, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+	assert.ErrorIs(t, err, domain.ErrInvalidDependency)
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_MissingDependency tests rejecting dependencies that do not exist
+func TestCreateTask_MissingDependency(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	depID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Mine", UserID: primitive.NewObjectID(), DueDate: time.Now().Add(time.Hour), DependsOn: []primitive.ObjectID{depID}}
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{depID}).Return([]*domain.Task{}, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+	assert.ErrorIs(t, err, domain.ErrInvalidDependency)
+}
+
+// TestCreateTask_QuotaBoundary tests creation just under, at and over the quota
+func TestCreateTask_QuotaBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int64
+		wantErr error
+	}{
+		{"one under", 4, nil},
+		{"exactly at", 5, domain.ErrTaskQuotaExceeded},
+		{"one over", 6, domain.ErrTaskQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTaskRepo := new(MockTaskRepository)
+			mockUserRepo := new(MockUserRepository)
+			taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo), WithTaskQuota(5))
+
+			userID := primitive.NewObjectID()
+			task := &domain.Task{Title: "Task", UserID: userID, DueDate: time.Now().Add(time.Hour)}
+			mockUserRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: "user"}, nil)
+			mockTaskRepo.On("CountByUserID", mock.Anything, userID, domain.TaskFilter{}).Return(tt.count, nil)
+			mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+			_, err := taskUseCase.CreateTask(context.Background(), task)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCreateTask_QuotaAdminExempt tests that admins can exceed the quota
+func TestCreateTask_QuotaAdminExempt(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo), WithTaskQuota(5))
+
+	adminID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Task", UserID: adminID, DueDate: time.Now().Add(time.Hour)}
+	mockUserRepo.On("GetByID", mock.Anything, adminID).Return(&domain.User{ID: adminID, Role: "admin"}, nil)
+	mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	mockTaskRepo.AssertNotCalled(t, "CountByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_CreateInterval tests that back-to-back creations are
+// throttled while spaced-out ones go through
+func TestCreateTask_CreateInterval(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		latest  time.Time
+		wantErr error
+	}{
+		{"back to back", now.Add(-time.Second), domain.ErrTaskCreateThrottled},
+		{"spaced out", now.Add(-30 * time.Second), nil},
+		{"first task", time.Time{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTaskRepo := new(MockTaskRepository)
+			mockUserRepo := new(MockUserRepository)
+			taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo),
+				WithClock(infrastructure.NewFakeClock(now)), WithCreateInterval(30*time.Second))
+
+			userID := primitive.NewObjectID()
+			task := &domain.Task{Title: "Task", UserID: userID, DueDate: now.Add(time.Hour)}
+			mockUserRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID, Role: "user"}, nil)
+			mockTaskRepo.On("LatestCreatedAt", mock.Anything, userID).Return(tt.latest, nil)
+			mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+			_, err := taskUseCase.CreateTask(context.Background(), task)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCreateTask_CreateIntervalAdminExempt tests that admins are never throttled
+func TestCreateTask_CreateIntervalAdminExempt(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo), WithCreateInterval(time.Minute))
+
+	adminID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Task", UserID: adminID, DueDate: time.Now().Add(time.Hour)}
+	mockUserRepo.On("GetByID", mock.Anything, adminID).Return(&domain.User{ID: adminID, Role: "admin"}, nil)
+	mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	mockTaskRepo.AssertNotCalled(t, "LatestCreatedAt", mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_DuplicateTitle tests that a second unfinished task with the
+// same title is rejected when unique titles are on
+func TestCreateTask_DuplicateTitle(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUniqueTitles(true))
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Write Report", UserID: userID, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("TitleTaken", mock.Anything, userID, "Write Report", primitive.NilObjectID).Return(true, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateTaskTitle)
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_DuplicateTitleAllowed tests that titles are not checked when the rule is off
+func TestCreateTask_DuplicateTitleAllowed(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUniqueTitles(false))
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Write Report", UserID: userID, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	mockTaskRepo.AssertNotCalled(t, "TitleTaken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_DuplicateTitleCompleted tests that a completed task never conflicts
+func TestCreateTask_DuplicateTitleCompleted(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUniqueTitles(true))
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Write Report", UserID: userID, Status: domain.StatusCompleted, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	mockTaskRepo.AssertNotCalled(t, "TitleTaken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_DuplicateTitle tests that renaming a task onto another
+// unfinished task's title is rejected, excluding the task itself
+func TestUpdateTask_DuplicateTitle(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUniqueTitles(true))
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Title: "Draft", Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("TitleTaken", mock.Anything, ownerID, "write report", taskID).Return(true, nil)
+
+	update := &domain.Task{ID: taskID, Title: "write report", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	err := taskUseCase.UpdateTask(context.Background(), update, ownerID, false)
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateTaskTitle)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestGetTaskStatsByUserID tests that stats are computed at the clock's time
+func TestGetTaskStatsByUserID(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo), WithClock(infrastructure.NewFakeClock(now)))
+
+	userID := primitive.NewObjectID()
+	stats := &domain.TaskStats{Total: 6, Pending: 3, InProgress: 1, Completed: 2, Overdue: 2}
+	mockUserRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID}, nil)
+	mockTaskRepo.On("StatsByUserID", mock.Anything, userID, now).Return(stats, nil)
+
+	result, err := taskUseCase.GetTaskStatsByUserID(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stats, result)
+}
+
+// TestGetTaskStatsByUserID_UserNotFound tests stats for a nonexistent user
+func TestGetTaskStatsByUserID_UserNotFound(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	userID := primitive.NewObjectID()
+	mockUserRepo.On("GetByID", mock.Anything, userID).Return((*domain.User)(nil), nil)
+
+	_, err := taskUseCase.GetTaskStatsByUserID(context.Background(), userID)
+
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	mockTaskRepo.AssertNotCalled(t, "StatsByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetActivityByUserID tests that task events are merged newest first and paginated
+func TestGetActivityByUserID(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+	userID := primitive.NewObjectID()
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 9, 0, 0, 0, time.UTC) }
+	report := &domain.Task{ID: primitive.NewObjectID(), Title: "Report", Status: domain.StatusCompleted, CreatedAt: day(1), UpdatedAt: day(4)}
+	review := &domain.Task{ID: primitive.NewObjectID(), Title: "Review", Status: domain.StatusPending, CreatedAt: day(3), UpdatedAt: day(5)}
+	plan := &domain.Task{ID: primitive.NewObjectID(), Title: "Plan", Status: domain.StatusInProgress, CreatedAt: day(2), UpdatedAt: day(2)}
+	// Each page loads the newest creations and completions up to its end
+	for _, window := range []domain.Pagination{{Page: 1}, {Page: 1, PageSize: 6}, {Page: 1, PageSize: 9}} {
+		mockTaskRepo.On("GetByUserID", mock.Anything, userID, domain.TaskFilter{Sort: domain.TaskSortNewest, Pagination: window}).Return([]*domain.Task{review, plan, report}, nil)
+		mockTaskRepo.On("GetCompleted", mock.Anything, userID, (*time.Time)(nil), (*time.Time)(nil), window).Return([]*domain.Task{report}, nil)
+	}
+
+	events, err := taskUseCase.GetActivityByUserID(context.Background(), userID, domain.Pagination{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ActivityEvent{
+		{Type: domain.ActivityTaskCompleted, TaskID: report.ID, TaskTitle: "Report", At: day(4)},
+		{Type: domain.ActivityTaskCreated, TaskID: review.ID, TaskTitle: "Review", At: day(3)},
+		{Type: domain.ActivityTaskCreated, TaskID: plan.ID, TaskTitle: "Plan", At: day(2)},
+		{Type: domain.ActivityTaskCreated, TaskID: report.ID, TaskTitle: "Report", At: day(1)},
+	}, events)
+
+	events, err = taskUseCase.GetActivityByUserID(context.Background(), userID, domain.Pagination{Page: 2, PageSize: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ActivityEvent{{Type: domain.ActivityTaskCreated, TaskID: report.ID, TaskTitle: "Report", At: day(1)}}, events)
+
+	events, err = taskUseCase.GetActivityByUserID(context.Background(), userID, domain.Pagination{Page: 3, PageSize: 3})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestUpdateTask_KeepsCreatedAt tests that an edit keeps the creation time,
+// so the task's created event stays where it was in the activity feed
+func TestUpdateTask_KeepsCreatedAt(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+	ownerID := primitive.NewObjectID()
+	createdAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	existing := &domain.Task{ID: primitive.NewObjectID(), UserID: ownerID, Title: "Draft", Status: domain.StatusPending, CreatedAt: createdAt}
+	mockTaskRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	update := &domain.Task{ID: existing.ID, Title: "Report", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	err := taskUseCase.UpdateTask(context.Background(), update, ownerID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, update.CreatedAt)
+
+	window := domain.Pagination{Page: 1, PageSize: 10}
+	mockTaskRepo.On("GetByUserID", mock.Anything, ownerID, domain.TaskFilter{Sort: domain.TaskSortNewest, Pagination: window}).Return([]*domain.Task{update}, nil)
+	mockTaskRepo.On("GetCompleted", mock.Anything, ownerID, (*time.Time)(nil), (*time.Time)(nil), window).Return([]*domain.Task{}, nil)
+
+	events, err := taskUseCase.GetActivityByUserID(context.Background(), ownerID, domain.Pagination{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ActivityEvent{{Type: domain.ActivityTaskCreated, TaskID: existing.ID, TaskTitle: "Report", At: createdAt}}, events)
+}
+
+// TestGetUpcomingTasks tests that the limit is passed to the repository
+func TestGetUpcomingTasks(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	tasks := []*domain.Task{{Title: "Soon"}, {Title: "Later"}}
+	mockTaskRepo.On("GetUpcoming", mock.Anything, userID, int64(2)).Return(tasks, nil)
+
+	result, err := taskUseCase.GetUpcomingTasks(context.Background(), userID, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+// TestGetDueReminders tests that reminders are looked up at the clock's time
+func TestGetDueReminders(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)))
+
+	userID := primitive.NewObjectID()
+	tasks := []*domain.Task{{Title: "Call back", DueDate: now.Add(time.Hour)}}
+	mockTaskRepo.On("GetRemindersDue", mock.Anything, userID, now, int64(5)).Return(tasks, nil)
+
+	result, err := taskUseCase.GetDueReminders(context.Background(), userID, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+// TestSearchTasks tests that the trimmed query is passed to the repository
+func TestSearchTasks(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+	userID := primitive.NewObjectID()
+	page := domain.Pagination{Page: 1, PageSize: 20}
+	tasks := []*domain.Task{{Title: "Quarterly report", Score: 2}}
+	mockTaskRepo.On("Search", mock.Anything, userID, "report", page).Return(tasks, nil)
+
+	result, err := taskUseCase.SearchTasks(context.Background(), userID, "  report ", page)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestSearchTasks_EmptyQuery tests that a blank query is rejected
+func TestSearchTasks_EmptyQuery(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	_, err := taskUseCase.SearchTasks(context.Background(), primitive.NewObjectID(), "   ", domain.Pagination{})
+	assert.ErrorIs(t, err, domain.ErrEmptySearchQuery)
+	mockTaskRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetUpcomingTasks_InvalidLimit tests that a non-positive limit is rejected
+func TestGetUpcomingTasks_InvalidLimit(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	_, err := taskUseCase.GetUpcomingTasks(context.Background(), primitive.NewObjectID(), 0)
+
+	assert.Error(t, err)
+	mockTaskRepo.AssertNotCalled(t, "GetUpcoming", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCloneTask tests that a clone is a new pending task with copied fields
+func TestCloneTask(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	source := &domain.Task{
+		ID:          primitive.NewObjectID(),
+		Title:       "Weekly report",
+		Description: "Summarize the week",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      domain.StatusCompleted,
+		UserID:      userID,
+		Tags:        []string{"work"},
+		CreatedAt:   time.Now().Add(-48 * time.Hour),
+		UpdatedAt:   time.Now().Add(-24 * time.Hour),
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+	stored := &domain.Task{}
+	mockTaskRepo.On("Create", mock.Anything, mock.AnythingOfType("*Domain.Task")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*domain.Task)
+		stored.ID = primitive.NewObjectID()
+	}).Return(stored, nil)
+
+	description := "Summarize the month"
+	clone, err := taskUseCase.CloneTask(context.Background(), source.ID, userID, domain.CloneTaskRequest{Description: &description})
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, "Weekly report", clone.Title)
+	assert.Equal(t, "Summarize the month", clone.Description)
+	assert.Equal(t, source.DueDate, clone.DueDate)
+	assert.Equal(t, []string{"work"}, clone.Tags)
+	assert.Equal(t, domain.StatusPending, clone.Status)
+	assert.True(t, clone.CreatedAt.IsZero())
+	assert.Equal(t, domain.StatusCompleted, source.Status)
+}
+
+// TestCloneTask_PastDueDate tests that a task past its due date can be cloned,
+// leaving the clone without one
+func TestCloneTask_PastDueDate(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	source := &domain.Task{ID: primitive.NewObjectID(), Title: "Last week's report", DueDate: time.Now().Add(-24 * time.Hour), UserID: userID}
+	mockTaskRepo.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+	mockTaskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.DueDate.IsZero()
+	})).Return(&domain.Task{ID: primitive.NewObjectID(), Title: source.Title}, nil)
+
+	_, err := taskUseCase.CloneTask(context.Background(), source.ID, userID, domain.CloneTaskRequest{})
+
+	assert.NoError(t, err)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestCloneTask_OtherOwner tests that another user's task cannot be cloned
+func TestCloneTask_OtherOwner(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	source := &domain.Task{ID: primitive.NewObjectID(), Title: "Private", UserID: primitive.NewObjectID()}
+	mockTaskRepo.On("GetByID", mock.Anything, source.ID).Return(source, nil)
+
+	_, err := taskUseCase.CloneTask(context.Background(), source.ID, primitive.NewObjectID(), domain.CloneTaskRequest{})
+
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestGetTasksByIDs tests a batch with owned, forbidden and nonexistent IDs
+func TestGetTasksByIDs(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	own := &domain.Task{ID: primitive.NewObjectID(), Title: "Mine", UserID: userID}
+	other := &domain.Task{ID: primitive.NewObjectID(), Title: "Theirs", UserID: primitive.NewObjectID()}
+	missing := primitive.NewObjectID()
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{own.ID, other.ID, missing}).Return([]*domain.Task{other, own}, nil)
+
+	result, err := taskUseCase.GetTasksByIDs(context.Background(), userID, []primitive.ObjectID{own.ID, other.ID, missing, own.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Task{own}, result.Tasks)
+	assert.Equal(t, []primitive.ObjectID{other.ID}, result.Forbidden)
+	assert.Equal(t, []primitive.ObjectID{missing}, result.Missing)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestGetTasksByIDs_TooMany tests the configurable cap on IDs per request
+func TestGetTasksByIDs_TooMany(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithBatchLimit(2))
+
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	_, err := taskUseCase.GetTasksByIDs(context.Background(), primitive.NewObjectID(), ids)
+
+	assert.ErrorIs(t, err, domain.ErrBatchTooLarge)
+	mockTaskRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+// TestBulkTagTasks_Add tests adding tags to several owned tasks
+func TestBulkTagTasks_Add(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	first := &domain.Task{ID: primitive.NewObjectID(), UserID: userID}
+	second := &domain.Task{ID: primitive.NewObjectID(), UserID: userID}
+	ids := []primitive.ObjectID{first.ID, second.ID}
+	mockTaskRepo.On("GetByIDs", mock.Anything, ids).Return([]*domain.Task{first, second}, nil)
+	mockTaskRepo.On("UpdateTags", mock.Anything, userID, ids, []string{"urgent"}, []string(nil)).Return(int64(2), nil)
+
+	result, err := taskUseCase.BulkTagTasks(context.Background(), userID, ids, []string{"urgent"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Updated)
+	assert.Empty(t, result.Skipped)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestBulkTagTasks_Remove tests removing a tag from an owned task
+func TestBulkTagTasks_Remove(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Tags: []string{"later"}}
+	ids := []primitive.ObjectID{task.ID}
+	mockTaskRepo.On("GetByIDs", mock.Anything, ids).Return([]*domain.Task{task}, nil)
+	mockTaskRepo.On("UpdateTags", mock.Anything, userID, ids, []string(nil), []string{"later"}).Return(int64(1), nil)
+
+	result, err := taskUseCase.BulkTagTasks(context.Background(), userID, ids, nil, []string{"later"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Updated)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestBulkTagTasks_AddAndRemove tests adding and removing tags in one request
+func TestBulkTagTasks_AddAndRemove(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Tags: []string{"draft"}}
+	ids := []primitive.ObjectID{task.ID}
+	mockTaskRepo.On("GetByIDs", mock.Anything, ids).Return([]*domain.Task{task}, nil)
+	mockTaskRepo.On("UpdateTags", mock.Anything, userID, ids, []string{"final"}, []string{"draft"}).Return(int64(1), nil)
+
+	result, err := taskUseCase.BulkTagTasks(context.Background(), userID, ids, []string{"final"}, []string{"draft"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Updated)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestBulkTagTasks_SkipsUnowned tests that other users' and nonexistent tasks are skipped
+func TestBulkTagTasks_SkipsUnowned(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	own := &domain.Task{ID: primitive.NewObjectID(), UserID: userID}
+	other := &domain.Task{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+	missing := primitive.NewObjectID()
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{other.ID, own.ID, missing}).Return([]*domain.Task{own, other}, nil)
+	mockTaskRepo.On("UpdateTags", mock.Anything, userID, []primitive.ObjectID{own.ID}, []string{"x"}, []string(nil)).Return(int64(1), nil)
+
+	result, err := taskUseCase.BulkTagTasks(context.Background(), userID, []primitive.ObjectID{other.ID, own.ID, missing, own.ID}, []string{"x"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Updated)
+	assert.Equal(t, []primitive.ObjectID{other.ID, missing}, result.Skipped)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestBulkTagTasks_NothingOwned tests that no update is issued when every ID is skipped
+func TestBulkTagTasks_NothingOwned(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	other := &domain.Task{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{other.ID}).Return([]*domain.Task{other}, nil)
+
+	result, err := taskUseCase.BulkTagTasks(context.Background(), primitive.NewObjectID(), []primitive.ObjectID{other.ID}, []string{"x"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), result.Updated)
+	assert.Equal(t, []primitive.ObjectID{other.ID}, result.Skipped)
+	mockTaskRepo.AssertNotCalled(t, "UpdateTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestBulkTagTasks_TagLimits tests that added tags obey the length limit and
+// that tasks the change would take past the tag limit are left alone
+func TestBulkTagTasks_TagLimits(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTagLimits(2, 5))
+
+	userID := primitive.NewObjectID()
+	_, err := taskUseCase.BulkTagTasks(context.Background(), userID, []primitive.ObjectID{primitive.NewObjectID()}, []string{"toolong"}, nil)
+	assert.ErrorIs(t, err, domain.ErrTagTooLong)
+	_, err = taskUseCase.BulkTagTasks(context.Background(), userID, []primitive.ObjectID{primitive.NewObjectID()}, []string{"a", "b", "c"}, nil)
+	assert.ErrorIs(t, err, domain.ErrTooManyTags)
+
+	full := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Tags: []string{"a", "b"}}
+	swapped := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Tags: []string{"a", "old"}}
+	empty := &domain.Task{ID: primitive.NewObjectID(), UserID: userID}
+	ids := []primitive.ObjectID{full.ID, swapped.ID, empty.ID}
+	mockTaskRepo.On("GetByIDs", mock.Anything, ids).Return([]*domain.Task{full, swapped, empty}, nil)
+	mockTaskRepo.On("UpdateTags", mock.Anything, userID, []primitive.ObjectID{swapped.ID, empty.ID}, []string{"new"}, []string{"old"}).Return(int64(2), nil)
+
+	result, err := taskUseCase.BulkTagTasks(context.Background(), userID, ids, []string{"new"}, []string{"old"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Updated)
+	assert.Equal(t, []primitive.ObjectID{full.ID}, result.OverLimit)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestBulkTagTasks_NoChanges tests rejecting a request without tags to add or remove
+func TestBulkTagTasks_NoChanges(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	_, err := taskUseCase.BulkTagTasks(context.Background(), primitive.NewObjectID(), []primitive.ObjectID{primitive.NewObjectID()}, nil, []string{})
+
+	assert.ErrorIs(t, err, domain.ErrNoTagChanges)
+	mockTaskRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+// TestReorderTasks tests that owned tasks keep the requested order and the rest are skipped
+func TestReorderTasks(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	first := &domain.Task{ID: primitive.NewObjectID(), UserID: userID}
+	second := &domain.Task{ID: primitive.NewObjectID(), UserID: userID}
+	other := &domain.Task{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+	missing := primitive.NewObjectID()
+	mockTaskRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{second.ID, other.ID, first.ID, missing}).
+		Return([]*domain.Task{first, second, other}, nil)
+	mockTaskRepo.On("Reorder", mock.Anything, userID, []primitive.ObjectID{second.ID, first.ID}).Return(int64(2), nil)
+
+	result, err := taskUseCase.ReorderTasks(context.Background(), userID, []primitive.ObjectID{second.ID, other.ID, first.ID, missing, second.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ReorderResult{Updated: 2, Skipped: []primitive.ObjectID{other.ID, missing}}, result)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestReorderTasks_BatchTooLarge tests that the batch limit applies to reordering
+func TestReorderTasks_BatchTooLarge(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithBatchLimit(1))
+
+	_, err := taskUseCase.ReorderTasks(context.Background(), primitive.NewObjectID(), []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()})
+
+	assert.ErrorIs(t, err, domain.ErrBatchTooLarge)
+	mockTaskRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_KeepsOrder tests that a full update cannot move the task in the manual order
+func TestUpdateTask_KeepsOrder(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	existing := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Status: domain.StatusPending, Order: 3}
+	update := &domain.Task{ID: existing.ID, Title: "Renamed", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockTaskRepo.On("Update", mock.Anything, update).Return(nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), update, userID, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, update.Order)
+}
+
+// TestUpdateTask_RecordsHistory tests that an update records a revision with the changed fields
+func TestUpdateTask_RecordsHistory(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockHistoryRepo := new(MockTaskHistoryRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskHistoryRepository(mockHistoryRepo))
+
+	userID := primitive.NewObjectID()
+	due := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	existing := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Title: "Draft", Status: domain.StatusPending, DueDate: due.UTC(), Tags: []string{}}
+	update := &domain.Task{ID: existing.ID, Title: "Final", Status: domain.StatusInProgress, DueDate: due}
+	mockTaskRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockTaskRepo.On("Update", mock.Anything, update).Return(nil)
+	mockHistoryRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), update, userID, false)
+
+	assert.NoError(t, err)
+	mockHistoryRepo.AssertNumberOfCalls(t, "Create", 1)
+	revision := mockHistoryRepo.Calls[0].Arguments.Get(1).(*domain.TaskRevision)
+	assert.Equal(t, existing.ID, revision.TaskID)
+	assert.Equal(t, userID, revision.ActorID)
+	assert.Equal(t, []string{"title", "status"}, revision.Changes, "the due date is the same instant and empty tags equal none")
+	assert.Equal(t, "Draft", revision.Before.Title)
+	assert.Equal(t, domain.StatusPending, revision.Before.Status)
+	assert.Equal(t, "Final", revision.After.Title)
+	assert.Equal(t, domain.StatusInProgress, revision.After.Status)
+}
+
+// TestUpdateTask_UnchangedSkipsHistory tests that an update changing nothing records no revision
+func TestUpdateTask_UnchangedSkipsHistory(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockHistoryRepo := new(MockTaskHistoryRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskHistoryRepository(mockHistoryRepo))
+
+	userID := primitive.NewObjectID()
+	due := time.Now().Add(time.Hour)
+	existing := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Title: "Same", Status: domain.StatusPending, DueDate: due}
+	update := &domain.Task{ID: existing.ID, Title: "Same", Status: domain.StatusPending, DueDate: due}
+	mockTaskRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockTaskRepo.On("Update", mock.Anything, update).Return(nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), update, userID, false)
+
+	assert.NoError(t, err)
+	mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_HistoryFailure tests that the task is not saved when its revision cannot be recorded
+func TestUpdateTask_HistoryFailure(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockHistoryRepo := new(MockTaskHistoryRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskHistoryRepository(mockHistoryRepo))
+
+	userID := primitive.NewObjectID()
+	existing := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Title: "Draft", Status: domain.StatusPending}
+	update := &domain.Task{ID: existing.ID, Title: "Final", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockHistoryRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	err := taskUseCase.UpdateTask(context.Background(), update, userID, false)
+
+	assert.Error(t, err)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestTaskChanges_RecordHistory tests that the changes made outside
+// UpdateTask are recorded as revisions too
+func TestTaskChanges_RecordHistory(t *testing.T) {
+	ownerID, targetID, adminID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	newTask := func() *domain.Task {
+		return &domain.Task{
+			ID:        primitive.NewObjectID(),
+			Title:     "Tracked",
+			UserID:    ownerID,
+			Status:    domain.StatusPending,
+			Tags:      []string{"draft"},
+			Checklist: []domain.ChecklistItem{{Text: "Write"}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		actorID primitive.ObjectID
+		change  func(taskUseCase domain.TaskUseCase, repo *MockTaskRepository, task *domain.Task) error
+		changes []string
+	}{
+		{"toggle checklist item", ownerID, func(taskUseCase domain.TaskUseCase, repo *MockTaskRepository, task *domain.Task) error {
+			repo.On("UpdateFields", mock.Anything, task.ID, mock.Anything).Return(nil)
+			_, err := taskUseCase.ToggleChecklistItem(context.Background(), task.ID, ownerID, false, 0)
+			return err
+		}, []string{"checklist"}},
+		{"force complete", adminID, func(taskUseCase domain.TaskUseCase, repo *MockTaskRepository, task *domain.Task) error {
+			repo.On("UpdateFields", mock.Anything, task.ID, mock.Anything).Return(nil)
+			_, err := taskUseCase.ForceCompleteTask(context.Background(), task.ID, adminID)
+			return err
+		}, []string{"status"}},
+		{"transfer", ownerID, func(taskUseCase domain.TaskUseCase, repo *MockTaskRepository, task *domain.Task) error {
+			repo.On("Update", mock.Anything, task).Return(nil)
+			_, err := taskUseCase.TransferTask(context.Background(), task.ID, ownerID, false, targetID)
+			return err
+		}, []string{"user_id"}},
+		{"share", ownerID, func(taskUseCase domain.TaskUseCase, repo *MockTaskRepository, task *domain.Task) error {
+			repo.On("Update", mock.Anything, task).Return(nil)
+			_, err := taskUseCase.ShareTask(context.Background(), task.ID, ownerID, false, targetID, domain.SharePermissionRead)
+			return err
+		}, []string{"shared_with"}},
+		{"bulk tag", ownerID, func(taskUseCase domain.TaskUseCase, repo *MockTaskRepository, task *domain.Task) error {
+			repo.On("GetByIDs", mock.Anything, []primitive.ObjectID{task.ID}).Return([]*domain.Task{task}, nil)
+			repo.On("UpdateTags", mock.Anything, ownerID, []primitive.ObjectID{task.ID}, []string{"final"}, []string{"draft"}).Return(int64(1), nil)
+			_, err := taskUseCase.BulkTagTasks(context.Background(), ownerID, []primitive.ObjectID{task.ID}, []string{"final"}, []string{"draft"})
+			return err
+		}, []string{"tags"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTaskRepo := new(MockTaskRepository)
+			mockUserRepo := new(MockUserRepository)
+			mockAuditRepo := new(MockAuditRepository)
+			mockHistoryRepo := new(MockTaskHistoryRepository)
+			taskUseCase := NewTaskUseCase(mockTaskRepo,
+				WithUserRepository(mockUserRepo),
+				WithTaskAuditRepository(mockAuditRepo),
+				WithTaskHistoryRepository(mockHistoryRepo),
+			)
+
+			task := newTask()
+			mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+			mockTaskRepo.On("CountByUserID", mock.Anything, targetID, mock.Anything).Return(int64(0), nil)
+			mockUserRepo.On("GetByID", mock.Anything, targetID).Return(&domain.User{ID: targetID}, nil)
+			mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			mockHistoryRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			assert.NoError(t, tt.change(taskUseCase, mockTaskRepo, task))
+
+			mockHistoryRepo.AssertNumberOfCalls(t, "Create", 1)
+			revision := mockHistoryRepo.Calls[0].Arguments.Get(1).(*domain.TaskRevision)
+			assert.Equal(t, task.ID, revision.TaskID)
+			assert.Equal(t, tt.actorID, revision.ActorID)
+			assert.Equal(t, tt.changes, revision.Changes)
+		})
+	}
+}
+
+// TestGetTaskHistory tests that only the owner or an admin can list a task's revisions
+func TestGetTaskHistory(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockHistoryRepo := new(MockTaskHistoryRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskHistoryRepository(mockHistoryRepo))
+
+	ownerID, viewerID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: ownerID, SharedWith: []primitive.ObjectID{viewerID}}
+	page := domain.Pagination{Page: 1, PageSize: 20}
+	revisions := []*domain.TaskRevision{{TaskID: task.ID, ActorID: ownerID, Changes: []string{"title"}}}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockHistoryRepo.On("GetByTaskID", mock.Anything, task.ID, page).Return(revisions, nil)
+
+	result, err := taskUseCase.GetTaskHistory(context.Background(), task.ID, ownerID, false, page)
+	assert.NoError(t, err)
+	assert.Equal(t, revisions, result)
+
+	result, err = taskUseCase.GetTaskHistory(context.Background(), task.ID, primitive.NewObjectID(), true, page)
+	assert.NoError(t, err)
+	assert.Equal(t, revisions, result)
+
+	_, err = taskUseCase.GetTaskHistory(context.Background(), task.ID, viewerID, false, page)
+	assert.ErrorIs(t, err, domain.ErrNotTaskOwner)
+
+	_, err = taskUseCase.GetTaskHistory(context.Background(), task.ID, primitive.NewObjectID(), false, page)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+// TestTransferTask tests handing a task to another user
+func TestTransferTask(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	ownerID, targetID, friendID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{
+		ID:            primitive.NewObjectID(),
+		Title:         "Handoff",
+		UserID:        ownerID,
+		AssigneeID:    &friendID,
+		DependsOn:     []primitive.ObjectID{primitive.NewObjectID()},
+		SharedWith:    []primitive.ObjectID{friendID},
+		SharedEditors: []primitive.ObjectID{friendID},
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockUserRepo.On("GetByID", mock.Anything, targetID).Return(&domain.User{ID: targetID}, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil)
+
+	result, err := taskUseCase.TransferTask(context.Background(), task.ID, ownerID, false, targetID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, targetID, result.UserID)
+	assert.Empty(t, result.DependsOn)
+	assert.Empty(t, result.SharedWith)
+	assert.Empty(t, result.SharedEditors)
+	assert.Nil(t, result.AssigneeID)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestTransferTask_DuplicateTitle tests that a task cannot be handed to a
+// user who already has an unfinished task with its title
+func TestTransferTask_DuplicateTitle(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo), WithUniqueTitles(true))
+
+	ownerID, targetID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Handoff", UserID: ownerID, Status: domain.StatusPending}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockUserRepo.On("GetByID", mock.Anything, targetID).Return(&domain.User{ID: targetID}, nil)
+	mockTaskRepo.On("TitleTaken", mock.Anything, targetID, "Handoff", task.ID).Return(true, nil)
+
+	_, err := taskUseCase.TransferTask(context.Background(), task.ID, ownerID, false, targetID)
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateTaskTitle)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestTransferTask_Rejected tests transfers by a stranger, by a reader and to a missing user
+func TestTransferTask_Rejected(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	ownerID, readerID, missingID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Handoff", UserID: ownerID, SharedWith: []primitive.ObjectID{readerID}}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockUserRepo.On("GetByID", mock.Anything, missingID).Return((*domain.User)(nil), nil)
+
+	_, err := taskUseCase.TransferTask(context.Background(), task.ID, primitive.NewObjectID(), false, missingID)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound, "a stranger cannot tell the task exists")
+
+	_, err = taskUseCase.TransferTask(context.Background(), task.ID, readerID, false, missingID)
+	assert.ErrorIs(t, err, domain.ErrNotTaskOwner)
+
+	_, err = taskUseCase.TransferTask(context.Background(), task.ID, primitive.NewObjectID(), true, missingID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "an admin passes the owner check")
+
+	assert.Equal(t, ownerID, task.UserID)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestShareTask_SharedUserReads tests that a user the task is shared with can read but not change it
+func TestShareTask_SharedUserReads(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	ownerID, readerID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Plan", UserID: ownerID, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockUserRepo.On("GetByID", mock.Anything, readerID).Return(&domain.User{ID: readerID}, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil).Once()
+
+	_, err := taskUseCase.GetTaskByID(context.Background(), task.ID, readerID, false)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound, "not shared yet")
+
+	shared, err := taskUseCase.ShareTask(context.Background(), task.ID, ownerID, false, readerID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []primitive.ObjectID{readerID}, shared.SharedWith)
+	assert.Empty(t, shared.SharedEditors)
+
+	result, err := taskUseCase.GetTaskByID(context.Background(), task.ID, readerID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Plan", result.Title)
+
+	update := &domain.Task{ID: task.ID, Title: "Changed", DueDate: time.Now().Add(time.Hour)}
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), update, readerID, false), domain.ErrNotTaskOwner)
+	assert.ErrorIs(t, taskUseCase.DeleteTask(context.Background(), task.ID, readerID, false, false), domain.ErrNotTaskOwner)
+	mockTaskRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+// TestShareTask_WritePermission tests that an editor may update a shared task but not delete or reshare it
+func TestShareTask_WritePermission(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	ownerID, editorID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Plan", UserID: ownerID, Status: domain.StatusPending, SharedWith: []primitive.ObjectID{editorID}, SharedEditors: []primitive.ObjectID{editorID}}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	update := &domain.Task{ID: task.ID, Title: "Changed", Status: domain.StatusInProgress, DueDate: time.Now().Add(time.Hour)}
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), update, editorID, false))
+	assert.Equal(t, ownerID, update.UserID)
+	assert.Equal(t, []primitive.ObjectID{editorID}, update.SharedEditors, "the shares survive an update")
+
+	assert.ErrorIs(t, taskUseCase.DeleteTask(context.Background(), task.ID, editorID, false, false), domain.ErrNotTaskOwner)
+	_, err := taskUseCase.ShareTask(context.Background(), task.ID, editorID, false, primitive.NewObjectID(), domain.SharePermissionRead)
+	assert.ErrorIs(t, err, domain.ErrNotTaskOwner)
+}
+
+// TestShareTask_Rejected tests invalid permissions, sharing with the owner and unknown users
+func TestShareTask_Rejected(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	ownerID, missingID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Plan", UserID: ownerID}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockUserRepo.On("GetByID", mock.Anything, missingID).Return((*domain.User)(nil), nil)
+
+	_, err := taskUseCase.ShareTask(context.Background(), task.ID, ownerID, false, missingID, "admin")
+	assert.ErrorIs(t, err, domain.ErrInvalidSharePermission)
+	_, err = taskUseCase.ShareTask(context.Background(), task.ID, ownerID, false, ownerID, domain.SharePermissionRead)
+	assert.ErrorIs(t, err, domain.ErrShareWithOwner)
+	_, err = taskUseCase.ShareTask(context.Background(), task.ID, ownerID, false, missingID, domain.SharePermissionRead)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestUnshareTask tests that removing a share revokes read and write access
+func TestUnshareTask(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	ownerID, editorID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Plan", UserID: ownerID, SharedWith: []primitive.ObjectID{editorID}, SharedEditors: []primitive.ObjectID{editorID}}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockTaskRepo.On("Update", mock.Anything, task).Return(nil).Once()
+
+	result, err := taskUseCase.UnshareTask(context.Background(), task.ID, ownerID, false, editorID)
+	assert.NoError(t, err)
+	assert.Empty(t, result.SharedWith)
+	assert.Empty(t, result.SharedEditors)
+
+	_, err = taskUseCase.GetTaskByID(context.Background(), task.ID, editorID, false)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	_, err = taskUseCase.UnshareTask(context.Background(), task.ID, ownerID, false, editorID)
+	assert.NoError(t, err, "removing a missing share is a no-op")
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestToggleChecklistItem tests flipping an item's done state
+func TestToggleChecklistItem(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Checklist: []domain.ChecklistItem{{Text: "Draft"}, {Text: "Review", Done: true}},
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockTaskRepo.On("UpdateFields", mock.Anything, task.ID, map[string]interface{}{"checklist.0.done": true}).Return(nil).Once()
+	mockTaskRepo.On("UpdateFields", mock.Anything, task.ID, map[string]interface{}{"checklist.1.done": false}).Return(nil).Once()
+
+	result, err := taskUseCase.ToggleChecklistItem(context.Background(), task.ID, userID, false, 0)
+	assert.NoError(t, err)
+	assert.True(t, result.Checklist[0].Done)
+	assert.True(t, result.Checklist[1].Done)
+
+	result, err = taskUseCase.ToggleChecklistItem(context.Background(), task.ID, userID, false, 1)
+	assert.NoError(t, err)
+	assert.False(t, result.Checklist[1].Done)
+	mockTaskRepo.AssertExpectations(t)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestToggleChecklistItem_SharedEditor tests that the users who may edit a
+// task can tick off its checklist, and those who may only read it cannot
+func TestToggleChecklistItem_SharedEditor(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	editorID, readerID := primitive.NewObjectID(), primitive.NewObjectID()
+	task := &domain.Task{
+		ID:            primitive.NewObjectID(),
+		UserID:        primitive.NewObjectID(),
+		SharedWith:    []primitive.ObjectID{editorID, readerID},
+		SharedEditors: []primitive.ObjectID{editorID},
+		Checklist:     []domain.ChecklistItem{{Text: "Draft"}},
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockTaskRepo.On("UpdateFields", mock.Anything, task.ID, map[string]interface{}{"checklist.0.done": true}).Return(nil).Once()
+
+	result, err := taskUseCase.ToggleChecklistItem(context.Background(), task.ID, editorID, false, 0)
+	assert.NoError(t, err)
+	assert.True(t, result.Checklist[0].Done)
+
+	_, err = taskUseCase.ToggleChecklistItem(context.Background(), task.ID, readerID, false, 0)
+	assert.ErrorIs(t, err, domain.ErrNotTaskOwner)
+	_, err = taskUseCase.ToggleChecklistItem(context.Background(), task.ID, primitive.NewObjectID(), false, 0)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestToggleChecklistItem_OutOfRange tests rejecting indices outside the checklist
+func TestToggleChecklistItem_OutOfRange(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: userID, Checklist: []domain.ChecklistItem{{Text: "Only"}}}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+
+	for _, index := range []int{-1, 1} {
+		_, err := taskUseCase.ToggleChecklistItem(context.Background(), task.ID, userID, false, index)
+		assert.ErrorIs(t, err, domain.ErrChecklistIndexOutOfRange)
+	}
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestGetTagsByUserID tests that tags come back sorted
+func TestGetTagsByUserID(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	mockTaskRepo.On("DistinctTags", mock.Anything, userID).Return([]string{"work", "errands", "home"}, nil)
+
+	tags, err := taskUseCase.GetTagsByUserID(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"errands", "home", "work"}, tags)
+}
+
+// TestGetTasksByUserID_IsOverdue tests that is_overdue is computed from the due date and status
+func TestGetTasksByUserID_IsOverdue(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)))
+
+	userID := primitive.NewObjectID()
+	overdue := &domain.Task{Title: "Overdue", UserID: userID, Status: domain.StatusInProgress, DueDate: now.Add(-time.Minute)}
+	completed := &domain.Task{Title: "Completed late", UserID: userID, Status: domain.StatusCompleted, DueDate: now.Add(-time.Hour)}
+	future := &domain.Task{Title: "Future", UserID: userID, Status: domain.StatusPending, DueDate: now.Add(time.Hour)}
+	undated := &domain.Task{Title: "Undated", UserID: userID, Status: domain.StatusPending}
+	mockTaskRepo.On("GetByUserID", mock.Anything, userID, domain.TaskFilter{}).
+		Return([]*domain.Task{overdue, completed, future, undated}, nil)
+
+	tasks, err := taskUseCase.GetTasksByUserID(context.Background(), userID, domain.TaskFilter{})
+
+	assert.NoError(t, err)
+	assert.True(t, tasks[0].IsOverdue)
+	assert.False(t, tasks[1].IsOverdue)
+	assert.False(t, tasks[2].IsOverdue)
+	assert.False(t, tasks[3].IsOverdue)
+}
+
+// TestGetTaskByID_IsOverdue tests that a single task read is marked overdue
+func TestGetTaskByID_IsOverdue(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)))
+
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: domain.StatusPending, DueDate: now.Add(-24 * time.Hour)}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+
+	result, err := taskUseCase.GetTaskByID(context.Background(), task.ID, task.UserID, false)
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsOverdue)
+}
+
+// TestForceCompleteTask_ClearsIsOverdue tests that completing an overdue task reports it as no longer overdue
+func TestForceCompleteTask_ClearsIsOverdue(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockAuditRepo := new(MockAuditRepository)
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskAuditRepository(mockAuditRepo), WithClock(infrastructure.NewFakeClock(now)))
+
+	task := &domain.Task{ID: primitive.NewObjectID(), Status: domain.StatusPending, DueDate: now.Add(-time.Hour), IsOverdue: true}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockTaskRepo.On("UpdateFields", mock.Anything, task.ID, map[string]interface{}{
+		"status":       domain.StatusCompleted,
+		"completed_at": &now,
+	}).Return(nil)
+
+	result, err := taskUseCase.ForceCompleteTask(context.Background(), task.ID, primitive.NewObjectID())
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsOverdue)
+}
+
+// TestGetAllTasksWithOwners tests that owners are fetched in a single batch
+func TestGetAllTasksWithOwners(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	ownerID := primitive.NewObjectID()
+	goneID := primitive.NewObjectID()
+	tasks := []*domain.Task{
+		{Title: "Task 1", UserID: ownerID},
+		{Title: "Task 2", UserID: ownerID},
+		{Title: "Task 3", UserID: goneID},
+	}
+	mockTaskRepo.On("GetAll", mock.Anything, domain.Pagination{}).Return(tasks, nil)
+	mockUserRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{ownerID, goneID}).
+		Return([]*domain.User{{ID: ownerID, Name: "John Doe", Email: "john@example.com"}}, nil).Once()
+
+	result, err := taskUseCase.GetAllTasksWithOwners(context.Background(), domain.Pagination{})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "Task 1", result[0].Title)
+	assert.Equal(t, &domain.TaskOwner{ID: ownerID, Name: "John Doe", Email: "john@example.com"}, result[0].User)
+	assert.Equal(t, result[0].User, result[1].User)
+	assert.Nil(t, result[2].User)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestGetAllTasksWithOwners_NoUserRepository tests the missing dependency error
+func TestGetAllTasksWithOwners_NoUserRepository(t *testing.T) {
+	taskUseCase := NewTaskUseCase(new(MockTaskRepository))
+
+	_, err := taskUseCase.GetAllTasksWithOwners(context.Background(), domain.Pagination{})
+	assert.Error(t, err)
+}
+
+// TestGetUsersWithOverdueTasks tests that overdue counts are joined with
+// their users in one batch, skipping users that no longer exist
+func TestGetUsersWithOverdueTasks(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo), WithClock(infrastructure.NewFakeClock(now)))
+
+	lateID := primitive.NewObjectID()
+	goneID := primitive.NewObjectID()
+	slightlyLateID := primitive.NewObjectID()
+	mockTaskRepo.On("CountOverdueByUser", mock.Anything, now).Return([]domain.OverdueCount{
+		{UserID: lateID, Count: 3},
+		{UserID: goneID, Count: 2},
+		{UserID: slightlyLateID, Count: 1},
+	}, nil)
+	mockUserRepo.On("GetByIDs", mock.Anything, []primitive.ObjectID{lateID, goneID, slightlyLateID}).Return([]*domain.User{
+		{ID: slightlyLateID, Name: "Jane Doe", Email: "jane@example.com"},
+		{ID: lateID, Name: "John Doe", Email: "john@example.com"},
+	}, nil).Once()
+
+	users, err := taskUseCase.GetUsersWithOverdueTasks(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.UserOverdue{
+		{TaskOwner: domain.TaskOwner{ID: lateID, Name: "John Doe", Email: "john@example.com"}, OverdueTasks: 3},
+		{TaskOwner: domain.TaskOwner{ID: slightlyLateID, Name: "Jane Doe", Email: "jane@example.com"}, OverdueTasks: 1},
+	}, users)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestGetUsersWithOverdueTasks_None tests that no users are looked up when nothing is overdue
+func TestGetUsersWithOverdueTasks_None(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+
+	mockTaskRepo.On("CountOverdueByUser", mock.Anything, mock.Anything).Return([]domain.OverdueCount{}, nil)
+
+	users, err := taskUseCase.GetUsersWithOverdueTasks(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+	assert.NotNil(t, users)
+	mockUserRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+// TestDeleteTask_Idempotent tests that deleting the same task twice succeeds both times
+func TestDeleteTask_Idempotent(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusPending}, nil).Once()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return((*domain.Task)(nil), nil).Once()
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(nil).Once()
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(domain.ErrTaskNotFound).Once()
+
+	assert.NoError(t, taskUseCase.DeleteTask(context.Background(), taskID, ownerID, false, false))
+	assert.NoError(t, taskUseCase.DeleteTask(context.Background(), taskID, ownerID, false, false))
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestDeleteTask_Strict tests that strict mode reports a missing task
+func TestDeleteTask_Strict(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithStrictDelete(true))
+
+	taskID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return((*domain.Task)(nil), nil)
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(domain.ErrTaskNotFound)
+
+	err := taskUseCase.DeleteTask(context.Background(), taskID, primitive.NewObjectID(), false, false)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+// TestDeleteTask_RepositoryError tests that other errors are never swallowed
+func TestDeleteTask_RepositoryError(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID}, nil)
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(errors.New("repository error"))
+
+	err := taskUseCase.DeleteTask(context.Background(), taskID, ownerID, false, true)
+	assert.EqualError(t, err, "repository error")
+}
+
+// TestDeleteTask_InProgressBlocked tests that an in-progress task is kept without force
+func TestDeleteTask_InProgressBlocked(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusInProgress}, nil)
+
+	err := taskUseCase.DeleteTask(context.Background(), taskID, ownerID, false, false)
+	assert.ErrorIs(t, err, domain.ErrTaskInProgress)
+	mockTaskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// TestDeleteTask_InProgressForced tests that force deletes an in-progress task
+func TestDeleteTask_InProgressForced(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusInProgress}, nil)
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(nil)
+
+	err := taskUseCase.DeleteTask(context.Background(), taskID, ownerID, false, true)
+	assert.NoError(t, err)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestDeleteTask_Pending tests that a pending task is deleted without force
+func TestDeleteTask_Pending(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusPending}, nil)
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(nil)
+
+	err := taskUseCase.DeleteTask(context.Background(), taskID, ownerID, false, false)
+	assert.NoError(t, err)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestTaskAccess_StrictMode tests that other users' tasks are hidden when shared mode is off
+func TestTaskAccess_StrictMode(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Title: "Mine", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}, nil)
+
+	_, err := taskUseCase.GetTaskByID(context.Background(), taskID, otherID, false)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	update := &domain.Task{ID: taskID, Title: "Theirs", DueDate: time.Now().Add(time.Hour)}
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), update, otherID, false), domain.ErrTaskNotFound)
+	assert.ErrorIs(t, taskUseCase.DeleteTask(context.Background(), taskID, otherID, false, false), domain.ErrTaskNotFound)
+
+	task, err := taskUseCase.GetTaskByID(context.Background(), taskID, ownerID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mine", task.Title)
+
+	_, err = taskUseCase.GetSharedTasks(context.Background(), domain.Pagination{})
+	assert.ErrorIs(t, err, domain.ErrSharedTasksDisabled)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockTaskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockTaskRepo.AssertNotCalled(t, "GetAllVisible", mock.Anything, mock.Anything)
+}
+
+// TestTaskAccess_SharedMode tests that other users may read but not change a task in shared mode
+func TestTaskAccess_SharedMode(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithSharedTasks(true))
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	stored := &domain.Task{ID: taskID, UserID: ownerID, Title: "Mine", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(stored, nil)
+	mockTaskRepo.On("GetAllVisible", mock.Anything, domain.Pagination{Page: 1, PageSize: 10}).Return([]*domain.Task{stored}, nil)
+
+	task, err := taskUseCase.GetTaskByID(context.Background(), taskID, otherID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mine", task.Title)
+
+	tasks, err := taskUseCase.GetSharedTasks(context.Background(), domain.Pagination{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	update := &domain.Task{ID: taskID, Title: "Theirs", DueDate: time.Now().Add(time.Hour)}
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), update, otherID, false), domain.ErrNotTaskOwner)
+	assert.ErrorIs(t, taskUseCase.DeleteTask(context.Background(), taskID, otherID, false, false), domain.ErrNotTaskOwner)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockTaskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// TestTaskAccess_OwnerDeleted tests that the tasks of a deleted account are
+// hidden from the users they are shared with, but not from admins
+func TestTaskAccess_OwnerDeleted(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithSharedTasks(true))
+
+	taskID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	stored := &domain.Task{
+		ID:            taskID,
+		UserID:        primitive.NewObjectID(),
+		Title:         "Orphaned",
+		Status:        domain.StatusPending,
+		SharedWith:    []primitive.ObjectID{editorID},
+		SharedEditors: []primitive.ObjectID{editorID},
+		OwnerDeleted:  true,
+	}
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(stored, nil)
+
+	_, err := taskUseCase.GetTaskByID(context.Background(), taskID, editorID, false)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	update := &domain.Task{ID: taskID, Title: "Edited", DueDate: time.Now().Add(time.Hour)}
+	assert.ErrorIs(t, taskUseCase.UpdateTask(context.Background(), update, editorID, false), domain.ErrTaskNotFound)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	task, err := taskUseCase.GetTaskByID(context.Background(), taskID, primitive.NewObjectID(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Orphaned", task.Title)
+}
+
+// TestTaskAccess_Admin tests that admins may change any user's task
+func TestTaskAccess_Admin(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	adminID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusPending}, nil)
+	update := &domain.Task{ID: taskID, Title: "Fixed", DueDate: time.Now().Add(time.Hour)}
+	mockTaskRepo.On("Update", mock.Anything, update).Return(nil)
+	mockTaskRepo.On("Delete", mock.Anything, taskID).Return(nil)
+
+	assert.NoError(t, taskUseCase.UpdateTask(context.Background(), update, adminID, true))
+	assert.Equal(t, ownerID, update.UserID)
+	assert.NoError(t, taskUseCase.DeleteTask(context.Background(), taskID, adminID, true, false))
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestTaskUseCase_ZeroID tests that the zero ObjectID is rejected before reaching the repository
+func TestTaskUseCase_ZeroID(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	mockUserRepo := new(MockUserRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithUserRepository(mockUserRepo))
+	ctx := context.Background()
+	callerID := primitive.NewObjectID()
+
+	_, err := taskUseCase.GetTaskByID(ctx, primitive.NilObjectID, callerID, false)
+	assert.ErrorIs(t, err, domain.ErrInvalidID)
+	update := &domain.Task{Title: "No ID", DueDate: time.Now().Add(time.Hour)}
+	assert.ErrorIs(t, taskUseCase.UpdateTask(ctx, update, callerID, false), domain.ErrInvalidID)
+	assert.ErrorIs(t, taskUseCase.DeleteTask(ctx, primitive.NilObjectID, callerID, false, true), domain.ErrInvalidID)
+	_, err = taskUseCase.CloneTask(ctx, primitive.NilObjectID, callerID, domain.CloneTaskRequest{})
+	assert.ErrorIs(t, err, domain.ErrInvalidID)
+	_, err = taskUseCase.TransferTask(ctx, primitive.NewObjectID(), callerID, false, primitive.NilObjectID)
+	assert.ErrorIs(t, err, domain.ErrInvalidID)
+	_, err = taskUseCase.ToggleChecklistItem(ctx, primitive.NilObjectID, callerID, false, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidID)
+	mockTaskRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	mockTaskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// TestDeleteCompletedTasks tests that the deleted count is passed through
+func TestDeleteCompletedTasks(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	userID := primitive.NewObjectID()
+	mockTaskRepo.On("DeleteCompletedByUserID", mock.Anything, userID).Return(int64(2), nil)
+
+	deleted, err := taskUseCase.DeleteCompletedTasks(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
+
+// TestUpdateTask_StatusRegression tests that moving an in-progress task back to pending is rejected when the rule is on
+func TestUpdateTask_StatusRegression(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithNoStatusRegression(true))
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusInProgress}, nil)
+
+	update := &domain.Task{ID: taskID, Title: "Started", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	err := taskUseCase.UpdateTask(context.Background(), update, ownerID, false)
+
+	assert.ErrorIs(t, err, domain.ErrStatusRegression)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_StatusRegressionAllowed tests that the move is allowed when the rule is off
+func TestUpdateTask_StatusRegressionAllowed(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithNoStatusRegression(false))
+
+	taskID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: ownerID, Status: domain.StatusInProgress}, nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	update := &domain.Task{ID: taskID, Title: "Started", Status: domain.StatusPending, DueDate: time.Now().Add(time.Hour)}
+	err := taskUseCase.UpdateTask(context.Background(), update, ownerID, false)
+
+	assert.NoError(t, err)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+// TestForceCompleteTask tests that an admin completes another user's pending task and the override is audited
+func TestForceCompleteTask(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	auditRepo := new(MockAuditRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskAuditRepository(auditRepo))
+
+	adminID := primitive.NewObjectID()
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: domain.StatusPending}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	mockTaskRepo.On("UpdateFields", mock.Anything, task.ID, mock.Anything).Return(nil).Once()
+	auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+		return entry.Action == domain.AuditTaskForceCompleted && entry.ActorID == adminID && entry.TargetID == task.ID
+	})).Return(nil).Once()
+
+	result, err := taskUseCase.ForceCompleteTask(context.Background(), task.ID, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+	assert.NotNil(t, result.CompletedAt)
+	mockTaskRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+}
+
+// TestForceCompleteTask_AuditFailure tests that the task is left alone when the override cannot be audited
+func TestForceCompleteTask_AuditFailure(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	auditRepo := new(MockAuditRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTaskAuditRepository(auditRepo))
+
+	task := &domain.Task{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: domain.StatusInProgress}
+	mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("write failed"))
+
+	_, err := taskUseCase.ForceCompleteTask(context.Background(), task.ID, primitive.NewObjectID())
+
+	assert.Error(t, err)
+	mockTaskRepo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_NegativeMinutes tests that negative effort estimates or actuals are rejected
+func TestCreateTask_NegativeMinutes(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	for _, task := range []*domain.Task{
+		{Title: "Estimate", DueDate: time.Now().Add(time.Hour), EstimatedMinutes: -1},
+		{Title: "Actual", DueDate: time.Now().Add(time.Hour), ActualMinutes: -30},
+	} {
+		_, err := taskUseCase.CreateTask(context.Background(), task)
+		assert.ErrorIs(t, err, domain.ErrNegativeMinutes)
+	}
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTask_NegativeMinutes tests that an update cannot set negative actual minutes
+func TestUpdateTask_NegativeMinutes(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	update := &domain.Task{ID: primitive.NewObjectID(), Title: "Logged", DueDate: time.Now().Add(time.Hour), EstimatedMinutes: 60, ActualMinutes: -5}
+	err := taskUseCase.UpdateTask(context.Background(), update, primitive.NewObjectID(), false)
+
+	assert.ErrorIs(t, err, domain.ErrNegativeMinutes)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_TagLimits tests the tag count and tag length boundaries
+func TestCreateTask_TagLimits(t *testing.T) {
+	tags := func(n int) []string {
+		tags := make([]string, n)
+		for i := range tags {
+			tags[i] = fmt.Sprintf("tag-%d", i)
+		}
+		return tags
+	}
+	tests := []struct {
+		name string
+		tags []string
+		err  error
+	}{
+		{name: "at the tag limit", tags: tags(3)},
+		{name: "over the tag limit", tags: tags(4), err: domain.ErrTooManyTags},
+		{name: "at the length limit", tags: []string{"abcde"}},
+		{name: "at the length limit in characters", tags: []string{"ünïcø"}},
+		{name: "over the length limit", tags: []string{"abcdef"}, err: domain.ErrTagTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTaskRepo := new(MockTaskRepository)
+			taskUseCase := NewTaskUseCase(mockTaskRepo, WithTagLimits(3, 5))
+
+			task := &domain.Task{Title: "Tagged", DueDate: time.Now().Add(time.Hour), Tags: tt.tags}
+			mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil).Maybe()
+
+			_, err := taskUseCase.CreateTask(context.Background(), task)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCreateTask_DefaultTagLimits tests the default of ten tags per task
+func TestCreateTask_DefaultTagLimits(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	task := &domain.Task{Title: "Tagged", DueDate: time.Now().Add(time.Hour)}
+	for i := 0; i < 11; i++ {
+		task.Tags = append(task.Tags, fmt.Sprintf("tag-%d", i))
+	}
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.ErrorIs(t, err, domain.ErrTooManyTags)
+	assert.EqualError(t, err, "too many tags: at most 10 allowed")
+}
+
+// TestUpdateTask_TagLimits tests that an update cannot exceed the tag limits
+func TestUpdateTask_TagLimits(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithTagLimits(2, 5))
+
+	update := &domain.Task{ID: primitive.NewObjectID(), Title: "Tagged", DueDate: time.Now().Add(time.Hour), Tags: []string{"a", "b", "c"}}
+	err := taskUseCase.UpdateTask(context.Background(), update, primitive.NewObjectID(), false)
+	assert.ErrorIs(t, err, domain.ErrTooManyTags)
+
+	update.Tags = []string{"home", "errands"}
+	err = taskUseCase.UpdateTask(context.Background(), update, primitive.NewObjectID(), false)
+	assert.ErrorIs(t, err, domain.ErrTagTooLong)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_MetadataLimits tests the metadata key count, key format and length boundaries
+func TestCreateTask_MetadataLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		err      error
+	}{
+		{name: "at the key limit", metadata: map[string]string{"team": "ops", "cost": "42"}},
+		{name: "over the key limit", metadata: map[string]string{"team": "ops", "cost": "42", "region": "eu"}, err: domain.ErrTooManyMetadataKeys},
+		{name: "at the key length limit", metadata: map[string]string{"abcdef": "x"}},
+		{name: "over the key length limit", metadata: map[string]string{"abcdefg": "x"}, err: domain.ErrMetadataTooLong},
+		{name: "at the value length limit in characters", metadata: map[string]string{"team": "ünïcødé!"}},
+		{name: "over the value length limit", metadata: map[string]string{"team": "operations"}, err: domain.ErrMetadataTooLong},
+		{name: "key with a dot", metadata: map[string]string{"a.b": "x"}, err: domain.ErrInvalidMetadataKey},
+		{name: "operator key", metadata: map[string]string{"$where": "x"}, err: domain.ErrInvalidMetadataKey},
+		{name: "empty key", metadata: map[string]string{"": "x"}, err: domain.ErrInvalidMetadataKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTaskRepo := new(MockTaskRepository)
+			taskUseCase := NewTaskUseCase(mockTaskRepo, WithMetadataLimits(2, 6, 8))
+
+			task := &domain.Task{Title: "Custom", DueDate: time.Now().Add(time.Hour), Metadata: tt.metadata}
+			mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil).Maybe()
+
+			_, err := taskUseCase.CreateTask(context.Background(), task)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestUpdateTask_MetadataLimits tests that an update cannot exceed the metadata limits
+func TestUpdateTask_MetadataLimits(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithMetadataLimits(1, 10, 10))
+
+	update := &domain.Task{ID: primitive.NewObjectID(), Title: "Custom", DueDate: time.Now().Add(time.Hour), Metadata: map[string]string{"team": "ops", "region": "eu"}}
+	err := taskUseCase.UpdateTask(context.Background(), update, primitive.NewObjectID(), false)
+
+	assert.ErrorIs(t, err, domain.ErrTooManyMetadataKeys)
+	assert.EqualError(t, err, "too many metadata keys: at most 1 allowed")
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestCreateTask_ZeroMinutes tests that zero minutes, meaning not tracked, are accepted
+func TestCreateTask_ZeroMinutes(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo)
+
+	task := &domain.Task{Title: "Untracked", DueDate: time.Now().Add(time.Hour), EstimatedMinutes: 45}
+	mockTaskRepo.On("Create", mock.Anything, task).Return(task, nil)
+
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+	assert.NoError(t, err)
+}
+
+// TestGetAgenda tests that tasks land in the right bucket around day and week boundaries in the configured timezone
+func TestGetAgenda(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	// Wednesday 2024-05-15 22:00 in New York, already Thursday in UTC
+	now := time.Date(2024, 5, 15, 22, 0, 0, 0, newYork)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now.UTC())), WithLocation(newYork))
+
+	userID := primitive.NewObjectID()
+	overdue := &domain.Task{Title: "Overdue", DueDate: now.Add(-time.Minute)}
+	today := &domain.Task{Title: "Late tonight", DueDate: time.Date(2024, 5, 15, 23, 59, 0, 0, newYork)}
+	tomorrow := &domain.Task{Title: "Tomorrow", DueDate: time.Date(2024, 5, 16, 0, 0, 0, 0, newYork)}
+	sunday := &domain.Task{Title: "Sunday night", DueDate: time.Date(2024, 5, 19, 23, 59, 0, 0, newYork)}
+	monday := &domain.Task{Title: "Next Monday", DueDate: time.Date(2024, 5, 20, 0, 0, 0, 0, newYork)}
+	mockTaskRepo.On("GetUpcoming", mock.Anything, userID, int64(0)).Return([]*domain.Task{overdue, today, tomorrow, sunday, monday}, nil)
+
+	agenda, err := taskUseCase.GetAgenda(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Task{overdue}, agenda.Overdue)
+	assert.Equal(t, []*domain.Task{today}, agenda.Today)
+	assert.Equal(t, []*domain.Task{tomorrow, sunday}, agenda.ThisWeek)
+	assert.Equal(t, []*domain.Task{monday}, agenda.Later)
+}
+
+// TestGetAgenda_Sunday tests that on Sunday the week has no days left after today
+func TestGetAgenda_Sunday(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	now := time.Date(2024, 5, 19, 9, 0, 0, 0, time.UTC)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)))
+
+	userID := primitive.NewObjectID()
+	tonight := &domain.Task{Title: "Tonight", DueDate: now.Add(12 * time.Hour)}
+	monday := &domain.Task{Title: "Monday", DueDate: now.Add(24 * time.Hour)}
+	mockTaskRepo.On("GetUpcoming", mock.Anything, userID, int64(0)).Return([]*domain.Task{tonight, monday}, nil)
+
+	agenda, err := taskUseCase.GetAgenda(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Empty(t, agenda.Overdue)
+	assert.Equal(t, []*domain.Task{tonight}, agenda.Today)
+	assert.Empty(t, agenda.ThisWeek)
+	assert.Equal(t, []*domain.Task{monday}, agenda.Later)
+}
+
+// TestCreateTask_DefaultDueDate tests that a task sent without a due date gets the configured default
+func TestCreateTask_DefaultDueDate(t *testing.T) {
+	now := time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		opts []TaskUseCaseOption
+		want time.Time
+	}{
+		"in seven days":   {opts: []TaskUseCaseOption{WithDefaultDueDate(7*24*time.Hour, false)}, want: now.AddDate(0, 0, 7)},
+		"end of today":    {opts: []TaskUseCaseOption{WithDefaultDueDate(0, true), WithLocation(newYork)}, want: time.Date(2024, 5, 15, 23, 59, 59, 999999999, newYork)},
+		"end of tomorrow": {opts: []TaskUseCaseOption{WithDefaultDueDate(24*time.Hour, true)}, want: time.Date(2024, 5, 16, 23, 59, 59, 999999999, time.UTC)},
+	} {
+		mockTaskRepo := new(MockTaskRepository)
+		taskUseCase := NewTaskUseCase(mockTaskRepo, append(tc.opts, WithClock(infrastructure.NewFakeClock(now)))...)
+		mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+		task := &domain.Task{Title: "No date"}
+		_, err := taskUseCase.CreateTask(context.Background(), task)
+
+		assert.NoError(t, err, name)
+		assert.True(t, task.DueDate.Equal(tc.want), "%s: got %v", name, task.DueDate)
+	}
+}
+
+// TestCreateTask_ExplicitDueDateOverridesDefault tests that a due date sent by the client is kept
+func TestCreateTask_ExplicitDueDateOverridesDefault(t *testing.T) {
+	now := time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC)
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithClock(infrastructure.NewFakeClock(now)), WithDefaultDueDate(7*24*time.Hour, true))
+	mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+	explicit := now.Add(2 * time.Hour)
+	task := &domain.Task{Title: "Dated", DueDate: explicit}
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	assert.Equal(t, explicit, task.DueDate)
+}
+
+// TestCreateTask_AutoAssign tests that the creator becomes the assignee only while the policy is on
+func TestCreateTask_AutoAssign(t *testing.T) {
+	creatorID := primitive.NewObjectID()
+	for _, enabled := range []bool{true, false} {
+		mockTaskRepo := new(MockTaskRepository)
+		taskUseCase := NewTaskUseCase(mockTaskRepo, WithAutoAssign(enabled))
+		mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+		task := &domain.Task{Title: "Triage", UserID: creatorID, DueDate: time.Now().Add(time.Hour)}
+		_, err := taskUseCase.CreateTask(context.Background(), task)
+
+		assert.NoError(t, err)
+		if enabled {
+			assert.Equal(t, &creatorID, task.AssigneeID)
+		} else {
+			assert.Nil(t, task.AssigneeID)
+		}
+	}
+}
+
+// TestCreateTask_AutoAssignKeepsAssignee tests that an assignee sent by the client is kept
+func TestCreateTask_AutoAssignKeepsAssignee(t *testing.T) {
+	mockTaskRepo := new(MockTaskRepository)
+	taskUseCase := NewTaskUseCase(mockTaskRepo, WithAutoAssign(true))
+	mockTaskRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+	assigneeID := primitive.NewObjectID()
+	task := &domain.Task{Title: "Delegated", UserID: primitive.NewObjectID(), AssigneeID: &assigneeID, DueDate: time.Now().Add(time.Hour)}
+	_, err := taskUseCase.CreateTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &assigneeID, task.AssigneeID)
+}
+
 // Run the test suite
 func TestTaskUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(TaskUseCaseTestSuite))
@@ -5,6 +5,8 @@ import (
 	infrastructure "Task-Management/Infrastructure"
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,19 +15,112 @@ type userUseCase struct {
 	userRepo         domain.UserRepository
 	hashPassword     func(string) (string, error)
 	comparePasswords func(string, string) bool
-	generateToken    func(string, string) (string, error)
+	needsRehash      func(string) bool
+	generateToken    func(string, string, int, bool) (string, error)
+	impersonateToken func(string, string, int, string) (string, error)
+	confirmToken     func(string) (string, error)
+	passwordHistory  int
+	inviteRepo       domain.InviteRepository
+	inviteOnly       bool
+	generateInvite   func() (string, error)
+	auditRepo        domain.AuditRepository
+	taskRepo         domain.TaskRepository
+	deletionGrace    time.Duration
+	location         *time.Location
 }
 
-func NewUserUseCase(userRepo domain.UserRepository) domain.UserUseCase {
-	return &userUseCase{
+// UserUseCaseOption customizes the user use case
+type UserUseCaseOption func(*userUseCase)
+
+// WithPasswordHistory rejects a new password that matches the current one or
+// any of the previous n, and keeps that many old hashes per user. Zero or a
+// negative value disables the check.
+func WithPasswordHistory(n int) UserUseCaseOption {
+	return func(u *userUseCase) {
+		u.passwordHistory = n
+	}
+}
+
+// WithInviteRepository stores the invites admins create with CreateInvite
+func WithInviteRepository(inviteRepo domain.InviteRepository) UserUseCaseOption {
+	return func(u *userUseCase) {
+		u.inviteRepo = inviteRepo
+	}
+}
+
+// WithInviteOnly makes Register require an unused invite code, which is
+// consumed by the registration. It needs WithInviteRepository. When off,
+// registration is open and any invite code is ignored.
+func WithInviteOnly(inviteOnly bool) UserUseCaseOption {
+	return func(u *userUseCase) {
+		u.inviteOnly = inviteOnly
+	}
+}
+
+// WithAuditRepository records sensitive actions such as Impersonate
+func WithAuditRepository(auditRepo domain.AuditRepository) UserUseCaseOption {
+	return func(u *userUseCase) {
+		u.auditRepo = auditRepo
+	}
+}
+
+// WithTaskRepository lets DeleteUser and RestoreUser hide and show the
+// tasks of the accounts they change, PurgeDeletedUsers remove the tasks of
+// the accounts it purges, and ExportUserData include a user's tasks
+func WithTaskRepository(taskRepo domain.TaskRepository) UserUseCaseOption {
+	return func(u *userUseCase) {
+		u.taskRepo = taskRepo
+	}
+}
+
+// WithUserLocation sets the timezone used to resolve calendar days, such as
+// the created_from and created_to filters. Defaults to UTC.
+func WithUserLocation(loc *time.Location) UserUseCaseOption {
+	return func(u *userUseCase) {
+		if loc != nil {
+			u.location = loc
+		}
+	}
+}
+
+// WithDeletionGracePeriod sets how long a deleted account can still be
+// restored before PurgeDeletedUsers removes it for good. With zero, deleted
+// accounts cannot be restored and are purged on the next run.
+func WithDeletionGracePeriod(grace time.Duration) UserUseCaseOption {
+	return func(u *userUseCase) {
+		if grace >= 0 {
+			u.deletionGrace = grace
+		}
+	}
+}
+
+func NewUserUseCase(userRepo domain.UserRepository, opts ...UserUseCaseOption) domain.UserUseCase {
+	u := &userUseCase{
 		userRepo:         userRepo,
 		hashPassword:     infrastructure.HashPassword,     // Default implementation
 		comparePasswords: infrastructure.ComparePasswords, // Default implementation
+		needsRehash:      infrastructure.NeedsRehash,      // Default implementation
 		generateToken:    infrastructure.GenerateToken,    // Default implementation
+		impersonateToken: infrastructure.GenerateImpersonationToken,
+		confirmToken:     infrastructure.GenerateConfirmationToken,
+		generateInvite:   infrastructure.GenerateInviteCode,
+		location:         time.UTC,
+	}
+	for _, opt := range opts {
+		opt(u)
 	}
+	return u
 }
 
-func (u *userUseCase) Register(ctx context.Context, user *domain.User) (*domain.User, error) {
+// Register creates a self-registered user. While registration is
+// invite-only, inviteCode must name an unused invite. It is redeemed before
+// the user is created, so two registrations cannot share it, and released
+// again if the user cannot be created.
+func (u *userUseCase) Register(ctx context.Context, user *domain.User, inviteCode string) (created *domain.User, err error) {
+	if u.inviteOnly && inviteCode == "" {
+		return nil, domain.ErrInviteRequired
+	}
+
 	existingUser, err := u.userRepo.GetByEmail(ctx, user.Email)
 	if err != nil && err.Error() != "user not found" { // Adjust error check
 		return nil, err
@@ -34,6 +129,22 @@ func (u *userUseCase) Register(ctx context.Context, user *domain.User) (*domain.
 		return nil, errors.New("user already exists")
 	}
 
+	if u.inviteOnly {
+		if u.inviteRepo == nil {
+			return nil, errors.New("invite repository is not configured")
+		}
+		if err := u.inviteRepo.Redeem(ctx, inviteCode, user.Email); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				if releaseErr := u.inviteRepo.Release(ctx, inviteCode, user.Email); releaseErr != nil {
+					err = errors.Join(err, releaseErr)
+				}
+			}
+		}()
+	}
+
 	hashedPassword, err := u.hashPassword(user.Password)
 	if err != nil {
 		return nil, err
@@ -43,17 +154,90 @@ func (u *userUseCase) Register(ctx context.Context, user *domain.User) (*domain.
 	return u.userRepo.Create(ctx, user)
 }
 
-func (u *userUseCase) Login(ctx context.Context, email, password string) (*domain.User, string, error) {
+// CreateInvite issues a new single-use registration code on behalf of the
+// admin createdBy
+func (u *userUseCase) CreateInvite(ctx context.Context, createdBy primitive.ObjectID) (*domain.Invite, error) {
+	if u.inviteRepo == nil {
+		return nil, errors.New("invite repository is not configured")
+	}
+
+	code, err := u.generateInvite()
+	if err != nil {
+		return nil, err
+	}
+	return u.inviteRepo.Create(ctx, &domain.Invite{Code: code, CreatedBy: createdBy})
+}
+
+// BulkRegister creates several users at once. Records whose email repeats an
+// earlier record in the batch or an existing user are reported as failed; the
+// rest are inserted together.
+func (u *userUseCase) BulkRegister(ctx context.Context, users []*domain.User) ([]domain.BulkUserResult, error) {
+	results := make([]domain.BulkUserResult, len(users))
+	seen := make(map[string]bool, len(users))
+	var toCreate []*domain.User
+	var toCreateIndex []int
+
+	for i, user := range users {
+		results[i] = domain.BulkUserResult{Index: i, Email: user.Email}
+
+		if seen[user.Email] {
+			results[i].Status = domain.BulkStatusFailed
+			results[i].Error = "duplicate email in batch"
+			continue
+		}
+		seen[user.Email] = true
+
+		existingUser, err := u.userRepo.GetByEmail(ctx, user.Email)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		if existingUser != nil {
+			results[i].Status = domain.BulkStatusFailed
+			results[i].Error = "user already exists"
+			continue
+		}
+
+		hashedPassword, err := u.hashPassword(user.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hashedPassword
+
+		toCreate = append(toCreate, user)
+		toCreateIndex = append(toCreateIndex, i)
+	}
+
+	if len(toCreate) == 0 {
+		return results, nil
+	}
+
+	created, err := u.userRepo.CreateMany(ctx, toCreate)
+	if err != nil {
+		return nil, err
+	}
+	for j, user := range created {
+		results[toCreateIndex[j]].Status = domain.BulkStatusCreated
+		results[toCreateIndex[j]].User = user
+	}
+	return results, nil
+}
+
+func (u *userUseCase) Login(ctx context.Context, email, password string, rememberMe bool) (*domain.User, string, error) {
 	user, err := u.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, "", errors.New("invalid credentials")
 	}
+	// Unknown and soft-deleted accounts are not found
+	if user == nil {
+		return nil, "", errors.New("invalid credentials")
+	}
 
 	if !u.comparePasswords(user.Password, password) {
 		return nil, "", errors.New("invalid credentials")
 	}
+	u.rehashPassword(ctx, user, password)
 
-	token, err := u.generateToken(user.ID.Hex(), user.Role)
+	token, err := u.generateToken(user.ID.Hex(), user.Role, user.TokenVersion, rememberMe)
 	if err != nil {
 		return nil, "", err
 	}
@@ -61,15 +245,70 @@ func (u *userUseCase) Login(ctx context.Context, email, password string) (*domai
 	return user, token, nil
 }
 
-func (u *userUseCase) GetAllUsers(ctx context.Context) ([]*domain.User, error) {
-	return u.userRepo.GetAll(ctx)
+// rehashPassword upgrades a stored hash made with an outdated bcrypt cost,
+// using the plain password the user just logged in with. It is best effort:
+// a failure leaves the old hash in place and does not affect the login.
+func (u *userUseCase) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if u.needsRehash == nil || !u.needsRehash(user.Password) {
+		return
+	}
+	hashedPassword, err := u.hashPassword(password)
+	if err != nil {
+		return
+	}
+	oldPassword := user.Password
+	user.Password = hashedPassword
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		user.Password = oldPassword
+	}
+}
+
+func (u *userUseCase) GetAllUsers(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
+	filter = u.resolveUserFilter(filter)
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedFrom.After(*filter.CreatedTo) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	return u.userRepo.GetAll(ctx, filter)
+}
+
+// resolveUserFilter turns calendar day bounds into created_at bounds in the
+// configured timezone
+func (u *userUseCase) resolveUserFilter(filter domain.UserFilter) domain.UserFilter {
+	loc := u.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if filter.CreatedFromDay != nil {
+		from, _ := dayRange(*filter.CreatedFromDay, loc)
+		filter.CreatedFrom = &from
+	}
+	if filter.CreatedToDay != nil {
+		_, to := dayRange(*filter.CreatedToDay, loc)
+		filter.CreatedTo = &to
+	}
+	return filter
+}
+
+// SearchUsers finds users by part of their name or email, ignoring case
+func (u *userUseCase) SearchUsers(ctx context.Context, query string, page domain.Pagination) ([]*domain.User, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrEmptySearchQuery
+	}
+	return u.userRepo.Search(ctx, query, page)
 }
 
 func (u *userUseCase) GetUserByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+	if err := requireID(id); err != nil {
+		return nil, err
+	}
 	return u.userRepo.GetByID(ctx, id)
 }
 
 func (u *userUseCase) UpdateUser(ctx context.Context, user *domain.User) error {
+	if err := requireID(user.ID); err != nil {
+		return err
+	}
 	if user.Password != "" {
 		hashedPassword, err := u.hashPassword(user.Password)
 		if err != nil {
@@ -81,6 +320,208 @@ func (u *userUseCase) UpdateUser(ctx context.Context, user *domain.User) error {
 	return u.userRepo.Update(ctx, user)
 }
 
+// ChangePassword replaces the user's password after checking the current one
+// and clears any pending MustChangePassword requirement
+func (u *userUseCase) ChangePassword(ctx context.Context, id primitive.ObjectID, currentPassword, newPassword string) error {
+	if err := requireID(id); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+	if !u.comparePasswords(user.Password, currentPassword) {
+		return domain.ErrIncorrectPassword
+	}
+
+	if err := u.replacePassword(user, newPassword); err != nil {
+		return err
+	}
+	user.MustChangePassword = false
+
+	return u.userRepo.Update(ctx, user)
+}
+
+// ResetPassword sets a temporary password chosen by an admin. The user has
+// to change it before making further changes.
+func (u *userUseCase) ResetPassword(ctx context.Context, id primitive.ObjectID, newPassword string) error {
+	if err := requireID(id); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+
+	if err := u.replacePassword(user, newPassword); err != nil {
+		return err
+	}
+	user.MustChangePassword = true
+
+	return u.userRepo.Update(ctx, user)
+}
+
+// replacePassword hashes newPassword into user.Password. With a password
+// history configured it first returns ErrPasswordReused if newPassword
+// matches a remembered hash, then moves the old hash into the history.
+func (u *userUseCase) replacePassword(user *domain.User, newPassword string) error {
+	if u.passwordHistory > 0 {
+		for _, hash := range append([]string{user.Password}, user.PasswordHistory...) {
+			if hash != "" && u.comparePasswords(hash, newPassword) {
+				return domain.ErrPasswordReused
+			}
+		}
+	}
+
+	hashedPassword, err := u.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if u.passwordHistory > 0 && user.Password != "" {
+		history := append([]string{user.Password}, user.PasswordHistory...)
+		if len(history) > u.passwordHistory {
+			history = history[:u.passwordHistory]
+		}
+		user.PasswordHistory = history
+	}
+	user.Password = hashedPassword
+	return nil
+}
+
+// RevokeSessions invalidates every token issued to the user so far. The
+// user has to log in again on every device.
+func (u *userUseCase) RevokeSessions(ctx context.Context, id primitive.ObjectID) error {
+	if err := requireID(id); err != nil {
+		return err
+	}
+	return u.userRepo.IncrementTokenVersion(ctx, id)
+}
+
+// Impersonate issues a short-lived token letting adminID act as userID. The
+// token names the admin, and the action is written to the audit log before
+// the token is handed out.
+func (u *userUseCase) Impersonate(ctx context.Context, adminID, userID primitive.ObjectID) (string, error) {
+	if u.auditRepo == nil {
+		return "", errors.New("audit repository is not configured")
+	}
+	if err := requireID(userID); err != nil {
+		return "", err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", domain.ErrUserNotFound
+	}
+
+	token, err := u.impersonateToken(user.ID.Hex(), user.Role, user.TokenVersion, adminID.Hex())
+	if err != nil {
+		return "", err
+	}
+	entry := &domain.AuditEntry{Action: domain.AuditUserImpersonated, ActorID: adminID, TargetID: userID}
+	if err := u.auditRepo.Create(ctx, entry); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// IssueConfirmation returns a short-lived token the user must present to go
+// ahead with a destructive admin action
+func (u *userUseCase) IssueConfirmation(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	if err := requireID(userID); err != nil {
+		return "", err
+	}
+	return u.confirmToken(userID.Hex())
+}
+
+// DeleteUser soft-deletes user id. The account can no longer log in, and its
+// existing tokens stop working, but an admin can bring it back with
+// RestoreUser until the grace period ends. With WithTaskRepository its tasks
+// are hidden from the users they are shared with or assigned to meanwhile;
+// deleting again finishes an interrupted run.
 func (u *userUseCase) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
-	return u.userRepo.Delete(ctx, id)
+	if err := requireID(id); err != nil {
+		return err
+	}
+	if err := u.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if u.taskRepo == nil {
+		return nil
+	}
+	return u.taskRepo.SetOwnerDeleted(ctx, id, true)
+}
+
+// RestoreUser re-enables user id, deleted within the grace period, and shows
+// its tasks again. Users that are not deleted, or whose grace period is
+// over, are reported as ErrUserNotFound, and users whose email was
+// registered again meanwhile as ErrUserAlreadyExists.
+func (u *userUseCase) RestoreUser(ctx context.Context, id primitive.ObjectID) error {
+	if err := requireID(id); err != nil {
+		return err
+	}
+	if err := u.userRepo.Restore(ctx, id, time.Now().Add(-u.deletionGrace)); err != nil {
+		return err
+	}
+	if u.taskRepo == nil {
+		return nil
+	}
+	return u.taskRepo.SetOwnerDeleted(ctx, id, false)
+}
+
+// PurgeDeletedUsers permanently removes the accounts whose grace period is
+// over, together with their tasks, and returns how many accounts were
+// removed. Tasks go first, so an interrupted run leaves no orphans and is
+// finished by the next one. It requires WithTaskRepository.
+func (u *userUseCase) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	if u.taskRepo == nil {
+		return 0, errors.New("task repository is not configured")
+	}
+
+	ids, err := u.userRepo.ListDeletedBefore(ctx, time.Now().Add(-u.deletionGrace))
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if _, err := u.taskRepo.DeleteByUserIDs(ctx, ids); err != nil {
+		return 0, err
+	}
+	return u.userRepo.Purge(ctx, ids)
+}
+
+// ExportUserData hands user id's profile and then each task they own to
+// exporter. Tasks are passed on as they are read rather than collected
+// first, so large accounts are exported without holding them in memory. It
+// requires WithTaskRepository.
+func (u *userUseCase) ExportUserData(ctx context.Context, id primitive.ObjectID, exporter domain.UserDataExporter) error {
+	if u.taskRepo == nil {
+		return errors.New("task repository is not configured")
+	}
+	if err := requireID(id); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+	if err := exporter.WriteProfile(user); err != nil {
+		return err
+	}
+	return u.taskRepo.ForEachByUserID(ctx, id, exporter.WriteTask)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"Task-Management/Domain"
 
@@ -34,8 +35,24 @@ func (m *MockUserRepository) Create(ctx context.Context, user *Domain.User) (*Do
 	return args.Get(0).(*Domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetAll(ctx context.Context) ([]*Domain.User, error) {
-	args := m.Called(ctx)
+func (m *MockUserRepository) CreateMany(ctx context.Context, users []*Domain.User) ([]*Domain.User, error) {
+	args := m.Called(ctx, users)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetAll(ctx context.Context, filter Domain.UserFilter) ([]*Domain.User, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*Domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, query string, page Domain.Pagination) ([]*Domain.User, error) {
+	args := m.Called(ctx, query, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*Domain.User), args.Error(1)
 }
 
@@ -44,6 +61,14 @@ func (m *MockUserRepository) GetByID(ctx context.Context, id primitive.ObjectID)
 	return args.Get(0).(*Domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*Domain.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.User), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *Domain.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -54,6 +79,62 @@ func (m *MockUserRepository) Delete(ctx context.Context, id primitive.ObjectID)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id primitive.ObjectID, deletedAfter time.Time) error {
+	args := m.Called(ctx, id, deletedAfter)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListDeletedBefore(ctx context.Context, before time.Time) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+func (m *MockUserRepository) Purge(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockAuditRepository is a mock implementation of the AuditRepository interface
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *Domain.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+// MockInviteRepository is a mock implementation of the InviteRepository interface
+type MockInviteRepository struct {
+	mock.Mock
+}
+
+func (m *MockInviteRepository) Create(ctx context.Context, invite *Domain.Invite) (*Domain.Invite, error) {
+	args := m.Called(ctx, invite)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Invite), args.Error(1)
+}
+
+func (m *MockInviteRepository) Redeem(ctx context.Context, code, email string) error {
+	args := m.Called(ctx, code, email)
+	return args.Error(0)
+}
+
+func (m *MockInviteRepository) Release(ctx context.Context, code, email string) error {
+	args := m.Called(ctx, code, email)
+	return args.Error(0)
+}
+
 // GetUserByEmail retrieves a user by email
 func (u *userUseCase) GetUserByEmail(ctx context.Context, email string) (*Domain.User, error) {
 	return u.userRepo.GetByEmail(ctx, email)
@@ -91,7 +172,8 @@ func (suite *UserUseCaseTestSuite) SetupTest() {
 		userRepo:         suite.mockRepo,
 		hashPassword:     suite.mockHashFunc,
 		comparePasswords: func(hashedPassword, plainPassword string) bool { return true },
-		generateToken:    func(userID, role string) (string, error) { return "mockToken", nil },
+		needsRehash:      func(hashedPassword string) bool { return false },
+		generateToken:    func(userID, role string, tokenVersion int, rememberMe bool) (string, error) { return "mockToken", nil },
 	}
 }
 
@@ -143,6 +225,37 @@ func (suite *UserUseCaseTestSuite) TestDeleteUser() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestDeleteUser_HidesTasks tests that the deleted account's tasks are marked
+// so other users no longer see them
+func (suite *UserUseCaseTestSuite) TestDeleteUser_HidesTasks() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("Delete", mock.Anything, userID).Return(nil)
+	taskRepo.On("SetOwnerDeleted", mock.Anything, userID, true).Return(nil)
+
+	err := suite.userUseCase.DeleteUser(context.Background(), userID)
+
+	assert.NoError(suite.T(), err)
+	taskRepo.AssertExpectations(suite.T())
+}
+
+// TestUserUseCase_ZeroID tests that the zero ObjectID is rejected before reaching the repository
+func (suite *UserUseCaseTestSuite) TestUserUseCase_ZeroID() {
+	ctx := context.Background()
+
+	_, err := suite.userUseCase.GetUserByID(ctx, primitive.NilObjectID)
+	assert.ErrorIs(suite.T(), err, Domain.ErrInvalidID)
+	assert.ErrorIs(suite.T(), suite.userUseCase.UpdateUser(ctx, &Domain.User{Name: "No ID"}), Domain.ErrInvalidID)
+	assert.ErrorIs(suite.T(), suite.userUseCase.ChangePassword(ctx, primitive.NilObjectID, "old", "new"), Domain.ErrInvalidID)
+	assert.ErrorIs(suite.T(), suite.userUseCase.ResetPassword(ctx, primitive.NilObjectID, "new"), Domain.ErrInvalidID)
+	assert.ErrorIs(suite.T(), suite.userUseCase.DeleteUser(ctx, primitive.NilObjectID), Domain.ErrInvalidID)
+	assert.ErrorIs(suite.T(), suite.userUseCase.RevokeSessions(ctx, primitive.NilObjectID), Domain.ErrInvalidID)
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything, mock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
 // TestGetUserByEmail tests fetching a user by email successfully
 func (suite *UserUseCaseTestSuite) TestGetUserByEmail() {
 	email := "test@example.com"
@@ -186,15 +299,192 @@ func (suite *UserUseCaseTestSuite) TestGetAllUsers() {
 	}
 
 	// Mock repository behavior
-	suite.mockRepo.On("GetAll", mock.Anything).Return(mockUsers, nil)
+	suite.mockRepo.On("GetAll", mock.Anything, Domain.UserFilter{}).Return(mockUsers, nil)
 
-	results, err := suite.userUseCase.GetAllUsers(context.Background())
+	results, err := suite.userUseCase.GetAllUsers(context.Background(), Domain.UserFilter{})
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, 2)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestSearchUsers tests that the query is trimmed before searching
+func (suite *UserUseCaseTestSuite) TestSearchUsers() {
+	page := Domain.Pagination{Page: 1, PageSize: 20}
+	mockUsers := []*Domain.User{{ID: primitive.NewObjectID(), Name: "John Doe", Email: "john@example.com"}}
+	suite.mockRepo.On("Search", mock.Anything, "john", page).Return(mockUsers, nil)
+
+	results, err := suite.userUseCase.SearchUsers(context.Background(), "  john ", page)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), mockUsers, results)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestSearchUsers_EmptyQuery tests that a blank query is rejected without searching
+func (suite *UserUseCaseTestSuite) TestSearchUsers_EmptyQuery() {
+	results, err := suite.userUseCase.SearchUsers(context.Background(), "   ", Domain.Pagination{})
+
+	assert.Nil(suite.T(), results)
+	assert.ErrorIs(suite.T(), err, Domain.ErrEmptySearchQuery)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRestoreUser tests that only deletions inside the grace period can be undone
+func (suite *UserUseCaseTestSuite) TestRestoreUser() {
+	userID := primitive.NewObjectID()
+	suite.userUseCase.deletionGrace = 7 * 24 * time.Hour
+	windowStart := mock.MatchedBy(func(deletedAfter time.Time) bool {
+		return time.Since(deletedAfter) > 7*24*time.Hour-time.Minute && time.Since(deletedAfter) < 7*24*time.Hour+time.Minute
+	})
+	suite.mockRepo.On("Restore", mock.Anything, userID, windowStart).Return(nil)
+
+	err := suite.userUseCase.RestoreUser(context.Background(), userID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestRestoreUser_ShowsTasks tests that a restored account's tasks are shown again
+func (suite *UserUseCaseTestSuite) TestRestoreUser_ShowsTasks() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("Restore", mock.Anything, userID, mock.Anything).Return(nil)
+	taskRepo.On("SetOwnerDeleted", mock.Anything, userID, false).Return(nil)
+
+	err := suite.userUseCase.RestoreUser(context.Background(), userID)
+
+	assert.NoError(suite.T(), err)
+	taskRepo.AssertExpectations(suite.T())
+}
+
+// TestRestoreUser_EmailTaken tests that an account whose email was registered
+// again is reported as a conflict and its tasks stay hidden
+func (suite *UserUseCaseTestSuite) TestRestoreUser_EmailTaken() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("Restore", mock.Anything, userID, mock.Anything).Return(Domain.ErrUserAlreadyExists)
+
+	err := suite.userUseCase.RestoreUser(context.Background(), userID)
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrUserAlreadyExists)
+	taskRepo.AssertNotCalled(suite.T(), "SetOwnerDeleted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRestoreUser_GracePeriodOver tests that a purgeable account is reported as not found
+func (suite *UserUseCaseTestSuite) TestRestoreUser_GracePeriodOver() {
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("Restore", mock.Anything, userID, mock.Anything).Return(Domain.ErrUserNotFound)
+
+	err := suite.userUseCase.RestoreUser(context.Background(), userID)
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrUserNotFound)
+}
+
+// recordingExporter collects what ExportUserData hands it
+type recordingExporter struct {
+	profile *Domain.User
+	tasks   []*Domain.Task
+}
+
+func (e *recordingExporter) WriteProfile(user *Domain.User) error {
+	e.profile = user
+	return nil
+}
+
+func (e *recordingExporter) WriteTask(task *Domain.Task) error {
+	e.tasks = append(e.tasks, task)
+	return nil
+}
+
+// TestExportUserData tests that the profile is exported before the user's tasks
+func (suite *UserUseCaseTestSuite) TestExportUserData() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	user := &Domain.User{ID: primitive.NewObjectID(), Email: "test@example.com"}
+	tasks := []*Domain.Task{{Title: "First"}, {Title: "Second"}}
+	suite.mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	taskRepo.On("ForEachByUserID", mock.Anything, user.ID, mock.Anything).Return(tasks, nil)
+
+	exporter := &recordingExporter{}
+	err := suite.userUseCase.ExportUserData(context.Background(), user.ID, exporter)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user, exporter.profile)
+	assert.Equal(suite.T(), tasks, exporter.tasks)
+}
+
+// TestExportUserData_UserNotFound tests that nothing is exported for an unknown user
+func (suite *UserUseCaseTestSuite) TestExportUserData_UserNotFound() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return((*Domain.User)(nil), nil)
+
+	exporter := &recordingExporter{}
+	err := suite.userUseCase.ExportUserData(context.Background(), userID, exporter)
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrUserNotFound)
+	assert.Nil(suite.T(), exporter.profile)
+	taskRepo.AssertNotCalled(suite.T(), "ForEachByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestPurgeDeletedUsers tests that expired accounts are purged after their tasks
+func (suite *UserUseCaseTestSuite) TestPurgeDeletedUsers() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	suite.mockRepo.On("ListDeletedBefore", mock.Anything, mock.Anything).Return(ids, nil)
+	taskRepo.On("DeleteByUserIDs", mock.Anything, ids).Return(int64(5), nil)
+	suite.mockRepo.On("Purge", mock.Anything, ids).Return(int64(2), nil)
+
+	purged, err := suite.userUseCase.PurgeDeletedUsers(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), purged)
+	suite.mockRepo.AssertExpectations(suite.T())
+	taskRepo.AssertExpectations(suite.T())
+}
+
+// TestPurgeDeletedUsers_TaskDeleteFails tests that accounts are kept when their tasks could not be removed
+func (suite *UserUseCaseTestSuite) TestPurgeDeletedUsers_TaskDeleteFails() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	ids := []primitive.ObjectID{primitive.NewObjectID()}
+	suite.mockRepo.On("ListDeletedBefore", mock.Anything, mock.Anything).Return(ids, nil)
+	taskRepo.On("DeleteByUserIDs", mock.Anything, ids).Return(int64(0), errors.New("database error"))
+
+	purged, err := suite.userUseCase.PurgeDeletedUsers(context.Background())
+
+	assert.Error(suite.T(), err)
+	assert.Zero(suite.T(), purged)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Purge", mock.Anything, mock.Anything)
+}
+
+// TestPurgeDeletedUsers_NothingToPurge tests that no deletes are issued when no grace period has ended
+func (suite *UserUseCaseTestSuite) TestPurgeDeletedUsers_NothingToPurge() {
+	taskRepo := new(MockTaskRepository)
+	suite.userUseCase.taskRepo = taskRepo
+	suite.mockRepo.On("ListDeletedBefore", mock.Anything, mock.Anything).Return([]primitive.ObjectID{}, nil)
+
+	purged, err := suite.userUseCase.PurgeDeletedUsers(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), purged)
+	taskRepo.AssertNotCalled(suite.T(), "DeleteByUserIDs", mock.Anything, mock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Purge", mock.Anything, mock.Anything)
+}
+
+// TestPurgeDeletedUsers_NoTaskRepository tests that purging requires the task repository
+func (suite *UserUseCaseTestSuite) TestPurgeDeletedUsers_NoTaskRepository() {
+	_, err := suite.userUseCase.PurgeDeletedUsers(context.Background())
+
+	assert.Error(suite.T(), err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "ListDeletedBefore", mock.Anything, mock.Anything)
+}
+
 // TestGetUserByID tests fetching a user by ID successfully
 func (suite *UserUseCaseTestSuite) TestGetUserByID() {
 	userID := primitive.NewObjectID()
@@ -235,7 +525,7 @@ func (suite *UserUseCaseTestSuite) TestRegisterUser() {
 	suite.mockRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(nil, errors.New("user not found"))
 	suite.mockRepo.On("Create", mock.Anything, mockUser).Return(mockUser, nil)
 
-	result, err := suite.userUseCase.Register(context.Background(), mockUser)
+	result, err := suite.userUseCase.Register(context.Background(), mockUser, "")
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "hashedPassword", result.Password)
@@ -252,13 +542,114 @@ func (suite *UserUseCaseTestSuite) TestRegisterUser_UserAlreadyExists() {
 	// Mock repository behavior
 	suite.mockRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(mockUser, nil)
 
-	result, err := suite.userUseCase.Register(context.Background(), mockUser)
+	result, err := suite.userUseCase.Register(context.Background(), mockUser, "")
 
 	assert.Nil(suite.T(), result)
 	assert.EqualError(suite.T(), err, "user already exists")
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestRegisterUser_InviteRequired tests that invite-only registration rejects a missing code
+func (suite *UserUseCaseTestSuite) TestRegisterUser_InviteRequired() {
+	inviteRepo := new(MockInviteRepository)
+	suite.userUseCase.inviteRepo = inviteRepo
+	suite.userUseCase.inviteOnly = true
+
+	result, err := suite.userUseCase.Register(context.Background(), &Domain.User{Email: "newuser@example.com"}, "")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, Domain.ErrInviteRequired)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	inviteRepo.AssertNotCalled(suite.T(), "Redeem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRegisterUser_InvalidInvite tests that an unknown or used code is rejected
+func (suite *UserUseCaseTestSuite) TestRegisterUser_InvalidInvite() {
+	inviteRepo := new(MockInviteRepository)
+	suite.userUseCase.inviteRepo = inviteRepo
+	suite.userUseCase.inviteOnly = true
+
+	suite.mockRepo.On("GetByEmail", mock.Anything, "newuser@example.com").Return(nil, errors.New("user not found"))
+	inviteRepo.On("Redeem", mock.Anything, "used-code", "newuser@example.com").Return(Domain.ErrInvalidInvite)
+
+	result, err := suite.userUseCase.Register(context.Background(), &Domain.User{Email: "newuser@example.com"}, "used-code")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, Domain.ErrInvalidInvite)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+// TestRegisterUser_ValidInvite tests that a valid code is redeemed and the user created
+func (suite *UserUseCaseTestSuite) TestRegisterUser_ValidInvite() {
+	inviteRepo := new(MockInviteRepository)
+	suite.userUseCase.inviteRepo = inviteRepo
+	suite.userUseCase.inviteOnly = true
+
+	mockUser := &Domain.User{Email: "newuser@example.com", Password: "password123"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(nil, errors.New("user not found"))
+	inviteRepo.On("Redeem", mock.Anything, "abc123", mockUser.Email).Return(nil)
+	suite.mockRepo.On("Create", mock.Anything, mockUser).Return(mockUser, nil)
+
+	result, err := suite.userUseCase.Register(context.Background(), mockUser, "abc123")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), mockUser.Email, result.Email)
+	inviteRepo.AssertExpectations(suite.T())
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestRegisterUser_InviteReleasedOnFailure tests that the invite can be used
+// again when the user it was redeemed for is not created
+func (suite *UserUseCaseTestSuite) TestRegisterUser_InviteReleasedOnFailure() {
+	inviteRepo := new(MockInviteRepository)
+	suite.userUseCase.inviteRepo = inviteRepo
+	suite.userUseCase.inviteOnly = true
+
+	mockUser := &Domain.User{Email: "newuser@example.com", Password: "password123"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(nil, errors.New("user not found"))
+	inviteRepo.On("Redeem", mock.Anything, "abc123", mockUser.Email).Return(nil)
+	suite.mockRepo.On("Create", mock.Anything, mockUser).Return(nil, errors.New("database error"))
+	inviteRepo.On("Release", mock.Anything, "abc123", mockUser.Email).Return(nil)
+
+	result, err := suite.userUseCase.Register(context.Background(), mockUser, "abc123")
+
+	assert.Nil(suite.T(), result)
+	assert.EqualError(suite.T(), err, "database error")
+	inviteRepo.AssertExpectations(suite.T())
+}
+
+// TestRegisterUser_InviteIgnoredWhenOpen tests that open registration does not touch invites
+func (suite *UserUseCaseTestSuite) TestRegisterUser_InviteIgnoredWhenOpen() {
+	inviteRepo := new(MockInviteRepository)
+	suite.userUseCase.inviteRepo = inviteRepo
+
+	mockUser := &Domain.User{Email: "newuser@example.com", Password: "password123"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(nil, errors.New("user not found"))
+	suite.mockRepo.On("Create", mock.Anything, mockUser).Return(mockUser, nil)
+
+	_, err := suite.userUseCase.Register(context.Background(), mockUser, "whatever")
+
+	assert.NoError(suite.T(), err)
+	inviteRepo.AssertNotCalled(suite.T(), "Redeem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateInvite tests that a generated code is stored for the admin
+func (suite *UserUseCaseTestSuite) TestCreateInvite() {
+	inviteRepo := new(MockInviteRepository)
+	suite.userUseCase.inviteRepo = inviteRepo
+	suite.userUseCase.generateInvite = func() (string, error) { return "abc123", nil }
+
+	adminID := primitive.NewObjectID()
+	inviteRepo.On("Create", mock.Anything, &Domain.Invite{Code: "abc123", CreatedBy: adminID}).
+		Return(&Domain.Invite{ID: primitive.NewObjectID(), Code: "abc123", CreatedBy: adminID}, nil)
+
+	invite, err := suite.userUseCase.CreateInvite(context.Background(), adminID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "abc123", invite.Code)
+	inviteRepo.AssertExpectations(suite.T())
+}
+
 // TestLoginUser tests logging in a user successfully
 func (suite *UserUseCaseTestSuite) TestLoginUser() {
 	email := "user@example.com"
@@ -273,7 +664,7 @@ func (suite *UserUseCaseTestSuite) TestLoginUser() {
 	// Mock repository behavior
 	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
 
-	result, token, err := suite.userUseCase.Login(context.Background(), email, password)
+	result, token, err := suite.userUseCase.Login(context.Background(), email, password, false)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), email, result.Email)
@@ -295,7 +686,21 @@ func (suite *UserUseCaseTestSuite) TestLoginUser_InvalidCredentials() {
 	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
 	suite.userUseCase.comparePasswords = func(hashedPassword, plainPassword string) bool { return false }
 
-	result, token, err := suite.userUseCase.Login(context.Background(), email, password)
+	result, token, err := suite.userUseCase.Login(context.Background(), email, password, false)
+
+	assert.Nil(suite.T(), result)
+	assert.Empty(suite.T(), token)
+	assert.EqualError(suite.T(), err, "invalid credentials")
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestLoginUser_DeletedAccount tests that an account in its deletion grace
+// period, which the repository no longer finds by email, cannot log in
+func (suite *UserUseCaseTestSuite) TestLoginUser_DeletedAccount() {
+	email := "user@example.com"
+	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return((*Domain.User)(nil), nil)
+
+	result, token, err := suite.userUseCase.Login(context.Background(), email, "password", false)
 
 	assert.Nil(suite.T(), result)
 	assert.Empty(suite.T(), token)
@@ -303,6 +708,378 @@ func (suite *UserUseCaseTestSuite) TestLoginUser_InvalidCredentials() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestLoginUser_RememberMe tests that the remember me flag reaches the token generator
+func (suite *UserUseCaseTestSuite) TestLoginUser_RememberMe() {
+	email := "user@example.com"
+	mockUser := &Domain.User{ID: primitive.NewObjectID(), Email: email, Password: "hashedPassword", Role: "user"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
+
+	var requested []bool
+	suite.userUseCase.generateToken = func(userID, role string, tokenVersion int, rememberMe bool) (string, error) {
+		requested = append(requested, rememberMe)
+		return "mockToken", nil
+	}
+
+	_, _, err := suite.userUseCase.Login(context.Background(), email, "password123", true)
+	assert.NoError(suite.T(), err)
+	_, _, err = suite.userUseCase.Login(context.Background(), email, "password123", false)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), []bool{true, false}, requested)
+}
+
+// TestLoginUser_RehashesOutdatedHash tests that a low-cost hash is upgraded on login
+func (suite *UserUseCaseTestSuite) TestLoginUser_RehashesOutdatedHash() {
+	email := "user@example.com"
+	mockUser := &Domain.User{ID: primitive.NewObjectID(), Email: email, Password: "oldHash", Role: "user"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
+	suite.mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(user *Domain.User) bool {
+		return user.Password == "hashedPassword"
+	})).Return(nil).Once()
+	suite.userUseCase.needsRehash = func(hashedPassword string) bool { return hashedPassword == "oldHash" }
+
+	_, token, err := suite.userUseCase.Login(context.Background(), email, "password123", false)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "mockToken", token)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestLoginUser_RehashFailureStillLogsIn tests that a failed rehash does not block login
+func (suite *UserUseCaseTestSuite) TestLoginUser_RehashFailureStillLogsIn() {
+	email := "user@example.com"
+	mockUser := &Domain.User{ID: primitive.NewObjectID(), Email: email, Password: "oldHash", Role: "user"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
+	suite.mockRepo.On("Update", mock.Anything, mockUser).Return(errors.New("write failed")).Once()
+	suite.userUseCase.needsRehash = func(hashedPassword string) bool { return true }
+
+	_, token, err := suite.userUseCase.Login(context.Background(), email, "password123", false)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "mockToken", token)
+	assert.Equal(suite.T(), "oldHash", mockUser.Password)
+}
+
+// TestLoginUser_CurrentHashNotRehashed tests that an up-to-date hash is left alone
+func (suite *UserUseCaseTestSuite) TestLoginUser_CurrentHashNotRehashed() {
+	email := "user@example.com"
+	mockUser := &Domain.User{ID: primitive.NewObjectID(), Email: email, Password: "hashedPassword", Role: "user"}
+	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
+
+	_, _, err := suite.userUseCase.Login(context.Background(), email, "password123", false)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+// TestBulkRegister_CleanBatch tests creating a batch with no conflicts
+func (suite *UserUseCaseTestSuite) TestBulkRegister_CleanBatch() {
+	users := []*Domain.User{
+		{Email: "a@example.com", Password: "password1"},
+		{Email: "b@example.com", Password: "password2"},
+	}
+
+	suite.mockRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.mockRepo.On("CreateMany", mock.Anything, users).Return(users, nil)
+
+	results, err := suite.userUseCase.BulkRegister(context.Background(), users)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 2)
+	for _, result := range results {
+		assert.Equal(suite.T(), Domain.BulkStatusCreated, result.Status)
+		assert.Equal(suite.T(), "hashedPassword", result.User.Password)
+	}
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestBulkRegister_DuplicateEmails tests duplicates within the batch and against existing users
+func (suite *UserUseCaseTestSuite) TestBulkRegister_DuplicateEmails() {
+	users := []*Domain.User{
+		{Email: "new@example.com", Password: "password1"},
+		{Email: "new@example.com", Password: "password2"},
+		{Email: "taken@example.com", Password: "password3"},
+	}
+
+	suite.mockRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, nil).Once()
+	suite.mockRepo.On("GetByEmail", mock.Anything, "taken@example.com").Return(&Domain.User{Email: "taken@example.com"}, nil)
+	suite.mockRepo.On("CreateMany", mock.Anything, []*Domain.User{users[0]}).Return([]*Domain.User{users[0]}, nil)
+
+	results, err := suite.userUseCase.BulkRegister(context.Background(), users)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), Domain.BulkStatusCreated, results[0].Status)
+	assert.Equal(suite.T(), Domain.BulkStatusFailed, results[1].Status)
+	assert.Equal(suite.T(), "duplicate email in batch", results[1].Error)
+	assert.Equal(suite.T(), Domain.BulkStatusFailed, results[2].Status)
+	assert.Equal(suite.T(), "user already exists", results[2].Error)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestGetAllUsers_InvalidDateRange tests rejecting a range that ends before it starts
+func (suite *UserUseCaseTestSuite) TestGetAllUsers_InvalidDateRange() {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	results, err := suite.userUseCase.GetAllUsers(context.Background(), Domain.UserFilter{CreatedFrom: &from, CreatedTo: &to})
+
+	assert.Nil(suite.T(), results)
+	assert.ErrorIs(suite.T(), err, Domain.ErrInvalidDateRange)
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetAll", mock.Anything, mock.Anything)
+}
+
+// TestGetAllUsers_DayRange tests that registration days are resolved in the configured timezone rather than UTC
+func (suite *UserUseCaseTestSuite) TestGetAllUsers_DayRange() {
+	newYork, err := time.LoadLocation("America/New_York")
+	suite.Require().NoError(err)
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	filter := Domain.UserFilter{CreatedFromDay: &day, CreatedToDay: &day}
+
+	cases := map[string]struct {
+		loc      *time.Location
+		from, to time.Time
+	}{
+		"default UTC": {
+			loc:  nil,
+			from: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 15, 23, 59, 59, 999999999, time.UTC),
+		},
+		"configured timezone": {
+			loc:  newYork,
+			from: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 16, 4, 59, 59, 999999999, time.UTC),
+		},
+	}
+
+	for name, tc := range cases {
+		suite.Run(name, func() {
+			repo := new(MockUserRepository)
+			userUseCase := NewUserUseCase(repo, WithUserLocation(tc.loc))
+			repo.On("GetAll", mock.Anything, mock.MatchedBy(func(f Domain.UserFilter) bool {
+				return f.CreatedFrom != nil && f.CreatedFrom.Equal(tc.from) && f.CreatedTo != nil && f.CreatedTo.Equal(tc.to)
+			})).Return([]*Domain.User{}, nil)
+
+			_, err := userUseCase.GetAllUsers(context.Background(), filter)
+
+			assert.NoError(suite.T(), err)
+			repo.AssertExpectations(suite.T())
+		})
+	}
+}
+
+// TestChangePassword tests that changing the password clears the reset requirement
+func (suite *UserUseCaseTestSuite) TestChangePassword() {
+	userID := primitive.NewObjectID()
+	mockUser := &Domain.User{ID: userID, Password: "oldHash", MustChangePassword: true}
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(mockUser, nil)
+	suite.mockRepo.On("Update", mock.Anything, mockUser).Return(nil)
+
+	err := suite.userUseCase.ChangePassword(context.Background(), userID, "temporary", "newSecret")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "hashedPassword", mockUser.Password)
+	assert.False(suite.T(), mockUser.MustChangePassword)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestChangePassword_IncorrectPassword tests rejecting a wrong current password
+func (suite *UserUseCaseTestSuite) TestChangePassword_IncorrectPassword() {
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(&Domain.User{ID: userID, Password: "oldHash"}, nil)
+	suite.userUseCase.comparePasswords = func(hashedPassword, plainPassword string) bool { return false }
+
+	err := suite.userUseCase.ChangePassword(context.Background(), userID, "wrong", "newSecret")
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrIncorrectPassword)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+// TestResetPassword tests that an admin reset flags the user
+func (suite *UserUseCaseTestSuite) TestResetPassword() {
+	userID := primitive.NewObjectID()
+	mockUser := &Domain.User{ID: userID, Password: "oldHash"}
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(mockUser, nil)
+	suite.mockRepo.On("Update", mock.Anything, mockUser).Return(nil)
+
+	err := suite.userUseCase.ResetPassword(context.Background(), userID, "temporary")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "hashedPassword", mockUser.Password)
+	assert.True(suite.T(), mockUser.MustChangePassword)
+}
+
+// TestResetPassword_UserNotFound tests resetting the password of an unknown user
+func (suite *UserUseCaseTestSuite) TestResetPassword_UserNotFound() {
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return((*Domain.User)(nil), nil)
+
+	err := suite.userUseCase.ResetPassword(context.Background(), userID, "temporary")
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrUserNotFound)
+}
+
+// TestChangePassword_ReusedPasswordRejected tests that recent passwords cannot be reused
+func (suite *UserUseCaseTestSuite) TestChangePassword_ReusedPasswordRejected() {
+	suite.userUseCase.passwordHistory = 2
+	suite.userUseCase.comparePasswords = func(hashedPassword, plainPassword string) bool {
+		return hashedPassword == "hash:"+plainPassword
+	}
+	userID := primitive.NewObjectID()
+	mockUser := &Domain.User{ID: userID, Password: "hash:current", PasswordHistory: []string{"hash:previous", "hash:older"}}
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(mockUser, nil)
+
+	for _, reused := range []string{"current", "previous", "older"} {
+		err := suite.userUseCase.ChangePassword(context.Background(), userID, "current", reused)
+		assert.ErrorIs(suite.T(), err, Domain.ErrPasswordReused, reused)
+	}
+	assert.Equal(suite.T(), "hash:current", mockUser.Password)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+// TestChangePassword_FreshPasswordAccepted tests that a new password is accepted and the history rotates
+func (suite *UserUseCaseTestSuite) TestChangePassword_FreshPasswordAccepted() {
+	suite.userUseCase.passwordHistory = 2
+	suite.userUseCase.comparePasswords = func(hashedPassword, plainPassword string) bool {
+		return hashedPassword == "hash:"+plainPassword
+	}
+	suite.userUseCase.hashPassword = func(password string) (string, error) { return "hash:" + password, nil }
+	userID := primitive.NewObjectID()
+	mockUser := &Domain.User{ID: userID, Password: "hash:current", PasswordHistory: []string{"hash:previous", "hash:older"}}
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(mockUser, nil)
+	suite.mockRepo.On("Update", mock.Anything, mockUser).Return(nil)
+
+	err := suite.userUseCase.ChangePassword(context.Background(), userID, "current", "fresh")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "hash:fresh", mockUser.Password)
+	assert.Equal(suite.T(), []string{"hash:current", "hash:previous"}, mockUser.PasswordHistory)
+}
+
+// TestResetPassword_ReusedPasswordRejected tests that an admin reset also honours the history
+func (suite *UserUseCaseTestSuite) TestResetPassword_ReusedPasswordRejected() {
+	suite.userUseCase.passwordHistory = 1
+	suite.userUseCase.comparePasswords = func(hashedPassword, plainPassword string) bool {
+		return hashedPassword == "hash:"+plainPassword
+	}
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(&Domain.User{ID: userID, Password: "hash:current", PasswordHistory: []string{"hash:previous"}}, nil)
+
+	err := suite.userUseCase.ResetPassword(context.Background(), userID, "previous")
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrPasswordReused)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+// TestLoginUser_EmbedsTokenVersion tests that issued tokens carry the user's current token version
+func (suite *UserUseCaseTestSuite) TestLoginUser_EmbedsTokenVersion() {
+	email := "user@example.com"
+	mockUser := &Domain.User{ID: primitive.NewObjectID(), Email: email, Password: "hashedPassword", Role: "user", TokenVersion: 4}
+	suite.mockRepo.On("GetByEmail", mock.Anything, email).Return(mockUser, nil)
+
+	var issued int
+	suite.userUseCase.generateToken = func(userID, role string, tokenVersion int, rememberMe bool) (string, error) {
+		issued = tokenVersion
+		return "mockToken", nil
+	}
+
+	_, _, err := suite.userUseCase.Login(context.Background(), email, "password123", false)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, issued)
+}
+
+// TestRevokeSessions tests that revoking sessions bumps the stored token version
+func (suite *UserUseCaseTestSuite) TestRevokeSessions() {
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("IncrementTokenVersion", mock.Anything, userID).Return(nil)
+
+	err := suite.userUseCase.RevokeSessions(context.Background(), userID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestRevokeSessions_UserNotFound tests revoking the sessions of an unknown user
+func (suite *UserUseCaseTestSuite) TestRevokeSessions_UserNotFound() {
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("IncrementTokenVersion", mock.Anything, userID).Return(Domain.ErrUserNotFound)
+
+	err := suite.userUseCase.RevokeSessions(context.Background(), userID)
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrUserNotFound)
+}
+
+// TestImpersonate tests that impersonation issues a token naming the admin and writes an audit entry
+func (suite *UserUseCaseTestSuite) TestImpersonate() {
+	auditRepo := new(MockAuditRepository)
+	suite.userUseCase.auditRepo = auditRepo
+	adminID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(&Domain.User{ID: userID, Role: Domain.RoleUser, TokenVersion: 2}, nil)
+	auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *Domain.AuditEntry) bool {
+		return entry.Action == Domain.AuditUserImpersonated && entry.ActorID == adminID && entry.TargetID == userID
+	})).Return(nil).Once()
+
+	var issued []string
+	suite.userUseCase.impersonateToken = func(userID, role string, tokenVersion int, impersonatedBy string) (string, error) {
+		issued = append(issued, userID, impersonatedBy)
+		return "impersonationToken", nil
+	}
+
+	token, err := suite.userUseCase.Impersonate(context.Background(), adminID, userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "impersonationToken", token)
+	assert.Equal(suite.T(), []string{userID.Hex(), adminID.Hex()}, issued)
+	auditRepo.AssertExpectations(suite.T())
+}
+
+// TestImpersonate_UserNotFound tests that an unknown user yields no token and no audit entry
+func (suite *UserUseCaseTestSuite) TestImpersonate_UserNotFound() {
+	auditRepo := new(MockAuditRepository)
+	suite.userUseCase.auditRepo = auditRepo
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return((*Domain.User)(nil), nil)
+
+	token, err := suite.userUseCase.Impersonate(context.Background(), primitive.NewObjectID(), userID)
+
+	assert.ErrorIs(suite.T(), err, Domain.ErrUserNotFound)
+	assert.Empty(suite.T(), token)
+	auditRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+// TestImpersonate_AuditFailure tests that no token is returned when the action cannot be audited
+func (suite *UserUseCaseTestSuite) TestImpersonate_AuditFailure() {
+	auditRepo := new(MockAuditRepository)
+	suite.userUseCase.auditRepo = auditRepo
+	userID := primitive.NewObjectID()
+	suite.mockRepo.On("GetByID", mock.Anything, userID).Return(&Domain.User{ID: userID}, nil)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("write failed"))
+	suite.userUseCase.impersonateToken = func(userID, role string, tokenVersion int, impersonatedBy string) (string, error) {
+		return "impersonationToken", nil
+	}
+
+	token, err := suite.userUseCase.Impersonate(context.Background(), primitive.NewObjectID(), userID)
+
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), token)
+}
+
+// TestIssueConfirmation tests that the confirmation token is issued to the caller
+func (suite *UserUseCaseTestSuite) TestIssueConfirmation() {
+	userID := primitive.NewObjectID()
+	var issuedTo string
+	suite.userUseCase.confirmToken = func(userID string) (string, error) {
+		issuedTo = userID
+		return "confirmationToken", nil
+	}
+
+	token, err := suite.userUseCase.IssueConfirmation(context.Background(), userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "confirmationToken", token)
+	assert.Equal(suite.T(), userID.Hex(), issuedTo)
+}
+
 // Run the test suite
 func TestUserUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(UserUseCaseTestSuite))